@@ -0,0 +1,104 @@
+package log
+
+import (
+	"bytes"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// queuedWrite is an encoded entry waiting to be written to the log files, or a request
+// to be notified once the entries queued before it are written, if done is set.
+type queuedWrite struct {
+	entry zapcore.Entry
+	data  []byte
+	done  chan struct{}
+}
+
+// asyncQueue is the state of the async queue of a logger, see Options.AsyncQueueSize.
+type asyncQueue struct {
+	mu      sync.RWMutex  // held for writing to close the queue, for reading to send to it
+	stopped bool          // whether the queue is closed, see stopQueue
+	done    chan struct{} // closed once the queued entries are written and the goroutine exits
+}
+
+// startQueue starts the goroutine writing the queued entries to the log files.
+func (l *Log) startQueue(size int) {
+	l.queue = make(chan queuedWrite, size)
+	l.queueState.done = make(chan struct{})
+
+	go func() {
+		defer close(l.queueState.done)
+
+		for w := range l.queue {
+			if w.done != nil {
+				close(w.done)
+				continue
+			}
+			if err := l.writeFilesNow(w.entry, w.data); err != nil {
+				l.reportFileWriteError(err, w.data)
+			}
+		}
+	}()
+}
+
+// enqueue queues a copy of the encoded entry data, or drops it if the queue is full. The
+// entries still in flight once the queue is stopped are dropped.
+func (l *Log) enqueue(entry zapcore.Entry, data []byte) {
+	l.queueState.mu.RLock()
+	defer l.queueState.mu.RUnlock()
+
+	if l.queueState.stopped {
+		return
+	}
+
+	select {
+	case l.queue <- queuedWrite{entry: entry, data: bytes.Clone(data)}:
+	default:
+		l.stats.droppedByQueue.Add(1)
+	}
+}
+
+// drainQueue waits until the entries queued so far are written to the log files.
+func (l *Log) drainQueue() {
+	if l.queue == nil {
+		return
+	}
+
+	l.queueState.mu.RLock()
+	defer l.queueState.mu.RUnlock()
+
+	if l.queueState.stopped {
+		return // Already drained by stopQueue
+	}
+
+	done := make(chan struct{})
+	l.queue <- queuedWrite{done: done}
+	<-done
+}
+
+// stopQueue closes the queue, and waits until the entries queued so far are written and the
+// goroutine writing them exits.
+func (l *Log) stopQueue() {
+	if l.queue == nil {
+		return
+	}
+
+	l.queueState.mu.Lock()
+	if !l.queueState.stopped {
+		l.queueState.stopped = true
+		close(l.queue)
+	}
+	l.queueState.mu.Unlock()
+
+	<-l.queueState.done
+}
+
+// DroppedEntries returns the number of entries dropped because the async queue was full,
+// see Options.AsyncQueueSize. It's always 0 when the log files are written synchronously.
+func (l *Log) DroppedEntries() uint64 {
+	if l.parent != nil {
+		return l.parent.DroppedEntries()
+	}
+	return l.stats.droppedByQueue.Load()
+}
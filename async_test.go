@@ -0,0 +1,213 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// slowFile is an in-memory log file taking delay for every write, like a disk under pressure.
+type slowFile struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	delay time.Duration
+}
+
+func (f *slowFile) Write(p []byte) (int, error) {
+	time.Sleep(f.delay)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.buf.Write(p)
+}
+
+func (f *slowFile) Close() error { return nil }
+
+func (f *slowFile) lines() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return strings.Count(f.buf.String(), "\n")
+}
+
+// failingFile is a log file whose writes always fail.
+type failingFile struct{}
+
+func (failingFile) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+func (failingFile) Close() error              { return nil }
+
+func TestWriteTimeout(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	// Without a timeout, every retry is attempted
+	logger := &Log{opts: NewOptions()}
+	start := time.Now()
+	err := logger.writeToFile(failingFile{}, []byte("entry\n"))
+	asrt.ErrorContains(err, "failed to write after retries: disk full")
+	asrt.GreaterOrEqual(time.Since(start), (MaxRetries-1)*BriefDelay)
+
+	// With a timeout shorter than the delay between retries, it gives up at once
+	logger = &Log{opts: NewOptions().WithWriteTimeout(5 * time.Millisecond)}
+	start = time.Now()
+	err = logger.writeToFile(failingFile{}, []byte("entry\n"))
+	asrt.ErrorContains(err, "failed to write within 5ms: disk full")
+	asrt.Less(time.Since(start), BriefDelay)
+}
+
+func TestAsyncQueue_SlowWrites(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_async_slow"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		AsyncQueueSize(4).
+		ConsoleOutput(false).
+		Build()
+
+	// Replace the main log file with one taking 20ms per write
+	file := &slowFile{delay: 20 * time.Millisecond}
+	logger.mu.Lock()
+	logger.file = file
+	logger.currDate = time.Now().Format(time.DateOnly)
+	logger.mu.Unlock()
+
+	// 20 synchronous writes would take 400ms, the caller must not wait for them
+	start := time.Now()
+	for range 20 {
+		logger.Info("queued entry")
+	}
+	asrt.Less(time.Since(start), 100*time.Millisecond)
+
+	// Entries that didn't fit in the queue are dropped and counted, the others written by Sync
+	require.NoError(t, logger.Sync())
+	asrt.Positive(logger.DroppedEntries())
+	asrt.Equal(20, file.lines()+int(logger.DroppedEntries()))
+}
+
+func TestAsyncQueue_Files(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_async_files"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		DisableSplitError(false).
+		AsyncQueueSize(100).
+		ConsoleOutput(false).
+		Build()
+
+	for range 50 {
+		logger.Info("main entry")
+	}
+	logger.Error("error entry")
+	require.NoError(t, logger.Sync())
+
+	asrt.Zero(logger.DroppedEntries())
+	asrt.Len(readJSONEntries(t, logger, testDir), 51)
+
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, true)))
+	require.NoError(t, err)
+	asrt.Contains(string(content), "error entry")
+
+	// The logger keeps working after Sync
+	logger.Info("after sync")
+	require.NoError(t, logger.Sync())
+	asrt.Len(readJSONEntries(t, logger, testDir), 52)
+}
+
+func TestAsyncQueue_Close(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_async_close"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		AsyncQueueSize(100).
+		ConsoleOutput(false).
+		Isolated(true).
+		Build()
+
+	for range 50 {
+		logger.Info("queued entry")
+	}
+	require.NoError(t, logger.Close())
+
+	// The queued entries are written, and the goroutine writing them exits
+	select {
+	case <-logger.queueState.done:
+	case <-time.After(time.Second):
+		t.Fatal("the queue goroutine is still running after Close")
+	}
+	asrt.Len(readJSONEntries(t, logger, testDir), 50)
+
+	// Entries still in flight, and syncs, don't send to the closed queue
+	asrt.NotPanics(func() {
+		logger.enqueue(zapcore.Entry{}, []byte("in flight\n"))
+		logger.Info("after close")
+		asrt.NoError(logger.Sync())
+		asrt.NoError(logger.Close())
+	})
+	asrt.Len(readJSONEntries(t, logger, testDir), 50)
+}
+
+func TestWriteRetries(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	// Zero retries: a single attempt, without delay
+	logger := &Log{opts: NewOptions().WithWriteRetries(0, time.Second)}
+	file := &flakyFile{failures: 100}
+	start := time.Now()
+	asrt.ErrorContains(logger.writeToFile(file, []byte("entry\n")), "failed to write after retries")
+	asrt.Equal(99, file.failures)
+	asrt.Less(time.Since(start), time.Second)
+
+	// Custom retries and delay
+	logger = &Log{opts: NewOptions().WithWriteRetries(4, 15*time.Millisecond)}
+	file = &flakyFile{failures: 100}
+	start = time.Now()
+	asrt.Error(logger.writeToFile(file, []byte("entry\n")))
+	asrt.Equal(95, file.failures)
+	asrt.GreaterOrEqual(time.Since(start), 4*15*time.Millisecond)
+
+	// A write succeeding on the last retry
+	file = &flakyFile{failures: 4}
+	require.NoError(t, logger.writeToFile(file, []byte("entry\n")))
+	asrt.Equal("entry\n", file.String())
+
+	// The defaults match MaxRetries and BriefDelay
+	asrt.Equal(MaxRetries-1, NewOptions().WriteMaxRetries)
+	asrt.Equal(BriefDelay, NewOptions().WriteRetryDelay)
+}
+
+func Test_Options_Validate_WriteSettings(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	asrt.NoError(NewOptions().WithWriteTimeout(time.Second).WithAsyncQueueSize(1024).Validate())
+	asrt.ErrorContains(NewOptions().WithWriteTimeout(-time.Second).Validate(), "invalid write timeout: -1s")
+	asrt.ErrorContains(NewOptions().WithAsyncQueueSize(-1).Validate(), "invalid async queue size: -1")
+	asrt.ErrorContains(NewOptions().WithWriteRetries(-1, 0).Validate(), "invalid write max retries: -1")
+	asrt.ErrorContains(NewOptions().WithWriteRetries(0, -time.Millisecond).Validate(),
+		"invalid write retry delay: -1ms")
+}
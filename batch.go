@@ -0,0 +1,114 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultBatchSamples is the number of items, and of failed items, kept as examples in the
+// summary of a BatchLogger.
+const DefaultBatchSamples = 5
+
+// BatchLogger counts the items of a bulk operation, e.g. importing rows, and logs a single
+// summary entry on Done instead of an entry per item. It is safe for concurrent use.
+type BatchLogger struct {
+	log   *Log
+	msg   string
+	start time.Time
+
+	mu           sync.Mutex
+	total        int
+	errors       int
+	samples      []map[string]any // first items added
+	errorSamples []map[string]any // first failed items added
+	done         bool
+}
+
+// Batch returns a BatchLogger logging msg as the summary of a bulk operation.
+//
+// Example Usage:
+//
+//	batch := logger.Batch("Imported rows")
+//	for _, row := range rows {
+//		if err := importRow(row); err != nil {
+//			batch.AddError(err, "row", row.ID)
+//			continue
+//		}
+//		batch.Add("row", row.ID)
+//	}
+//	batch.Done()
+func (l *Log) Batch(msg string) *BatchLogger {
+	return &BatchLogger{log: l, msg: msg, start: time.Now()}
+}
+
+// Add counts a successful item. The key-value pairs describe it and are kept as an example
+// in the summary for the first DefaultBatchSamples items.
+func (b *BatchLogger) Add(keysAndValues ...any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.total++
+	if len(b.samples) < DefaultBatchSamples {
+		b.samples = append(b.samples, batchSample(keysAndValues))
+	}
+}
+
+// AddError counts a failed item. The error and the key-value pairs describing the item are
+// kept as an example in the summary for the first DefaultBatchSamples failed items.
+func (b *BatchLogger) AddError(err error, keysAndValues ...any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.total++
+	b.errors++
+	if len(b.errorSamples) < DefaultBatchSamples {
+		sample := batchSample(keysAndValues)
+		if err != nil {
+			sample["error"] = err.Error()
+		}
+		b.errorSamples = append(b.errorSamples, sample)
+	}
+}
+
+// Done logs the summary with the "total" and "errors" counts, the "duration" since Batch
+// and the "samples" and "error_samples" examples, if any. It is logged at info level, or at
+// warn level if any item failed. Only the first call logs.
+func (b *BatchLogger) Done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.done {
+		return
+	}
+	b.done = true
+
+	keysAndValues := []any{
+		"total", b.total,
+		"errors", b.errors,
+		"duration", time.Since(b.start),
+	}
+	if len(b.samples) > 0 {
+		keysAndValues = append(keysAndValues, "samples", b.samples)
+	}
+	if len(b.errorSamples) > 0 {
+		keysAndValues = append(keysAndValues, "error_samples", b.errorSamples)
+	}
+
+	if b.errors > 0 {
+		b.log.sugar.Warnw(b.msg, keysAndValues...)
+		return
+	}
+	b.log.sugar.Infow(b.msg, keysAndValues...)
+}
+
+// batchSample returns the key-value pairs of an item as a map. Non-string keys and a
+// dangling key are ignored.
+func batchSample(keysAndValues []any) map[string]any {
+	sample := make(map[string]any, len(keysAndValues)/2+1)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if key, ok := keysAndValues[i].(string); ok {
+			sample[key] = keysAndValues[i+1]
+		}
+	}
+	return sample
+}
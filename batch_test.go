@@ -0,0 +1,83 @@
+package log
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLog_Batch(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix(""))
+
+	batch := logger.Batch("Imported rows")
+	var wg sync.WaitGroup
+	for i := range 1000 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%333 == 332 {
+				batch.AddError(errors.New("duplicate key"), "row", i)
+				return
+			}
+			batch.Add("row", i)
+		}()
+	}
+	wg.Wait()
+	asrt.Empty(recorded.All(), "nothing is logged per item")
+
+	batch.Done()
+	batch.Done()
+
+	entries := recorded.TakeAll()
+	require.Len(t, entries, 1)
+	asrt.Equal("Imported rows", entries[0].Message)
+	asrt.Equal(zapcore.WarnLevel, entries[0].Level)
+	asrt.Contains(entries[0].Caller.File, "batch_test.go")
+
+	fields := entries[0].ContextMap()
+	asrt.Equal(int64(1000), fields["total"])
+	asrt.Equal(int64(3), fields["errors"])
+	asrt.Contains(fields, "duration")
+
+	samples, ok := fields["samples"].([]map[string]any)
+	require.True(t, ok)
+	asrt.Len(samples, DefaultBatchSamples)
+	asrt.Contains(samples[0], "row")
+
+	errorSamples, ok := fields["error_samples"].([]map[string]any)
+	require.True(t, ok)
+	require.Len(t, errorSamples, 3)
+	for _, sample := range errorSamples {
+		asrt.Equal("duplicate key", sample["error"])
+		asrt.Contains([]any{332, 665, 998}, sample["row"])
+	}
+}
+
+func TestLog_Batch_NoErrors(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix(""))
+
+	batch := logger.Batch("Nothing to import")
+	batch.Done()
+
+	entries := recorded.TakeAll()
+	require.Len(t, entries, 1)
+	asrt.Equal(zapcore.InfoLevel, entries[0].Level)
+	fields := entries[0].ContextMap()
+	asrt.Equal(int64(0), fields["total"])
+	asrt.Equal(int64(0), fields["errors"])
+	asrt.NotContains(fields, "samples")
+	asrt.NotContains(fields, "error_samples")
+}
@@ -2,7 +2,12 @@ package log
 
 import (
 	"os"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/buffer"
 )
 
 // BenchmarkLogPerformance tests the performance of various logging operations
@@ -173,3 +178,306 @@ func BenchmarkOptionsPerformance(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkTypedFields compares loosely-typed Infow with strongly-typed InfoF
+func BenchmarkTypedFields(b *testing.B) {
+	tempDir := "/tmp/benchmark_logs_typed_fields"
+	defer os.RemoveAll(tempDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(tempDir).
+		WithConsoleOutput(false))
+	defer logger.Sync()
+
+	b.Run("Infow", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.Infow("Request handled", "path", "/api/users", "status", 200, "attempt", i)
+		}
+	})
+
+	b.Run("InfoF", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.InfoF("Request handled", String("path", "/api/users"), Int("status", 200), Int("attempt", i))
+		}
+	})
+}
+
+// BenchmarkZeroSugar compares the sugared methods with the non-sugared Msg methods
+func BenchmarkZeroSugar(b *testing.B) {
+	tempDir := "/tmp/benchmark_logs_zero_sugar"
+	defer os.RemoveAll(tempDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(tempDir).
+		WithConsoleOutput(false))
+	defer logger.Sync()
+
+	b.Run("Info", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.Info("Simple info message")
+		}
+	})
+
+	b.Run("InfoMsg", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.InfoMsg("Simple info message")
+		}
+	})
+
+	b.Run("Infow", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.Infow("Info message with fields", "iteration", i, "type", "benchmark")
+		}
+	})
+
+	b.Run("InfoMsgWithFields", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.InfoMsg("Info message with fields", Int("iteration", i), String("type", "benchmark"))
+		}
+	})
+}
+
+// BenchmarkSugarCache compares creating a sugared logger per call with the cached one
+func BenchmarkSugarCache(b *testing.B) {
+	tempDir := "/tmp/benchmark_logs_sugar_cache"
+	defer os.RemoveAll(tempDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(tempDir).
+		WithConsoleOutput(false))
+	defer logger.Sync()
+
+	b.Run("SugarPerCall", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.log.Sugar().Info("Simple info message")
+		}
+	})
+
+	b.Run("CachedSugar", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.Info("Simple info message")
+		}
+	})
+
+	b.Run("GlobalCachedSugar", func(b *testing.B) {
+		ReplaceLogger(logger)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			Info("Simple info message")
+		}
+	})
+}
+
+// BenchmarkPrefix compares prepending the prefix through a temporary buffer (two copies of
+// the entry), copying the entry into a second pooled buffer, and moving it in place within
+// the encoder buffer as prependPrefix does, which needs no pool
+func BenchmarkPrefix(b *testing.B) {
+	entry := []byte(strings.Repeat("2025-07-20 10:00:00.000\tinfo\tmain.go:42\tRequest handled\n", 4))
+	prefix := "BENCH_"
+	pool := buffer.NewPool()
+	scratch := sync.Pool{New: func() any { return &buffer.Buffer{} }}
+
+	b.Run("TwoPass", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			buf := pool.Get()
+			_, _ = buf.Write(entry)
+
+			tempBuf, _ := scratch.Get().(*buffer.Buffer)
+			tempBuf.Reset()
+			tempBuf.AppendString(prefix)
+			_, _ = tempBuf.Write(buf.Bytes())
+			buf.Reset()
+			_, _ = buf.Write(tempBuf.Bytes())
+			scratch.Put(tempBuf)
+
+			buf.Free()
+		}
+		b.ReportMetric(float64(2*len(entry)+len(prefix)), "bytes-copied/op")
+	})
+
+	b.Run("Pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			buf := pool.Get()
+			_, _ = buf.Write(entry)
+
+			prefixed := pool.Get()
+			prefixed.AppendString(prefix)
+			_, _ = prefixed.Write(buf.Bytes())
+			buf.Free()
+
+			prefixed.Free()
+		}
+		b.ReportMetric(float64(len(entry)+len(prefix)), "bytes-copied/op")
+	})
+
+	b.Run("InPlace", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			buf := pool.Get()
+			_, _ = buf.Write(entry)
+
+			buf = prependPrefix(buf, prefix)
+			buf.Free()
+		}
+		b.ReportMetric(float64(len(entry)+len(prefix)), "bytes-copied/op")
+	})
+}
+
+// BenchmarkCheck compares Check with the sugared and non-sugared methods, at an enabled
+// and a disabled level
+func BenchmarkCheck(b *testing.B) {
+	tempDir := "/tmp/benchmark_logs_check"
+	defer os.RemoveAll(tempDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(tempDir).
+		WithConsoleOutput(false))
+	defer logger.Sync()
+
+	b.Run("DisabledDebugw", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.Debugw("Cache miss", "key", "user:42", "size", i)
+		}
+	})
+
+	b.Run("DisabledDebugMsg", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			logger.DebugMsg("Cache miss", String("key", "user:42"), Int("size", i))
+		}
+	})
+
+	b.Run("DisabledCheck", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if ce := logger.Check("debug", "Cache miss"); ce != nil {
+				ce.Write(String("key", "user:42"), Int("size", i))
+			}
+		}
+	})
+
+	b.Run("EnabledCheck", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if ce := logger.Check("info", "Cache miss"); ce != nil {
+				ce.Write(String("key", "user:42"), Int("size", i))
+			}
+		}
+	})
+}
+
+// discardFile is a log file dropping the entries, to keep file I/O out of benchmarks.
+type discardFile struct{}
+
+func (discardFile) Write(p []byte) (int, error) { return len(p), nil }
+func (discardFile) Close() error                { return nil }
+
+// newDiscardLogger returns a logger created by NewLog with opts, without console output,
+// whose log file is a discardFile, so only the logging path itself is measured.
+func newDiscardLogger(tb testing.TB, opts *Options) *Log {
+	tb.Helper()
+
+	logger := NewLog(opts.WithDirectory(tb.TempDir()).WithConsoleOutput(false).WithIsolated(true))
+	logger.mu.Lock()
+	file := logger.file
+	logger.file = discardFile{}
+	logger.mu.Unlock()
+
+	// Drop the reference NewLog took on the shared file, so the registry doesn't keep it open
+	if err := releaseLogFile(file); err != nil {
+		tb.Fatalf("release log file: %v", err)
+	}
+	return logger
+}
+
+// BenchmarkDiscard measures the logging methods without file I/O, with and without
+// sampling and the prefix
+func BenchmarkDiscard(b *testing.B) {
+	loggers := []struct {
+		name string
+		opts *Options
+	}{
+		{"Default", NewOptions()},
+		{"Sampled", NewOptions().WithSampling(true, 100, 100)},
+		{"NoPrefix", NewOptions().WithPrefix("")},
+	}
+
+	for _, l := range loggers {
+		logger := newDiscardLogger(b, l.opts)
+
+		b.Run(l.name+"/Info", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.Info("Request handled")
+			}
+		})
+
+		b.Run(l.name+"/Infow", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.Infow("Request handled", "path", "/api/users", "status", 200, "attempt", i)
+			}
+		})
+
+		b.Run(l.name+"/InfoF", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.InfoF("Request handled", String("path", "/api/users"), Int("status", 200), Int("attempt", i))
+			}
+		})
+	}
+}
+
+// Allocation budgets of the zero-sugar methods, guarded by TestAllocsPerRun. Raising one
+// must be a deliberate decision, backed by BenchmarkDiscard.
+const (
+	allocBudgetInfoMsg  = 7 // InfoMsg with two typed fields, written to the log file
+	allocBudgetDisabled = 1 // DebugMsg at a disabled level, for its variadic fields
+)
+
+// TestAllocsPerRun guards the allocations of the zero-sugar methods against regressions.
+// Not parallel: the allocations of concurrent tests would be counted as well.
+func TestAllocsPerRun(t *testing.T) {
+	if raceEnabled {
+		t.Skip("The race detector adds allocations")
+	}
+
+	logger := newDiscardLogger(t, NewOptions())
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		logger.InfoMsg("Request handled", String("path", "/api/users"), Int("status", 200))
+	})
+	assert.LessOrEqual(t, allocs, float64(allocBudgetInfoMsg), "InfoMsg allocations")
+
+	allocs = testing.AllocsPerRun(1000, func() {
+		logger.DebugMsg("Cache miss", String("key", "user:42"))
+	})
+	assert.LessOrEqual(t, allocs, float64(allocBudgetDisabled), "disabled DebugMsg allocations")
+}
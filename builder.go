@@ -1,5 +1,13 @@
 package log
 
+import (
+	"io"
+	"os"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
 // Builder provides a fluent interface for configuring and creating Log instances
 // It wraps the existing Options struct and provides chainable methods for configuration
 type Builder struct{ opts *Options }
@@ -17,7 +25,47 @@ func (b *Builder) Level(level string) *Builder {
 	return b
 }
 
-// Format sets the log format (console or json)
+// FilenamePattern sets the path of the log files relative to the directory
+// Supported tokens: {name}, {date}, {level}, {pid}, {host}, e.g. "{name}/{date}/app-{level}.log"
+// Returns the Builder for method chaining
+func (b *Builder) FilenamePattern(pattern string) *Builder {
+	b.opts.WithFilenamePattern(pattern) // Use existing method
+	return b
+}
+
+// MaxFilenameLength sets the maximum length in bytes of the sanitized filename (up to 255)
+// Longer filenames are truncated at a rune boundary
+// Returns the Builder for method chaining
+func (b *Builder) MaxFilenameLength(length int) *Builder {
+	b.opts.WithMaxFilenameLength(length) // Use existing method
+	return b
+}
+
+// RelativeTo sets the anchor of a relative directory: "cwd" (working directory),
+// "exe" (directory of the executable) or "config" (directory of the configuration file)
+// Returns the Builder for method chaining
+func (b *Builder) RelativeTo(anchor string) *Builder {
+	b.opts.WithRelativeTo(anchor) // Use existing method
+	return b
+}
+
+// TimeEncoder sets the encoding of the timestamps
+// Valid values: "layout" (TimeLayout), "epoch", "epochmillis", "rfc3339", "rfc3339nano", "iso8601"
+// Returns the Builder for method chaining
+func (b *Builder) TimeEncoder(encoder string) *Builder {
+	b.opts.WithTimeEncoder(encoder) // Use existing method
+	return b
+}
+
+// DurationEncoder sets the encoding of the durations of fields
+// Valid values: "string", "seconds", "millis", "nanos"
+// Returns the Builder for method chaining
+func (b *Builder) DurationEncoder(encoder string) *Builder {
+	b.opts.WithDurationEncoder(encoder) // Use existing method
+	return b
+}
+
+// Format sets the log format (console, json or csv)
 // Returns the Builder for method chaining
 func (b *Builder) Format(format string) *Builder {
 	b.opts.WithFormat(format) // Use existing method
@@ -52,6 +100,54 @@ func (b *Builder) TimeLayout(layout string) *Builder {
 	return b
 }
 
+// ConsoleFormat sets the format of the console output, overriding Format
+// Valid values: "console", "json", "csv", or empty to use Format
+// Returns the Builder for method chaining
+func (b *Builder) ConsoleFormat(format string) *Builder {
+	b.opts.WithConsoleFormat(format) // Use existing method
+	return b
+}
+
+// FileFormat sets the format of the log files, overriding Format
+// Valid values: "console", "json", "csv", or empty to use Format
+// Returns the Builder for method chaining
+func (b *Builder) FileFormat(format string) *Builder {
+	b.opts.WithFileFormat(format) // Use existing method
+	return b
+}
+
+// ConsoleLevel sets the minimum level of the console output, overriding Level
+// Valid values: "debug", "info", "warn", "error", "dpanic", "panic", "fatal", or empty to use Level
+// Returns the Builder for method chaining
+func (b *Builder) ConsoleLevel(level string) *Builder {
+	b.opts.WithConsoleLevel(level) // Use existing method
+	return b
+}
+
+// FileLevel sets the minimum level of the log files, overriding Level
+// Valid values: "debug", "info", "warn", "error", "dpanic", "panic", "fatal", or empty to use Level
+// Returns the Builder for method chaining
+func (b *Builder) FileLevel(level string) *Builder {
+	b.opts.WithFileLevel(level) // Use existing method
+	return b
+}
+
+// PrettyJSON sets whether to indent the JSON console output over multiple lines
+// Meant for development; the log files are never indented
+// Returns the Builder for method chaining
+func (b *Builder) PrettyJSON(pretty bool) *Builder {
+	b.opts.WithPrettyJSON(pretty) // Use existing method
+	return b
+}
+
+// FieldOrder sets the keys of the fields rendered first in the console format, in this order
+// e.g. FieldOrder("request_id", "user_id") for lines that are easy to scan
+// Returns the Builder for method chaining
+func (b *Builder) FieldOrder(keys ...string) *Builder {
+	b.opts.WithFieldOrder(keys...) // Use existing method
+	return b
+}
+
 // DisableCaller sets whether to disable caller information
 // Returns the Builder for method chaining
 func (b *Builder) DisableCaller(disable bool) *Builder {
@@ -66,6 +162,77 @@ func (b *Builder) DisableStacktrace(disable bool) *Builder {
 	return b
 }
 
+// StacktraceLevel sets the minimum level at which a stacktrace is attached to entries
+// Valid levels: "debug", "info", "warn", "error", "dpanic", "panic", "fatal"
+// Returns the Builder for method chaining
+func (b *Builder) StacktraceLevel(level string) *Builder {
+	b.opts.WithStacktraceLevel(level) // Use existing method
+	return b
+}
+
+// IncludeHostPID sets whether to add the host name and process ID to every entry
+// They are added as the "host" and "pid" fields
+// Returns the Builder for method chaining
+func (b *Builder) IncludeHostPID(include bool) *Builder {
+	b.opts.WithIncludeHostPID(include) // Use existing method
+	return b
+}
+
+// IncludeGoroutineID sets whether to add the ID of the logging goroutine to every entry
+// It is added as the "goroutine" field, for debugging concurrency issues
+// Returns the Builder for method chaining
+func (b *Builder) IncludeGoroutineID(include bool) *Builder {
+	b.opts.WithIncludeGoroutineID(include) // Use existing method
+	return b
+}
+
+// CallerWithFunction sets whether to add the name of the calling function to every entry
+// It is added as the "func" field, next to the file:line caller, and honors ExtraCallerSkip
+// Returns the Builder for method chaining
+func (b *Builder) CallerWithFunction(include bool) *Builder {
+	b.opts.WithCallerWithFunction(include) // Use existing method
+	return b
+}
+
+// Fields sets the fields added to every entry of the logger
+// e.g. Fields(map[string]any{"service": "checkout", "version": version})
+// Returns the Builder for method chaining
+func (b *Builder) Fields(fields map[string]any) *Builder {
+	b.opts.WithFields(fields) // Use existing method
+	return b
+}
+
+// MaxFieldLength sets the maximum length in bytes of field values, 0 meaning no limit
+// Longer values are truncated and marked with TruncatedMarker
+// Returns the Builder for method chaining
+func (b *Builder) MaxFieldLength(length int) *Builder {
+	b.opts.WithMaxFieldLength(length) // Use existing method
+	return b
+}
+
+// FlattenNested sets whether to flatten the fields holding maps, structs and objects into
+// one field per leaf value, e.g. "user.id" instead of "user": {"id": ...}
+// Returns the Builder for method chaining
+func (b *Builder) FlattenNested(flatten bool) *Builder {
+	b.opts.WithFlattenNested(flatten) // Use existing method
+	return b
+}
+
+// FlattenSeparator sets the separator joining the keys of flattened fields, "." if empty
+// Returns the Builder for method chaining
+func (b *Builder) FlattenSeparator(separator string) *Builder {
+	b.opts.WithFlattenSeparator(separator) // Use existing method
+	return b
+}
+
+// Isolated sets whether the logger leaves the default logger and the standard library logger
+// untouched, so libraries can embed a logger without affecting the host application
+// Returns the Builder for method chaining
+func (b *Builder) Isolated(isolated bool) *Builder {
+	b.opts.WithIsolated(isolated) // Use existing method
+	return b
+}
+
 // DisableSplitError sets whether to disable separate error log files
 // Returns the Builder for method chaining
 func (b *Builder) DisableSplitError(disable bool) *Builder {
@@ -73,6 +240,30 @@ func (b *Builder) DisableSplitError(disable bool) *Builder {
 	return b
 }
 
+// ErrorsOnlyInErrorFile sets whether entries at error level and above are written to the
+// error log file only, instead of to both the main and the error log files
+// Returns the Builder for method chaining
+func (b *Builder) ErrorsOnlyInErrorFile(only bool) *Builder {
+	b.opts.WithErrorsOnlyInErrorFile(only) // Use existing method
+	return b
+}
+
+// SplitFatal sets whether to also write Panic and Fatal entries to a separate fatal log file
+// Returns the Builder for method chaining
+func (b *Builder) SplitFatal(split bool) *Builder {
+	b.opts.WithSplitFatal(split) // Use existing method
+	return b
+}
+
+// ExtraCallerSkip sets the number of extra stack frames skipped when reporting the caller, on
+// top of the Log method itself, which is always skipped
+// Wrappers around the logger add one per extra frame, e.g. 1 for a single helper function
+// Returns the Builder for method chaining
+func (b *Builder) ExtraCallerSkip(skip int) *Builder {
+	b.opts.WithExtraCallerSkip(skip) // Use existing method
+	return b
+}
+
 // MaxSize sets the maximum size of log files in megabytes before rotation
 // Returns the Builder for method chaining
 func (b *Builder) MaxSize(size int) *Builder {
@@ -94,6 +285,54 @@ func (b *Builder) Compress(compress bool) *Builder {
 	return b
 }
 
+// CompressAlgorithm sets the algorithm used to compress rotated log files (gzip, zstd or none)
+// It only takes effect when compression is enabled
+// Returns the Builder for method chaining
+func (b *Builder) CompressAlgorithm(algorithm string) *Builder {
+	b.opts.WithCompressAlgorithm(algorithm) // Use existing method
+	return b
+}
+
+// CompressActive sets whether to gzip the active log files as they are written
+// The files can't be tailed while active; call Sync to finalize them
+// Returns the Builder for method chaining
+func (b *Builder) CompressActive(compress bool) *Builder {
+	b.opts.WithCompressActive(compress) // Use existing method
+	return b
+}
+
+// DisableRotation sets whether to write plain append-only files without size-based rotation
+// Use it when the files are rotated externally, e.g. by logrotate with copytruncate
+// Returns the Builder for method chaining
+func (b *Builder) DisableRotation(disable bool) *Builder {
+	b.opts.WithDisableRotation(disable) // Use existing method
+	return b
+}
+
+// FileHeader sets whether to begin each new log file with a JSON header line
+// describing it (creation time, format and the logger fields)
+// Returns the Builder for method chaining
+func (b *Builder) FileHeader(header bool) *Builder {
+	b.opts.WithFileHeader(header) // Use existing method
+	return b
+}
+
+// DirMode sets the permissions of the directories created for the log files
+// e.g. 0o700 to keep them private
+// Returns the Builder for method chaining
+func (b *Builder) DirMode(mode os.FileMode) *Builder {
+	b.opts.WithDirMode(mode) // Use existing method
+	return b
+}
+
+// FileMode sets the permissions of the log files, e.g. 0o600 to keep them private
+// A zero mode keeps the default of the log file type
+// Returns the Builder for method chaining
+func (b *Builder) FileMode(mode os.FileMode) *Builder {
+	b.opts.WithFileMode(mode) // Use existing method
+	return b
+}
+
 // Sampling configures log sampling settings
 // Returns the Builder for method chaining
 func (b *Builder) Sampling(enable bool, initial, thereafter int) *Builder {
@@ -101,6 +340,30 @@ func (b *Builder) Sampling(enable bool, initial, thereafter int) *Builder {
 	return b
 }
 
+// SampleTick sets the window of the sampling counters, 1s by default
+// A longer window suits bursty workloads, a shorter one lets logs resume sooner
+// Returns the Builder for method chaining
+func (b *Builder) SampleTick(tick time.Duration) *Builder {
+	b.opts.WithSampleTick(tick) // Use existing method
+	return b
+}
+
+// SamplingHook sets the function called with every sampling decision when sampling is enabled
+// This allows counting dropped logs, e.g. to export a logs_dropped_total metric
+// Returns the Builder for method chaining
+func (b *Builder) SamplingHook(hook func(entry zapcore.Entry, dec zapcore.SamplingDecision)) *Builder {
+	b.opts.WithSamplingHook(hook) // Use existing method
+	return b
+}
+
+// SampleKeyFunc sets the function returning the key grouping entries for sampling
+// This allows sampling per field value, e.g. per endpoint, instead of per message
+// Returns the Builder for method chaining
+func (b *Builder) SampleKeyFunc(keyFunc func(entry zapcore.Entry, fields []zapcore.Field) string) *Builder {
+	b.opts.WithSampleKeyFunc(keyFunc) // Use existing method
+	return b
+}
+
 // ConsoleOutput sets whether to output logs to console
 // When disabled, logs are only written to files
 // Returns the Builder for method chaining
@@ -109,6 +372,102 @@ func (b *Builder) ConsoleOutput(enable bool) *Builder {
 	return b
 }
 
+// ErrorToStderr sets whether to write entries at Error level and above to stderr
+// These entries are written to stderr instead of stdout when ConsoleOutput is enabled
+// Returns the Builder for method chaining
+func (b *Builder) ErrorToStderr(enable bool) *Builder {
+	b.opts.WithErrorToStderr(enable) // Use existing method
+	return b
+}
+
+// Syslog configures sending logs to a local or remote syslog daemon
+// Set Only to send logs to syslog instead of log files
+// Returns the Builder for method chaining
+func (b *Builder) Syslog(syslog SyslogOptions) *Builder {
+	b.opts.WithSyslog(syslog) // Use existing method
+	return b
+}
+
+// Remote configures streaming logs to a TCP or UDP collector (e.g. Logstash or Fluentd)
+// Logs are still written locally; entries are dropped while the collector is unreachable
+// Returns the Builder for method chaining
+func (b *Builder) Remote(protocol, addr string) *Builder {
+	b.opts.WithRemote(protocol, addr) // Use existing method
+	return b
+}
+
+// WriteTimeout sets the maximum time spent retrying a failed write to a log file
+// 0 means the retries are not bounded in time
+// Returns the Builder for method chaining
+func (b *Builder) WriteTimeout(timeout time.Duration) *Builder {
+	b.opts.WithWriteTimeout(timeout) // Use existing method
+	return b
+}
+
+// WriteRetries sets the number of times a failed write to a log file is retried (0 for a single
+// attempt) and the delay between attempts
+// Returns the Builder for method chaining
+func (b *Builder) WriteRetries(maxRetries int, delay time.Duration) *Builder {
+	b.opts.WithWriteRetries(maxRetries, delay) // Use existing method
+	return b
+}
+
+// AsyncQueueSize sets the size of the queue of entries written to the log files in the background
+// Log calls never wait for the disk; entries are dropped while the queue is full
+// Returns the Builder for method chaining
+func (b *Builder) AsyncQueueSize(size int) *Builder {
+	b.opts.WithAsyncQueueSize(size) // Use existing method
+	return b
+}
+
+// SyncOnError sets whether to fsync the log files after writing an entry at error level or above
+// This makes error entries durable right away, at the cost of some latency on errors
+// Returns the Builder for method chaining
+func (b *Builder) SyncOnError(sync bool) *Builder {
+	b.opts.WithSyncOnError(sync) // Use existing method
+	return b
+}
+
+// ExtraWriters sets the writers receiving a copy of every encoded entry,
+// e.g. an in-memory buffer of the last lines
+// Returns the Builder for method chaining
+func (b *Builder) ExtraWriters(writers ...io.Writer) *Builder {
+	b.opts.WithExtraWriters(writers...) // Use existing method
+	return b
+}
+
+// OnWriteError sets the callback invoked when an entry ultimately fails to be written
+// This allows alerting or buffering to an alternate location instead of printing to stderr
+// Returns the Builder for method chaining
+func (b *Builder) OnWriteError(fn func(err error, entry []byte)) *Builder {
+	b.opts.WithOnWriteError(fn) // Use existing method
+	return b
+}
+
+// InternalErrorWriter sets the writer receiving the diagnostics of the logger itself
+// e.g. invalid options or failed writes, instead of stderr
+// Returns the Builder for method chaining
+func (b *Builder) InternalErrorWriter(w io.Writer) *Builder {
+	b.opts.WithInternalErrorWriter(w) // Use existing method
+	return b
+}
+
+// DeadletterFile sets the path of the file where entries that failed to be written to a log file are appended
+// This keeps them when the log files can't be written, in addition to the OnWriteError callback
+// Returns the Builder for method chaining
+func (b *Builder) DeadletterFile(path string) *Builder {
+	b.opts.WithDeadletterFile(path) // Use existing method
+	return b
+}
+
+// FatalHook sets the function called before the process exits on a fatal entry
+// This allows closing databases or flushing other loggers, since Fatal skips deferred functions
+// Returns the Builder for method chaining
+func (b *Builder) FatalHook(hook func()) *Builder {
+	b.opts.WithFatalHook(hook) // Use existing method
+	return b
+}
+
 // Development applies the development preset configuration
 // This configures the logger for development environment with debug level,
 // console output, caller info enabled, and fast flush
@@ -136,8 +495,21 @@ func (b *Builder) Testing() *Builder {
 	return b
 }
 
+// Options returns a copy of the options configured so far
+// This is useful to inspect or save the configuration, e.g. with SaveToFile
+func (b *Builder) Options() *Options {
+	opts := *b.opts
+	return &opts
+}
+
 // Build creates and returns a new Log instance with the configured options
 // This method calls the existing NewLog() function with the built options
 func (b *Builder) Build() *Log {
 	return NewLog(b.opts) // Call existing function
 }
+
+// BuildStrict creates a new Log instance like Build, but returns an error instead of
+// falling back to defaults if the configured options are invalid
+func (b *Builder) BuildStrict() (*Log, error) {
+	return NewLogStrict(b.opts) // Call existing function
+}
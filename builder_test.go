@@ -3,10 +3,12 @@ package log
 import (
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 )
 
 func TestNewBuilder(t *testing.T) {
@@ -987,6 +989,26 @@ func ExampleBuilder_presetWithOverrides() {
 	logger.Sync()
 }
 
+// ExampleBuilder_samplingHook demonstrates counting the entries dropped by sampling
+func ExampleBuilder_samplingHook() {
+	var dropped atomic.Int64 // e.g. exported as a logs_dropped_total metric
+
+	logger := NewBuilder().
+		Directory("./logs").
+		Sampling(true, 100, 1000).
+		SamplingHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+			if dec&zapcore.LogDropped != 0 {
+				dropped.Add(1)
+			}
+		}).
+		Build()
+
+	for range 1000 {
+		logger.Info("Hot path message")
+	}
+	logger.Sync()
+}
+
 func TestBuilderConsoleOutput(t *testing.T) {
 	t.Parallel()
 	asrt := assert.New(t)
@@ -1014,3 +1036,17 @@ func TestBuilderConsoleOutput(t *testing.T) {
 		ConsoleOutput(false) // Override preset
 	asrt.False(builder.opts.ConsoleOutput)
 }
+
+func TestBuilderOptions(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	builder := NewBuilder().Level("debug").Filename("app")
+	opts := builder.Options()
+	asrt.Equal("debug", opts.Level)
+	asrt.Equal("app", opts.Filename)
+
+	// The returned options are a copy
+	opts.Level = "error"
+	asrt.Equal("debug", builder.opts.Level)
+}
@@ -0,0 +1,40 @@
+package log
+
+import (
+	"slices"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// funcKey is the key of the field added by Options.CallerWithFunction.
+const funcKey = "func"
+
+// callerFuncCore is a zapcore.Core wrapper adding the name of the calling function to every
+// entry, see Options.CallerWithFunction.
+type callerFuncCore struct {
+	zapcore.Core
+}
+
+// With adds structured context to the wrapped core, keeping the func field.
+func (c *callerFuncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &callerFuncCore{Core: c.Core.With(fields)}
+}
+
+// Check registers this core if the wrapped core accepts the entry, so the func field is
+// added in Write.
+func (c *callerFuncCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(entry, nil) == nil {
+		return ce
+	}
+	return ce.AddCore(entry, c)
+}
+
+// Write writes the entry with the func field. The function is the one zap resolved for the
+// caller, so it honors ExtraCallerSkip like the file and line, and is unknown without caller.
+func (c *callerFuncCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if entry.Caller.Function != "" {
+		fields = append(slices.Clip(fields), zap.String(funcKey, entry.Caller.Function))
+	}
+	return c.Core.Write(entry, fields)
+}
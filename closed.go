@@ -0,0 +1,27 @@
+package log
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// closedCore is a zapcore.Core wrapper dropping the entries of a closed logger, see Log.Close.
+type closedCore struct {
+	zapcore.Core
+	closed *atomic.Bool
+}
+
+// With adds structured context to the wrapped core, still dropping the entries once closed.
+func (c *closedCore) With(fields []zapcore.Field) zapcore.Core {
+	return &closedCore{Core: c.Core.With(fields), closed: c.closed}
+}
+
+// Check drops the entry if the logger is closed, and defers to the wrapped core otherwise.
+// Panic and fatal entries still panic and exit, since zap does so whatever the core.
+func (c *closedCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.closed.Load() {
+		return ce
+	}
+	return c.Core.Check(entry, ce)
+}
@@ -0,0 +1,182 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// backupTimeFormat is the timestamp format lumberjack uses in the names of rotated files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// backupCompressor compresses the backup files produced by lumberjack's size-based rotation
+// with zstd, since lumberjack only supports gzip. It also enforces MaxBackups on the
+// compressed files, which lumberjack doesn't recognize as its own backups.
+type backupCompressor struct {
+	maxSize     int64 // rotation size in bytes
+	maxBackups  int
+	errorOutput io.Writer // receives the compression failures
+
+	mu    sync.Mutex       // protects sizes
+	sizes map[string]int64 // expected current size of each log file
+
+	compressMu sync.Mutex // serializes compression runs
+}
+
+// newBackupCompressor returns a compressor for files rotated at maxSize megabytes,
+// reporting failures to errorOutput.
+func newBackupCompressor(maxSize, maxBackups int, errorOutput io.Writer) *backupCompressor {
+	return &backupCompressor{
+		maxSize:     int64(maxSize) * 1024 * 1024,
+		maxBackups:  maxBackups,
+		errorOutput: errorOutput,
+		sizes:       make(map[string]int64),
+	}
+}
+
+// wrote records that n bytes were written to the log file at filename, mirroring
+// lumberjack's rotation rule to detect when the write caused a rotation, in which
+// case the new backup is compressed in the background.
+func (c *backupCompressor) wrote(filename string, n int) {
+	c.mu.Lock()
+	size, ok := c.sizes[filename]
+	switch {
+	case !ok:
+		// First write seen, start from the actual size
+		if info, err := os.Stat(filename); err == nil {
+			size = info.Size()
+		}
+	case size+int64(n) > c.maxSize:
+		size = int64(n)
+		go c.compressBackups(filename)
+	default:
+		size += int64(n)
+	}
+	c.sizes[filename] = size
+	c.mu.Unlock()
+}
+
+// rotated records that the log file at filename was explicitly rotated,
+// and compresses the new backup in the background.
+func (c *backupCompressor) rotated(filename string) {
+	c.mu.Lock()
+	c.sizes[filename] = 0
+	c.mu.Unlock()
+
+	go c.compressBackups(filename)
+}
+
+// compressBackups compresses every uncompressed backup of the log file at filename,
+// then removes the oldest compressed backups exceeding maxBackups.
+func (c *backupCompressor) compressBackups(filename string) {
+	c.compressMu.Lock()
+	defer c.compressMu.Unlock()
+
+	dir := filepath.Dir(filename)
+	ext := filepath.Ext(filename)
+	prefix := strings.TrimSuffix(filepath.Base(filename), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		fmt.Fprintf(c.errorOutput, "Failed to list rotated log files in %s: %v\n", dir, err)
+		return
+	}
+
+	type backup struct {
+		path string
+		time time.Time
+	}
+	var compressed []backup
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(name, ext+".zst"):
+			ts, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext+".zst"))
+			if err == nil {
+				compressed = append(compressed, backup{filepath.Join(dir, name), ts})
+			}
+
+		case strings.HasSuffix(name, ext):
+			ts, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+			if err != nil {
+				continue
+			}
+
+			path := filepath.Join(dir, name)
+			if err := compressZstd(path, path+".zst"); err != nil {
+				fmt.Fprintf(c.errorOutput, "Failed to compress rotated log file %s: %v\n", path, err)
+				continue
+			}
+			compressed = append(compressed, backup{path + ".zst", ts})
+		}
+	}
+
+	if c.maxBackups <= 0 || len(compressed) <= c.maxBackups {
+		return
+	}
+
+	// Keep the newest maxBackups files
+	slices.SortFunc(compressed, func(a, b backup) int { return b.time.Compare(a.time) })
+	for _, old := range compressed[c.maxBackups:] {
+		if err := os.Remove(old.path); err != nil {
+			fmt.Fprintf(c.errorOutput, "Failed to remove old log file %s: %v\n", old.path, err)
+		}
+	}
+}
+
+// compressZstd compresses the file at src into dst with zstd and removes src.
+func compressZstd(src, dst string) (err error) {
+	in, err := os.Open(src) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode()) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(dst)
+		}
+	}()
+
+	enc, err := zstd.NewWriter(out)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	if _, err = io.Copy(enc, in); err != nil {
+		_ = enc.Close()
+		_ = out.Close()
+		return err
+	}
+	if err = enc.Close(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+
+	_ = in.Close()
+	return os.Remove(src)
+}
@@ -0,0 +1,181 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rotateOnce writes enough data to the logger to trigger a size-based rotation (MaxSize 1MB)
+// and returns the marker message written before the rotation.
+func rotateOnce(logger *Log) string {
+	marker := "rotated-away-marker"
+	logger.Info(marker)
+
+	chunk := strings.Repeat("x", 300*1024)
+	for range 4 {
+		logger.Info(chunk)
+	}
+	return marker
+}
+
+// findBackups returns the rotated files in dir with the given suffix.
+func findBackups(t *testing.T, dir, suffix string) []string {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*T*"+suffix))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestCompressAlgorithm_Rotation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		algorithm  string
+		suffix     string
+		decompress func(io.Reader) (io.Reader, error)
+	}{
+		{
+			algorithm: CompressGzip,
+			suffix:    ".log.gz",
+			decompress: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+		{
+			algorithm: CompressZstd,
+			suffix:    ".log.zst",
+			decompress: func(r io.Reader) (io.Reader, error) {
+				return zstd.NewReader(r)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			t.Parallel()
+			asrt := assert.New(t)
+
+			testDir := "./logs/test_logs_compress_" + tt.algorithm
+			defer os.RemoveAll(testDir)
+
+			logger := NewLog(NewOptions().
+				WithDirectory(testDir).
+				WithConsoleOutput(false).
+				WithMaxSize(1).
+				WithCompress(true).
+				WithCompressAlgorithm(tt.algorithm))
+			defer logger.Close()
+
+			marker := rotateOnce(logger)
+
+			// Compression runs in the background after rotation
+			var backups []string
+			require.Eventually(t, func() bool {
+				backups = findBackups(t, testDir, tt.suffix)
+				return len(backups) == 1 && len(findBackups(t, testDir, ".log")) == 0
+			}, 5*time.Second, 20*time.Millisecond)
+
+			f, err := os.Open(backups[0])
+			require.NoError(t, err)
+			defer f.Close()
+
+			r, err := tt.decompress(f)
+			require.NoError(t, err)
+			content, err := io.ReadAll(r)
+			require.NoError(t, err)
+			asrt.Contains(string(content), marker)
+		})
+	}
+}
+
+func TestCompressAlgorithm_None(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_compress_none"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(false).
+		WithMaxSize(1).
+		WithCompress(true).
+		WithCompressAlgorithm(CompressNone))
+	defer logger.Close()
+
+	marker := rotateOnce(logger)
+
+	backups := findBackups(t, testDir, ".log")
+	require.Len(t, backups, 1)
+
+	content, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	asrt.Contains(string(content), marker)
+
+	time.Sleep(100 * time.Millisecond)
+	asrt.Empty(findBackups(t, testDir, ".gz"))
+	asrt.Empty(findBackups(t, testDir, ".zst"))
+}
+
+func TestBackupCompressor_MaxBackups(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+
+	// Three rotated backups, one of them already compressed
+	base := time.Date(2025, 7, 20, 10, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		name := filepath.Join(dir, "app-"+base.Add(time.Duration(i)*time.Minute).Format(backupTimeFormat)+".log")
+		require.NoError(t, os.WriteFile(name, []byte("backup\n"), 0o644))
+	}
+	require.NoError(t, compressZstd(
+		filepath.Join(dir, "app-"+base.Format(backupTimeFormat)+".log"),
+		filepath.Join(dir, "app-"+base.Format(backupTimeFormat)+".log.zst"),
+	))
+
+	// Files not produced by rotation are left alone
+	require.NoError(t, os.WriteFile(filename, []byte("current\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-notes.log"), nil, 0o644))
+
+	newBackupCompressor(1, 2, os.Stderr).compressBackups(filename)
+
+	backups := findBackups(t, dir, ".log.zst")
+	asrt.Len(backups, 2)
+	asrt.NotContains(backups, filepath.Join(dir, "app-"+base.Format(backupTimeFormat)+".log.zst"))
+	asrt.Empty(findBackups(t, dir, ".log"))
+	asrt.FileExists(filename)
+	asrt.FileExists(filepath.Join(dir, "app-notes.log"))
+}
+
+func TestCompressAlgorithm_ExplicitRotate(t *testing.T) {
+	t.Parallel()
+
+	testDir := "./logs/test_logs_compress_rotate"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(false).
+		WithCompress(true).
+		WithCompressAlgorithm(CompressZstd))
+	defer logger.Close()
+
+	logger.Info("compressed after explicit rotation")
+	require.NoError(t, logger.Rotate())
+
+	require.Eventually(t, func() bool {
+		return len(findBackups(t, testDir, ".log.zst")) == 1 && len(findBackups(t, testDir, ".log")) == 0
+	}, 5*time.Second, 20*time.Millisecond)
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// consoleEncoder is the encoder of the console core when the console and file formats
+// differ. It prepends the prefix like Log does, but never touches the log files.
+type consoleEncoder struct {
+	zapcore.Encoder
+	prefix        string
+	errorToStderr bool                          // whether errors are written to stderr instead
+	onError       func(err error, entry []byte) // reports failed writes to stderr
+}
+
+// Clone copies the encoder, keeping the prefix and stderr settings.
+func (e *consoleEncoder) Clone() zapcore.Encoder {
+	clone := *e
+	clone.Encoder = e.Encoder.Clone()
+	return &clone
+}
+
+// EncodeEntry encodes the entry with the prefix prepended.
+func (e *consoleEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return nil, fmt.Errorf("EncodeEntry error: %w", err)
+	}
+
+	if e.prefix != "" {
+		buf = prependPrefix(buf, e.prefix)
+	}
+
+	if e.errorToStderr && entry.Level >= zapcore.ErrorLevel {
+		writeToStderr(buf, e.onError)
+	}
+
+	return buf, nil
+}
+
+// writeToStderr writes the encoded entry in buf to stderr and empties buf, so the
+// console writer doesn't print it a second time.
+func writeToStderr(buf *buffer.Buffer, onError func(err error, entry []byte)) {
+	if _, err := os.Stderr.Write(buf.Bytes()); err != nil {
+		onError(fmt.Errorf("failed to write to stderr: %w", err), buf.Bytes())
+	}
+	buf.Reset()
+}
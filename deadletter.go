@@ -0,0 +1,69 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// reportFileWriteError reports an entry that ultimately failed to be written to a log file,
+// appending it to the DeadletterFile first if set. Entries saved to the deadletter file are
+// only reported to OnWriteError, if set, since they are not lost.
+func (l *Log) reportFileWriteError(err error, entry []byte) {
+	if l.opts.DeadletterFile == "" {
+		l.reportWriteError(err, entry)
+		return
+	}
+
+	if dlErr := l.writeDeadletter(entry); dlErr != nil {
+		l.reportWriteError(fmt.Errorf("%w, and %w", err, dlErr), entry)
+		return
+	}
+
+	l.stats.writeErrors.Add(1)
+	if l.opts.OnWriteError != nil {
+		l.opts.OnWriteError(err, append([]byte(nil), entry...))
+	}
+}
+
+// writeDeadletter appends the entry to the DeadletterFile, opening it if needed. The file is
+// separate from the log files, so it is still written when they can't be.
+func (l *Log) writeDeadletter(entry []byte) error {
+	l.deadletterMu.Lock()
+	defer l.deadletterMu.Unlock()
+
+	if l.deadletter == nil {
+		path := l.opts.DeadletterFile
+		if err := os.MkdirAll(filepath.Dir(path), l.dirMode()); err != nil {
+			return fmt.Errorf("failed to write to deadletter file: %w", err)
+		}
+
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) //nolint:gosec
+		if err != nil {
+			return fmt.Errorf("failed to write to deadletter file: %w", err)
+		}
+		l.deadletter = file
+	}
+
+	if _, err := l.deadletter.Write(entry); err != nil {
+		return fmt.Errorf("failed to write to deadletter file: %w", err)
+	}
+	return nil
+}
+
+// closeDeadletter closes the DeadletterFile if open. It is reopened by the next failed write.
+func (l *Log) closeDeadletter() error {
+	l.deadletterMu.Lock()
+	defer l.deadletterMu.Unlock()
+
+	if l.deadletter == nil {
+		return nil
+	}
+
+	err := l.deadletter.Close()
+	l.deadletter = nil
+	if err != nil {
+		return fmt.Errorf("close deadletter file: %w", err)
+	}
+	return nil
+}
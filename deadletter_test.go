@@ -0,0 +1,105 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+func TestDeadletterFile(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_deadletter"
+	defer os.RemoveAll(testDir)
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	deadletter := filepath.Join(testDir, "failed", "deadletter.log")
+	logger := NewBuilder().
+		Directory(testDir).
+		ConsoleOutput(false).
+		Isolated(true).
+		DeadletterFile(deadletter).
+		OnWriteError(func(err error, _ []byte) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}).
+		Build()
+	defer logger.Close()
+
+	logger.Info("written")
+	asrt.NoFileExists(deadletter, "the deadletter file is only created on a failed write")
+
+	// Point the log file below a regular file, so every write fails
+	blocker := filepath.Join(testDir, "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0o644))
+	logger.mu.Lock()
+	logger.file = &lumberjack.Logger{Filename: filepath.Join(blocker, "unwritable.log")}
+	logger.mu.Unlock()
+
+	logger.Warnw("lost on a broken disk", "order", 42)
+	logger.Error("also lost")
+	require.NoError(t, logger.Sync())
+
+	content, err := os.ReadFile(deadletter)
+	require.NoError(t, err)
+	asrt.Contains(string(content), "lost on a broken disk")
+	asrt.Contains(string(content), "42")
+	asrt.Contains(string(content), "also lost")
+	asrt.NotContains(string(content), "written")
+
+	// The callback is still invoked for the saved entries
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, errs, 2)
+	asrt.Contains(errs[0].Error(), "failed to write to log file")
+}
+
+func TestDeadletterFile_Unwritable(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_deadletter_unwritable"
+	defer os.RemoveAll(testDir)
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	blocker := filepath.Join(testDir, "blocker")
+	logger := NewBuilder().
+		Directory(testDir).
+		ConsoleOutput(false).
+		Isolated(true).
+		DeadletterFile(filepath.Join(blocker, "deadletter.log")).
+		OnWriteError(func(err error, _ []byte) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}).
+		Build()
+	defer logger.Close()
+
+	logger.Info("written")
+	require.NoError(t, os.WriteFile(blocker, nil, 0o644))
+	logger.mu.Lock()
+	logger.file = &lumberjack.Logger{Filename: filepath.Join(blocker, "unwritable.log")}
+	logger.mu.Unlock()
+
+	logger.Warn("lost on a broken disk")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, errs, 1)
+	asrt.Contains(errs[0].Error(), "failed to write to log file")
+	asrt.Contains(errs[0].Error(), "failed to write to deadletter file")
+}
@@ -119,7 +119,7 @@ func validateLevel(opts *Options) error {
 
 // validateFormat validates and fixes the log format
 func validateFormat(opts *Options) error {
-	if opts.Format != FormatConsole && opts.Format != FormatJSON {
+	if !isValidFormat(opts.Format) {
 		originalValue := opts.Format
 		opts.Format = DefaultFormat
 		return NewConfigError("Format", originalValue, "Use Default Log Format "+DefaultFormat, ErrInvalidFormat)
@@ -150,7 +150,7 @@ func validateDirectory(opts *Options) error {
 // validateFilename validates and fixes the filename
 func validateFilename(opts *Options) error {
 	if opts.Filename != "" {
-		sanitized := sanitizeFilename(opts.Filename)
+		sanitized := sanitizeFilenameMax(opts.Filename, opts.MaxFilenameLength)
 		if sanitized == "" {
 			originalValue := opts.Filename
 			opts.Filename = DefaultFilename
@@ -235,6 +235,13 @@ func validateSampling(opts *Options) error {
 				fmt.Sprintf("使用默认后续采样数 %d", DefaultSampleThereafter), ErrInvalidSampling))
 		}
 
+		if opts.SampleTick < 0 {
+			originalValue := opts.SampleTick
+			opts.SampleTick = DefaultSampleTick
+			errs = append(errs, NewConfigError("SampleTick", originalValue,
+				fmt.Sprintf("使用默认采样周期 %s", DefaultSampleTick), ErrInvalidSampling))
+		}
+
 		if len(errs) > 0 {
 			return fmt.Errorf("采样配置错误: %v", errs)
 		}
@@ -293,7 +300,7 @@ func ValidateAndFixOptions(opts *Options) *Options {
 	fixed, err := ValidateOptions(opts)
 	if err != nil {
 		// Log validation errors but continue with fixed configuration
-		fmt.Fprintf(os.Stderr, "配置验证警告: %v\n", err)
+		fmt.Fprintf(fixed.internalErrors(), "配置验证警告: %v\n", err)
 	}
 	return fixed
 }
@@ -222,6 +222,7 @@ func TestValidateFormat(t *testing.T) {
 	}{
 		{"valid console", "console", false, "console"},
 		{"valid json", "json", false, "json"},
+		{"valid csv", "csv", false, "csv"},
 		{"invalid format", "xml", true, DefaultFormat},
 		{"empty format", "", true, DefaultFormat},
 	}
@@ -0,0 +1,139 @@
+package log
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// callSite identifies the call site of an Every method.
+type callSite struct {
+	file string
+	line int
+}
+
+// everyState tracks the emissions of an Every method call site.
+type everyState struct {
+	mu         sync.Mutex
+	last       time.Time // time of the last emission
+	suppressed int       // calls suppressed since the last emission
+}
+
+// every reports whether the call site of the Every method calling it may emit an entry,
+// given an interval of d between emissions. If so, it returns keysAndValues with the
+// "suppressed" count of the calls dropped since the last emission, if any.
+func (l *Log) every(d time.Duration, keysAndValues []any) ([]any, bool) {
+	if d <= 0 {
+		return keysAndValues, true
+	}
+
+	// The file:line identifies the call site, even if it's inlined in several places
+	_, file, line, _ := runtime.Caller(2) // Skip every and the Every method
+	site := callSite{file: file, line: line}
+
+	value, ok := l.everyState.Load(site)
+	if !ok {
+		value, _ = l.everyState.LoadOrStore(site, &everyState{})
+	}
+	state, _ := value.(*everyState)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	now := time.Now()
+	if !state.last.IsZero() && now.Sub(state.last) < d {
+		state.suppressed++
+		return nil, false
+	}
+
+	state.last = now
+	if state.suppressed > 0 {
+		keysAndValues = append(keysAndValues[:len(keysAndValues):len(keysAndValues)],
+			"suppressed", state.suppressed)
+		state.suppressed = 0
+	}
+	return keysAndValues, true
+}
+
+// DebugEvery logs a message with some additional context at most once every d for each
+// call site, e.g. in a polling loop. The next entry after suppressed calls has a
+// "suppressed" field counting them.
+func DebugEvery(d time.Duration, msg string, keysAndValues ...any) {
+	l := DefaultLogger()
+	if keysAndValues, ok := l.every(d, keysAndValues); ok {
+		l.sugar.Debugw(msg, keysAndValues...)
+	}
+}
+
+// DebugEvery logs a message with some additional context at most once every d for each
+// call site, e.g. in a polling loop. The next entry after suppressed calls has a
+// "suppressed" field counting them.
+func (l *Log) DebugEvery(d time.Duration, msg string, keysAndValues ...any) {
+	if keysAndValues, ok := l.every(d, keysAndValues); ok {
+		l.sugar.Debugw(msg, keysAndValues...)
+	}
+}
+
+// InfoEvery logs a message with some additional context at most once every d for each
+// call site, e.g. in a polling loop. The next entry after suppressed calls has a
+// "suppressed" field counting them.
+//
+// Example Usage:
+//
+//	for {
+//		log.InfoEvery(5*time.Second, "Waiting for leader election", "node", node)
+//		time.Sleep(10 * time.Millisecond)
+//	}
+func InfoEvery(d time.Duration, msg string, keysAndValues ...any) {
+	l := DefaultLogger()
+	if keysAndValues, ok := l.every(d, keysAndValues); ok {
+		l.sugar.Infow(msg, keysAndValues...)
+	}
+}
+
+// InfoEvery logs a message with some additional context at most once every d for each
+// call site, e.g. in a polling loop. The next entry after suppressed calls has a
+// "suppressed" field counting them.
+func (l *Log) InfoEvery(d time.Duration, msg string, keysAndValues ...any) {
+	if keysAndValues, ok := l.every(d, keysAndValues); ok {
+		l.sugar.Infow(msg, keysAndValues...)
+	}
+}
+
+// WarnEvery logs a message with some additional context at most once every d for each
+// call site, e.g. in a polling loop. The next entry after suppressed calls has a
+// "suppressed" field counting them.
+func WarnEvery(d time.Duration, msg string, keysAndValues ...any) {
+	l := DefaultLogger()
+	if keysAndValues, ok := l.every(d, keysAndValues); ok {
+		l.sugar.Warnw(msg, keysAndValues...)
+	}
+}
+
+// WarnEvery logs a message with some additional context at most once every d for each
+// call site, e.g. in a polling loop. The next entry after suppressed calls has a
+// "suppressed" field counting them.
+func (l *Log) WarnEvery(d time.Duration, msg string, keysAndValues ...any) {
+	if keysAndValues, ok := l.every(d, keysAndValues); ok {
+		l.sugar.Warnw(msg, keysAndValues...)
+	}
+}
+
+// ErrorEvery logs a message with some additional context at most once every d for each
+// call site, e.g. in a polling loop. The next entry after suppressed calls has a
+// "suppressed" field counting them.
+func ErrorEvery(d time.Duration, msg string, keysAndValues ...any) {
+	l := DefaultLogger()
+	if keysAndValues, ok := l.every(d, keysAndValues); ok {
+		l.sugar.Errorw(msg, keysAndValues...)
+	}
+}
+
+// ErrorEvery logs a message with some additional context at most once every d for each
+// call site, e.g. in a polling loop. The next entry after suppressed calls has a
+// "suppressed" field counting them.
+func (l *Log) ErrorEvery(d time.Duration, msg string, keysAndValues ...any) {
+	if keysAndValues, ok := l.every(d, keysAndValues); ok {
+		l.sugar.Errorw(msg, keysAndValues...)
+	}
+}
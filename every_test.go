@@ -0,0 +1,53 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLog_InfoEvery(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix(""))
+
+	start := time.Now()
+	for i := range 1000 {
+		logger.InfoEvery(time.Hour, "polling", "i", i)
+	}
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	// Only the first call is emitted
+	entries := recorded.TakeAll()
+	require.Len(t, entries, 1)
+	asrt.Equal("polling", entries[0].Message)
+	asrt.Equal(map[string]any{"i": int64(0)}, entries[0].ContextMap())
+	asrt.Contains(entries[0].Caller.File, "every_test.go")
+
+	// Once the interval elapses, the next entry counts the suppressed calls
+	wait := func() { logger.WarnEvery(50*time.Millisecond, "waiting") } // A single call site
+	for range 1000 {
+		wait()
+	}
+	time.Sleep(60 * time.Millisecond)
+	wait()
+
+	entries = recorded.TakeAll()
+	require.Len(t, entries, 2)
+	asrt.Equal(zapcore.WarnLevel, entries[1].Level)
+	asrt.Empty(entries[0].Context)
+	asrt.Equal(map[string]any{"suppressed": int64(999)}, entries[1].ContextMap())
+
+	// Call sites are throttled independently, and a non-positive interval never throttles
+	logger.InfoEvery(time.Hour, "other call site")
+	for range 3 {
+		logger.ErrorEvery(0, "always")
+	}
+	asrt.Len(recorded.TakeAll(), 4)
+}
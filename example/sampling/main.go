@@ -1,14 +1,26 @@
 package main
 
 import (
+	"fmt"
+	"sync/atomic"
+
 	"github.com/kydenul/log"
+	"go.uber.org/zap/zapcore"
 )
 
 func main() {
+	// Count dropped entries, e.g. to export a logs_dropped_total metric
+	var dropped atomic.Int64
+
 	// Create a logger with aggressive sampling
 	logger := log.NewBuilder().
 		Level("info").
 		Sampling(true, 2, 1000). // Allow 2 initial, then 1 every 1000
+		SamplingHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+			if dec&zapcore.LogDropped != 0 {
+				dropped.Add(1)
+			}
+		}).
 		Build()
 
 	// Test 1: Same message (should be sampled)
@@ -30,4 +42,6 @@ func main() {
 	}
 
 	logger.Sync()
+
+	fmt.Printf("\n=== Dropped by sampling: %d ===\n", dropped.Load())
 }
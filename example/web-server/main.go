@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -13,6 +17,11 @@ import (
 	"github.com/kydenul/log/logutil"
 )
 
+const (
+	serviceName = "web-server-example"
+	version     = "v1.0.0"
+)
+
 // User represents a user in our system
 type User struct {
 	ID        int       `json:"id"`
@@ -159,7 +168,7 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 	response := map[string]interface{}{
 		"status":    "healthy",
 		"timestamp": time.Now().Format(time.RFC3339),
-		"version":   "v1.0.0",
+		"version":   version,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -169,6 +178,10 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 func main() {
 	// Configure logger based on environment
 	env := os.Getenv("ENVIRONMENT")
+
+	// Stamp every log line with the service, version and environment
+	log.WithGlobalFields("service", serviceName, "version", version, "env", env)
+
 	var logger *log.Log
 
 	switch env {
@@ -187,8 +200,9 @@ func main() {
 	}
 
 	// Log application startup
+	startTime := time.Now()
 	port := 8080
-	logutil.LogStartup(logger, "web-server-example", "v1.0.0", port)
+	logutil.LogStartup(logger, serviceName, version, port)
 
 	// Create user service
 	userService := NewUserService(logger)
@@ -220,26 +234,58 @@ func main() {
 
 	http.Handle("/health", middleware(http.HandlerFunc(healthHandler)))
 
+	// Track open connections and in-flight requests, to report what the shutdown released
+	var connections, inFlight atomic.Int64
+	server := &http.Server{
+		Addr: ":8080",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inFlight.Add(1)
+			defer inFlight.Add(-1)
+			http.DefaultServeMux.ServeHTTP(w, r)
+		}),
+		ConnState: func(_ net.Conn, state http.ConnState) {
+			switch state {
+			case http.StateNew:
+				connections.Add(1)
+			case http.StateHijacked, http.StateClosed:
+				connections.Add(-1)
+			}
+		},
+	}
+
 	// Setup graceful shutdown
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
 
 	go func() {
+		defer close(done)
+
 		<-c
 		logger.Warn("Shutdown signal received, initiating graceful shutdown")
 
-		// In a real application, you would:
-		// 1. Stop accepting new requests
-		// 2. Wait for existing requests to complete
-		// 3. Close database connections
-		// 4. Clean up resources
+		// Stop accepting new requests and wait for the in-flight ones to complete
+		stats := logutil.ShutdownStats{
+			ConnectionsClosed: int(connections.Load()),
+			RequestsDrained:   int(inFlight.Load()),
+		}
+		drainStart := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Warnw("Graceful shutdown timed out", "error", err)
+			stats.RequestsAborted = int(inFlight.Load())
+			stats.RequestsDrained -= stats.RequestsAborted
+		}
+		stats.DrainDuration = time.Since(drainStart)
 
-		shutdownStart := time.Now()
-		time.Sleep(1 * time.Second) // Simulate cleanup time
-		shutdownDuration := time.Since(shutdownStart)
+		// Flush the logs written while draining
+		flushStart := time.Now()
+		_ = logger.Sync()
+		stats.FlushDuration = time.Since(flushStart)
 
-		logutil.LogShutdown(logger, "web-server-example", shutdownDuration)
-		os.Exit(0)
+		logutil.LogShutdownWithStats(logger, serviceName, time.Since(startTime), stats)
+		_ = logger.Sync()
 	}()
 
 	// Start server
@@ -249,7 +295,8 @@ func main() {
 		"endpoints", []string{"/users", "/users/{id}", "/health"},
 	)
 
-	if err := http.ListenAndServe(":8080", nil); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		logger.Fatal("Server failed to start", "error", err)
 	}
+	<-done
 }
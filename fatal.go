@@ -0,0 +1,43 @@
+package log
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// osExit terminates the process after a fatal entry, replaced in tests.
+var osExit = os.Exit
+
+// fatalHook is the hook run after a fatal entry is written. Unlike zapcore.WriteThenFatal,
+// it calls Options.FatalHook and flushes the log files before exiting.
+type fatalHook struct {
+	logger *Log
+}
+
+// OnWrite runs the cleanup and exits the process.
+func (h fatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	h.logger.runFatalHook()
+
+	if err := h.logger.Sync(); err != nil {
+		fmt.Fprintf(h.logger.opts.internalErrors(), "Failed to sync logger before exit: %v\n", err)
+	}
+
+	osExit(1)
+}
+
+// runFatalHook calls Options.FatalHook, if set. A panic in the hook is reported to
+// InternalErrorWriter so the process still exits.
+func (l *Log) runFatalHook() {
+	if l.opts.FatalHook == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(l.opts.internalErrors(), "Fatal hook panicked: %v\n", r)
+		}
+	}()
+	l.opts.FatalHook()
+}
@@ -0,0 +1,193 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Not parallel: replaces osExit and os.Stderr
+func TestFatalHook(t *testing.T) {
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_fatal_hook"
+	defer os.RemoveAll(testDir)
+
+	var calls []string
+	defer func(exit func(int)) { osExit = exit }(osExit)
+	osExit = func(int) { calls = append(calls, "exit") }
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("").
+		WithFormat(FormatJSON).
+		WithConsoleOutput(false).
+		WithSplitFatal(true).
+		WithFatalHook(func() { calls = append(calls, "hook") }))
+
+	logger.Fatalw("cannot start", "reason", "port in use")
+
+	// The hook runs after the entry is written and before the process exits
+	asrt.Equal([]string{"hook", "exit"}, calls)
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 1)
+	asrt.Equal("fatal", entries[0]["level"])
+	asrt.Equal("port in use", entries[0]["reason"])
+
+	// The fatal log file is written before the process exits
+	content, err := os.ReadFile(filepath.Join(testDir, logger.levelFileName(logger.currDate, "fatal")))
+	require.NoError(t, err)
+	asrt.Contains(string(content), "cannot start")
+
+	// The process exits even if the hook panics
+	calls = nil
+	logger = NewLogWithCore(nil, NewOptions().WithFatalHook(func() { panic("cleanup failed") }))
+	stderr := captureOutput(t, &os.Stderr)
+	logger.Fatal("boom")
+	asrt.Contains(stderr(), "Fatal hook panicked: cleanup failed")
+	asrt.Equal([]string{"exit"}, calls)
+}
+
+func TestSplitFatal(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_split_fatal"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Filename("app").
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		DisableSplitError(false).
+		SplitFatal(true).
+		Isolated(true).
+		Build()
+
+	logger.Info("started")
+	logger.Error("request failed")
+	asrt.Panics(func() { logger.Panicw("invariant violated", "order_id", 42) })
+	require.NoError(t, logger.Sync())
+
+	// Only the panic entry goes to the fatal log file
+	fatalName := logger.levelFileName(logger.currDate, "fatal")
+	asrt.Equal("app-"+logger.currDate+"_fatal.log", fatalName)
+	content, err := os.ReadFile(filepath.Join(testDir, fatalName))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 1)
+	asrt.Contains(lines[0], `"level":"panic"`)
+	asrt.Contains(lines[0], `"msg":"invariant violated","order_id":42`)
+
+	// The main log file still has every entry, and the error log file the error and the panic
+	asrt.Len(readJSONEntries(t, logger, testDir), 3)
+	content, err = os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, true)))
+	require.NoError(t, err)
+	asrt.NotContains(string(content), "started")
+	asrt.Contains(string(content), "request failed")
+	asrt.Contains(string(content), "invariant violated")
+
+	// Without SplitFatal there is no fatal log file
+	plainDir := "./logs/test_logs_split_fatal_disabled"
+	defer os.RemoveAll(plainDir)
+	plain := NewLog(NewOptions().WithDirectory(plainDir).WithConsoleOutput(false).WithIsolated(true))
+	asrt.Panics(func() { plain.Panic("boom") })
+	require.NoError(t, plain.Sync())
+	asrt.NoFileExists(filepath.Join(plainDir, plain.levelFileName(plain.currDate, "fatal")))
+}
+
+func TestSplitError_PanicLevels(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_split_error_panic_levels"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		DisableSplitError(false).
+		Isolated(true).
+		Build()
+
+	logger.Warn("slow request")
+	logger.Error("request failed")
+	logger.Zap().DPanic("unexpected state") // Only panics in development mode
+	func() {
+		defer func() { asrt.NotNil(recover()) }()
+		logger.Panicw("invariant violated", "order_id", 42)
+	}()
+	require.NoError(t, logger.Sync())
+
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, true)))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 3, "every entry at error level and above, but not the warning")
+	asrt.Contains(lines[0], `"level":"error"`)
+	asrt.Contains(lines[1], `"level":"dpanic"`)
+	asrt.Contains(lines[2], `"level":"panic"`)
+	asrt.Contains(lines[2], `"msg":"invariant violated","order_id":42`)
+}
+
+func TestErrorsOnlyInErrorFile(t *testing.T) {
+	t.Parallel()
+
+	for _, only := range []bool{false, true} {
+		t.Run(fmt.Sprintf("only=%v", only), func(t *testing.T) {
+			t.Parallel()
+			asrt := assert.New(t)
+
+			testDir := fmt.Sprintf("./logs/test_logs_errors_only_in_error_file_%v", only)
+			defer os.RemoveAll(testDir)
+
+			logger := NewBuilder().
+				Directory(testDir).
+				Prefix("").
+				Format(FormatJSON).
+				ConsoleOutput(false).
+				DisableSplitError(false).
+				ErrorsOnlyInErrorFile(only).
+				Isolated(true).
+				Build()
+
+			logger.Info("request served")
+			logger.Error("request failed")
+			require.NoError(t, logger.Sync())
+
+			main, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+			require.NoError(t, err)
+			errs, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, true)))
+			require.NoError(t, err)
+
+			asrt.Contains(string(main), "request served")
+			asrt.NotContains(string(errs), "request served")
+			asrt.Contains(string(errs), "request failed")
+			if only {
+				asrt.NotContains(string(main), "request failed")
+			} else {
+				asrt.Contains(string(main), "request failed")
+			}
+		})
+	}
+}
+
+func TestSplitFatal_FilenamePattern(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	logger := &Log{opts: NewOptions().WithFilenamePattern("{date}/app-{level}.log")}
+	asrt.Equal(filepath.Join("2025-07-20", "app-fatal.log"), logger.levelFileName("2025-07-20", "fatal"))
+
+	logger = &Log{opts: NewOptions().WithFilenamePattern("{date}/app.log")}
+	asrt.Equal(filepath.Join("2025-07-20", "app_fatal.log"), logger.levelFileName("2025-07-20", "fatal"))
+	asrt.Equal(filepath.Join("2025-07-20", "app_error.log"), logger.generateFileName("2025-07-20", true))
+}
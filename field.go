@@ -0,0 +1,133 @@
+package log
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Field is a strongly-typed key-value pair for structured logging.
+// Unlike the loosely-typed key-value pairs of the "w" methods, fields are passed
+// directly to the underlying zap logger, without boxing or reflection.
+type Field = zapcore.Field
+
+// String constructs a field with the given key and string value.
+func String(key, val string) Field { return zap.String(key, val) }
+
+// Int constructs a field with the given key and int value.
+func Int(key string, val int) Field { return zap.Int(key, val) }
+
+// Int64 constructs a field with the given key and int64 value.
+func Int64(key string, val int64) Field { return zap.Int64(key, val) }
+
+// Uint64 constructs a field with the given key and uint64 value.
+func Uint64(key string, val uint64) Field { return zap.Uint64(key, val) }
+
+// Float64 constructs a field with the given key and float64 value.
+func Float64(key string, val float64) Field { return zap.Float64(key, val) }
+
+// Bool constructs a field with the given key and bool value.
+func Bool(key string, val bool) Field { return zap.Bool(key, val) }
+
+// Duration constructs a field with the given key and time.Duration value.
+func Duration(key string, val time.Duration) Field { return zap.Duration(key, val) }
+
+// Time constructs a field with the given key and time.Time value.
+func Time(key string, val time.Time) Field { return zap.Time(key, val) }
+
+// Err constructs a field that lazily stores err.Error() under the key "error".
+// If err is nil, the field is a no-op.
+func Err(err error) Field { return zap.Error(err) }
+
+// Any constructs a field with the given key and an arbitrary value, choosing the best
+// representation for its type. Prefer the typed constructors on hot paths.
+func Any(key string, val any) Field { return zap.Any(key, val) }
+
+// DebugF logs a message with strongly-typed fields at debug level, bypassing the sugar layer.
+func DebugF(msg string, fields ...Field) { DefaultLogger().log.Debug(msg, fields...) }
+
+// DebugF logs a message with strongly-typed fields at debug level, bypassing the sugar layer.
+func (l *Log) DebugF(msg string, fields ...Field) { l.log.Debug(msg, fields...) }
+
+// InfoF logs a message with strongly-typed fields at info level, bypassing the sugar layer.
+//
+// Example:
+//
+//	logger.InfoF("Request handled",
+//	    log.String("path", r.URL.Path),
+//	    log.Int("status", status),
+//	    log.Duration("latency", time.Since(start)),
+//	)
+func InfoF(msg string, fields ...Field) { DefaultLogger().log.Info(msg, fields...) }
+
+// InfoF logs a message with strongly-typed fields at info level, bypassing the sugar layer.
+func (l *Log) InfoF(msg string, fields ...Field) { l.log.Info(msg, fields...) }
+
+// WarnF logs a message with strongly-typed fields at warn level, bypassing the sugar layer.
+func WarnF(msg string, fields ...Field) { DefaultLogger().log.Warn(msg, fields...) }
+
+// WarnF logs a message with strongly-typed fields at warn level, bypassing the sugar layer.
+func (l *Log) WarnF(msg string, fields ...Field) { l.log.Warn(msg, fields...) }
+
+// ErrorF logs a message with strongly-typed fields at error level, bypassing the sugar layer.
+func ErrorF(msg string, fields ...Field) { DefaultLogger().log.Error(msg, fields...) }
+
+// ErrorF logs a message with strongly-typed fields at error level, bypassing the sugar layer.
+func (l *Log) ErrorF(msg string, fields ...Field) { l.log.Error(msg, fields...) }
+
+// DebugMsg logs a message with fields at debug level through the non-sugared zap logger.
+// It is equivalent to DebugF.
+func DebugMsg(msg string, fields ...zapcore.Field) { DefaultLogger().log.Debug(msg, fields...) }
+
+// DebugMsg logs a message with fields at debug level through the non-sugared zap logger.
+// It is equivalent to DebugF.
+func (l *Log) DebugMsg(msg string, fields ...zapcore.Field) { l.log.Debug(msg, fields...) }
+
+// InfoMsg logs a message with fields at info level through the non-sugared zap logger,
+// avoiding the allocations of the sugar layer on latency-sensitive paths. The prefix
+// and file output still apply, since they live in the encoder. It is equivalent to InfoF.
+func InfoMsg(msg string, fields ...zapcore.Field) { DefaultLogger().log.Info(msg, fields...) }
+
+// InfoMsg logs a message with fields at info level through the non-sugared zap logger,
+// avoiding the allocations of the sugar layer on latency-sensitive paths. The prefix
+// and file output still apply, since they live in the encoder. It is equivalent to InfoF.
+func (l *Log) InfoMsg(msg string, fields ...zapcore.Field) { l.log.Info(msg, fields...) }
+
+// WarnMsg logs a message with fields at warn level through the non-sugared zap logger.
+// It is equivalent to WarnF.
+func WarnMsg(msg string, fields ...zapcore.Field) { DefaultLogger().log.Warn(msg, fields...) }
+
+// WarnMsg logs a message with fields at warn level through the non-sugared zap logger.
+// It is equivalent to WarnF.
+func (l *Log) WarnMsg(msg string, fields ...zapcore.Field) { l.log.Warn(msg, fields...) }
+
+// ErrorMsg logs a message with fields at error level through the non-sugared zap logger.
+// It is equivalent to ErrorF.
+func ErrorMsg(msg string, fields ...zapcore.Field) { DefaultLogger().log.Error(msg, fields...) }
+
+// ErrorMsg logs a message with fields at error level through the non-sugared zap logger.
+// It is equivalent to ErrorF.
+func (l *Log) ErrorMsg(msg string, fields ...zapcore.Field) { l.log.Error(msg, fields...) }
+
+// CheckedEntry is an entry that passed the level and sampling checks of a logger, see Check.
+type CheckedEntry = zapcore.CheckedEntry
+
+// Check returns a CheckedEntry if a message at the given level would be logged, or nil
+// otherwise, so hot paths only build their fields when they are written. Nothing is
+// allocated when the level is disabled. Invalid levels return nil.
+//
+// The entry is written through the same prefix, outputs and files as the other methods.
+//
+// Example Usage:
+//
+//	if ce := logger.Check("debug", "Cache miss"); ce != nil {
+//	    ce.Write(log.String("key", key), log.Int("size", size))
+//	}
+func (l *Log) Check(level, msg string) *CheckedEntry {
+	zapLevel, ok := parseLevel(level)
+	if !ok {
+		return nil
+	}
+	return l.log.Check(zapLevel, msg)
+}
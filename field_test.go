@@ -0,0 +1,193 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFieldHelpers(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	now := time.Date(2025, 7, 20, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		field Field
+		key   string
+		typ   zapcore.FieldType
+		want  any
+	}{
+		{String("s", "value"), "s", zapcore.StringType, "value"},
+		{Int("i", 42), "i", zapcore.Int64Type, int64(42)},
+		{Int64("i64", -7), "i64", zapcore.Int64Type, int64(-7)},
+		{Uint64("u64", 7), "u64", zapcore.Uint64Type, uint64(7)},
+		{Float64("f", 1.5), "f", zapcore.Float64Type, 1.5},
+		{Bool("b", true), "b", zapcore.BoolType, true},
+		{Duration("d", time.Second), "d", zapcore.DurationType, time.Second},
+		{Time("t", now), "t", zapcore.TimeType, now},
+		{Err(errors.New("boom")), "error", zapcore.ErrorType, "boom"},
+		{Any("a", []string{"x"}), "a", zapcore.ArrayMarshalerType, []any{"x"}},
+	}
+
+	for _, tt := range tests {
+		asrt.Equal(tt.key, tt.field.Key)
+		asrt.Equal(tt.typ, tt.field.Type, tt.key)
+
+		enc := zapcore.NewMapObjectEncoder()
+		tt.field.AddTo(enc)
+		asrt.Equal(tt.want, enc.Fields[tt.key], tt.key)
+	}
+
+	// A nil error is a no-op field
+	asrt.Equal(zapcore.SkipType, Err(nil).Type)
+}
+
+func TestLog_TypedFieldMethods(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix("TYPED_"))
+
+	logger.DebugF("debug typed", Int("n", 1))
+	logger.InfoF("info typed", String("path", "/health"), Duration("latency", time.Millisecond))
+	logger.WarnF("warn typed", Bool("retry", true))
+	logger.ErrorF("error typed", Err(errors.New("boom")))
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 4)
+
+	asrt.Equal(zapcore.DebugLevel, entries[0].Level)
+	asrt.Equal(zapcore.InfoLevel, entries[1].Level)
+	asrt.Equal(zapcore.WarnLevel, entries[2].Level)
+	asrt.Equal(zapcore.ErrorLevel, entries[3].Level)
+
+	asrt.Equal("TYPED_info typed", entries[1].Message)
+	asrt.Equal("/health", entries[1].ContextMap()["path"])
+	asrt.Equal(time.Millisecond, entries[1].ContextMap()["latency"])
+	asrt.Equal("boom", entries[3].ContextMap()["error"])
+
+	// The caller is the call site, not the wrapper
+	asrt.Contains(entries[1].Caller.File, "field_test.go")
+}
+
+func TestLog_InfoF_FileOutput(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_typed_fields"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("TYPED_").
+		WithFormat("json").
+		WithConsoleOutput(false))
+	defer logger.Close()
+
+	logger.InfoF("typed file message", String("user", "alice"), Int("attempt", 3))
+
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	asrt.Contains(string(content), "TYPED_")
+	asrt.Contains(string(content), `"user":"alice"`)
+	asrt.Contains(string(content), `"attempt":3`)
+}
+
+func TestLog_ZeroSugarMethods(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_zero_sugar"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("MSG_").
+		WithLevel("debug").
+		WithDisableSplitError(false).
+		WithConsoleOutput(false))
+	defer logger.Close()
+
+	logger.DebugMsg("debug msg")
+	logger.InfoMsg("info msg", zapcore.Field{Key: "k", Type: zapcore.StringType, String: "v"})
+	logger.WarnMsg("warn msg")
+	logger.ErrorMsg("error msg", Int("code", 500))
+
+	// Prefix and file output still apply, since they live in the encoder
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	for _, want := range []string{"MSG_", "debug msg", "info msg", `{"k": "v"}`, "warn msg", "error msg"} {
+		asrt.Contains(string(content), want)
+	}
+
+	errContent, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, true)))
+	require.NoError(t, err)
+	asrt.Contains(string(errContent), "error msg")
+	asrt.NotContains(string(errContent), "warn msg")
+}
+
+func TestLog_Check(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_check"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("CHECK_").
+		WithFormat(FormatJSON).
+		WithLevel("info").
+		WithDisableSplitError(false).
+		WithConsoleOutput(false))
+	defer logger.Close()
+
+	// Disabled and invalid levels return nil
+	asrt.Nil(logger.Check("debug", "disabled"))
+	asrt.Nil(logger.Check("verbose", "invalid"))
+	asrt.Nil(logger.Check("", "invalid"))
+
+	ce := logger.Check("info", "checked info")
+	require.NotNil(t, ce)
+	ce.Write(String("key", "value"))
+
+	ce = logger.Check("error", "checked error")
+	require.NotNil(t, ce)
+	ce.Write(Int("code", 500))
+
+	// The entries go through the prefix, caller and files like the other methods
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	asrt.Contains(string(content), `CHECK_{"level":"info"`)
+	asrt.Contains(string(content), `"msg":"checked info","key":"value"`)
+	asrt.Contains(string(content), `"caller":"module/field_test.go`)
+	asrt.NotContains(string(content), "disabled")
+
+	errContent, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, true)))
+	require.NoError(t, err)
+	asrt.Contains(string(errContent), `"msg":"checked error","code":500`)
+}
+
+// Not parallel: AllocsPerRun can't run in parallel tests
+func TestLog_Check_NoAllocsWhenDisabled(t *testing.T) {
+	testDir := "./logs/test_logs_check_allocs"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().WithDirectory(testDir).WithConsoleOutput(false))
+	defer logger.Close()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if ce := logger.Check("debug", "disabled"); ce != nil {
+			ce.Write(String("key", "value"))
+		}
+	})
+	assert.Zero(t, allocs)
+}
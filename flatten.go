@@ -0,0 +1,145 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// flattenCore is a zapcore.Core wrapper replacing the fields holding maps, structs and
+// objects by one field per leaf value, see Options.FlattenNested.
+type flattenCore struct {
+	zapcore.Core
+	separator string
+}
+
+// With adds structured context to the wrapped core, with the fields flattened.
+func (c *flattenCore) With(fields []zapcore.Field) zapcore.Core {
+	return &flattenCore{Core: c.Core.With(c.flatten(fields)), separator: c.separator}
+}
+
+// Check registers this core if the wrapped core accepts the entry, so the fields are
+// flattened in Write.
+func (c *flattenCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(entry, nil) == nil {
+		return ce
+	}
+	return ce.AddCore(entry, c)
+}
+
+// Write writes the entry with the fields flattened.
+func (c *flattenCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.flatten(fields))
+}
+
+// flatten returns fields with the nested values flattened. fields is only copied if a
+// value is flattened.
+func (c *flattenCore) flatten(fields []zapcore.Field) []zapcore.Field {
+	flattened, copied := fields, false
+	for i, field := range fields {
+		nested, ok := nestedValue(field)
+		if !ok {
+			if copied {
+				flattened = append(flattened, field)
+			}
+			continue
+		}
+		if !copied {
+			flattened, copied = slices.Clone(fields[:i]), true
+		}
+		flattened = c.appendFlattened(flattened, field.Key, nested)
+	}
+	return flattened
+}
+
+// appendFlattened appends one field per leaf value of nested to fields, keyed by the path
+// to the leaf joined with the separator, e.g. "user.address.city". Keys are sorted, so the
+// order is stable. Empty maps are kept as is, so the key isn't lost.
+func (c *flattenCore) appendFlattened(fields []zapcore.Field, prefix string, nested map[string]any) []zapcore.Field {
+	if len(nested) == 0 {
+		return append(fields, zap.Any(prefix, nested))
+	}
+
+	keys := make([]string, 0, len(nested))
+	for key := range nested {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		path := prefix + c.separator + key
+		if child, ok := asMap(nested[key]); ok {
+			fields = c.appendFlattened(fields, path, child)
+		} else {
+			fields = append(fields, leafField(path, nested[key]))
+		}
+	}
+	return fields
+}
+
+// nestedValue returns the value of field as a map if it's a map, a struct or an object,
+// the fields worth flattening.
+func nestedValue(field zapcore.Field) (map[string]any, bool) {
+	switch field.Type {
+	case zapcore.ReflectType:
+		return asMap(field.Interface)
+
+	case zapcore.ObjectMarshalerType:
+		enc := zapcore.NewMapObjectEncoder()
+		if err := enc.AddObject(field.Key, field.Interface.(zapcore.ObjectMarshaler)); err != nil {
+			return nil, false
+		}
+		return asMap(enc.Fields[field.Key])
+
+	default:
+		return nil, false
+	}
+}
+
+// asMap returns value as a map if it's a map, a struct or a pointer to one. Other than
+// map[string]any, values are converted through their JSON encoding, so the keys are the
+// ones of the JSON encoder and json tags are honored.
+func asMap(value any) (map[string]any, bool) {
+	if m, ok := value.(map[string]any); ok {
+		return m, true
+	}
+
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Map && v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // Keep integers as such
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil || m == nil {
+		return nil, false
+	}
+	return m, true
+}
+
+// leafField returns the field for a leaf value, with the numbers decoded from JSON typed
+// back as integers or floats.
+func leafField(key string, value any) zapcore.Field {
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return zap.Int64(key, i)
+		}
+		if f, err := n.Float64(); err == nil {
+			return zap.Float64(key, f)
+		}
+		return zap.String(key, n.String())
+	}
+	return zap.Any(key, value)
+}
@@ -0,0 +1,138 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type flattenAddress struct {
+	City    string `json:"city"`
+	ZipCode string `json:"zip_code"`
+}
+
+type flattenUser struct {
+	ID      int            `json:"id"`
+	Name    string         `json:"name"`
+	Address flattenAddress `json:"address"`
+	Tags    []string       `json:"tags"`
+	secret  string
+}
+
+func TestFlattenNested_Maps(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix("").WithFlattenNested(true))
+
+	logger.Infow("nested maps",
+		"user", map[string]any{"id": 1, "profile": map[string]any{"plan": "pro", "seats": 5}},
+		"limits", map[string]int{"rps": 100},
+		"empty", map[string]any{},
+		"ids", []int{1, 2},
+		"count", 42,
+	)
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	asrt.Equal(map[string]any{
+		"user.id":            int64(1),
+		"user.profile.plan":  "pro",
+		"user.profile.seats": int64(5),
+		"limits.rps":         int64(100),
+		"empty":              map[string]any{},
+		"ids":                []any{1, 2},
+		"count":              int64(42),
+	}, entries[0].ContextMap())
+
+	// Keys are sorted, in place of the nested field
+	var keys []string
+	for _, field := range entries[0].Context {
+		keys = append(keys, field.Key)
+	}
+	asrt.Equal([]string{
+		"user.id", "user.profile.plan", "user.profile.seats", "limits.rps", "empty", "ids", "count",
+	}, keys)
+}
+
+func TestFlattenNested_Structs(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix("").
+		WithFlattenNested(true).WithFlattenSeparator("_"))
+
+	user := flattenUser{
+		ID:      7,
+		Name:    "ada",
+		Address: flattenAddress{City: "London", ZipCode: "N1"},
+		Tags:    []string{"admin"},
+		secret:  "hidden",
+	}
+	logger.Infow("struct", "user", user)
+	logger.InfoF("pointer", Any("user", &user), zap.Float64("ratio", 0.5))
+	logger.InfoF("object", zap.Object("req", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddString("method", "GET")
+		return enc.AddObject("peer", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddInt("port", 443)
+			return nil
+		}))
+	})))
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 3)
+
+	// json tags are honored, unexported fields are left out like in the JSON encoding
+	want := map[string]any{
+		"user_id":               int64(7),
+		"user_name":             "ada",
+		"user_address_city":     "London",
+		"user_address_zip_code": "N1",
+		"user_tags":             []any{"admin"},
+	}
+	asrt.Equal(want, entries[0].ContextMap())
+
+	want["ratio"] = 0.5
+	asrt.Equal(want, entries[1].ContextMap())
+
+	asrt.Equal(map[string]any{"req_method": "GET", "req_peer_port": int64(443)}, entries[2].ContextMap())
+}
+
+func TestFlattenNested_FileOutput(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_flatten_nested"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatConsole).
+		ConsoleOutput(false).
+		FlattenNested(true).
+		Fields(map[string]any{"service": map[string]any{"name": "billing"}}).
+		Isolated(true).
+		Build()
+
+	logger.WithField("request", map[string]any{"id": "r-1"}).
+		Infow("charged", "user", map[string]any{"id": 1})
+	require.NoError(t, logger.Sync())
+
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	line := strings.TrimSpace(string(content))
+	for _, want := range []string{`"service.name": "billing"`, `"request.id": "r-1"`, `"user.id": 1`} {
+		asrt.Contains(line, want)
+	}
+	asrt.NotContains(line, `{"id"`)
+}
@@ -0,0 +1,106 @@
+package log
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// globalFields holds the fields set by WithGlobalFields, added to the entries of all loggers.
+var globalFields atomic.Pointer[[]zapcore.Field]
+
+// WithGlobalFields sets fields added to every entry of every logger, including loggers
+// created before the call, e.g. to stamp the service, version and environment on every
+// line. It takes alternating keys and values like the "w" methods; Field values are
+// accepted as is. Each call replaces the previous global fields, and calling it without
+// arguments removes them.
+//
+// Fields of a logger (Options.Fields, IncludeHostPID) and fields passed to a log call take
+// precedence over global fields with the same key.
+//
+// Example Usage:
+//
+//	log.WithGlobalFields("service", "checkout", "version", version, "env", env)
+func WithGlobalFields(keysAndValues ...any) {
+	var fields []zapcore.Field
+	for i := 0; i < len(keysAndValues); i++ {
+		if field, ok := keysAndValues[i].(zapcore.Field); ok {
+			fields = append(fields, field)
+			continue
+		}
+
+		// A key without a value is ignored
+		if i == len(keysAndValues)-1 {
+			break
+		}
+		fields = append(fields, zap.Any(fmt.Sprint(keysAndValues[i]), keysAndValues[i+1]))
+		i++
+	}
+
+	if len(fields) == 0 {
+		globalFields.Store(nil)
+		return
+	}
+	globalFields.Store(&fields)
+}
+
+// globalFieldsCore is a zapcore.Core wrapper adding the global fields to every entry,
+// except those whose key is already set by the logger or by the log call.
+type globalFieldsCore struct {
+	zapcore.Core
+	keys []string // keys of the context fields of the logger
+}
+
+// With adds structured context to the wrapped core, recording the keys so they take
+// precedence over the global fields.
+func (c *globalFieldsCore) With(fields []zapcore.Field) zapcore.Core {
+	keys := slices.Clone(c.keys)
+	for _, field := range fields {
+		keys = append(keys, field.Key)
+	}
+	return &globalFieldsCore{Core: c.Core.With(fields), keys: keys}
+}
+
+// Check delegates to the wrapped core if there are no global fields. Otherwise it
+// registers this core if the wrapped core accepts the entry, so they are added in Write.
+func (c *globalFieldsCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if globalFields.Load() == nil {
+		return c.Core.Check(entry, ce)
+	}
+	if c.Core.Check(entry, nil) == nil {
+		return ce
+	}
+	return ce.AddCore(entry, c)
+}
+
+// Write adds the global fields before the fields of the log call and writes the entry.
+func (c *globalFieldsCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	global := globalFields.Load()
+	if global == nil {
+		return c.Core.Write(entry, fields)
+	}
+
+	merged := make([]zapcore.Field, 0, len(*global)+len(fields))
+	for _, field := range *global {
+		if slices.Contains(c.keys, field.Key) || hasFieldKey(fields, field.Key) {
+			continue
+		}
+		merged = append(merged, field)
+	}
+	merged = append(merged, fields...)
+
+	return c.Core.Write(entry, merged)
+}
+
+// hasFieldKey reports whether one of fields has the given key.
+func hasFieldKey(fields []zapcore.Field, key string) bool {
+	for _, field := range fields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
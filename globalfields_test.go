@@ -0,0 +1,103 @@
+package log
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Not parallel: sets the global fields
+func TestWithGlobalFields(t *testing.T) {
+	asrt := assert.New(t)
+	defer WithGlobalFields()
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix("")) // Created before the call
+
+	WithGlobalFields("service", "checkout", "version", "1.2.3", "env", "dev")
+
+	logger.Info("global fields")
+	logger.Infow("per-call override", "env", "prod", "order_id", 42)
+	logger.InfoF("typed override", String("version", "1.2.4"))
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 3)
+	asrt.Equal(map[string]any{"service": "checkout", "version": "1.2.3", "env": "dev"},
+		entries[0].ContextMap())
+	asrt.Equal(map[string]any{"service": "checkout", "version": "1.2.3", "env": "prod", "order_id": int64(42)},
+		entries[1].ContextMap())
+	asrt.Len(entries[1].Context, 4, "overridden global field must not be duplicated")
+	asrt.Equal("1.2.4", entries[2].ContextMap()["version"])
+	asrt.Len(entries[2].Context, 3)
+
+	// Fields of the logger take precedence over global fields
+	core, recorded = observer.New(zapcore.InfoLevel)
+	NewLogWithCore(core, NewOptions().WithFields(map[string]any{"env": "staging"})).Info("logger fields")
+
+	entries = recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	asrt.Equal(map[string]any{"service": "checkout", "version": "1.2.3", "env": "staging"},
+		entries[0].ContextMap())
+	asrt.Len(entries[0].Context, 3)
+
+	// Fields are accepted as is, and keys without a value are ignored
+	WithGlobalFields(String("region", "eu"), "dangling")
+	core, recorded = observer.New(zapcore.InfoLevel)
+	NewLogWithCore(core, NewOptions()).Info("field values")
+	asrt.Equal(map[string]any{"region": "eu"}, recorded.AllUntimed()[0].ContextMap())
+
+	// Calling without arguments removes the global fields
+	WithGlobalFields()
+	core, recorded = observer.New(zapcore.InfoLevel)
+	NewLogWithCore(core, NewOptions()).Info("no global fields")
+	asrt.Empty(recorded.AllUntimed()[0].Context)
+}
+
+// Not parallel: sets the global fields
+func TestWithGlobalFields_FileOutput(t *testing.T) {
+	asrt := assert.New(t)
+	defer WithGlobalFields()
+
+	testDir := "./logs/test_logs_global_fields"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		Fields(map[string]any{"version": "2.0.0"}).
+		Build()
+
+	WithGlobalFields("service", "checkout", "version", "1.0.0")
+	logger.Infow("to file", "request_id", "r-1")
+	require.NoError(t, logger.Sync())
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 1)
+	asrt.Equal("checkout", entries[0]["service"])
+	asrt.Equal("2.0.0", entries[0]["version"])
+	asrt.Equal("r-1", entries[0]["request_id"])
+}
+
+func TestOptionsFields(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().
+		WithFields(map[string]any{"service": "billing", "replicas": 3}))
+
+	logger.Info("first")
+	logger.Warnw("second", "attempt", 2)
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	asrt.Equal(map[string]any{"service": "billing", "replicas": int64(3)}, entries[0].ContextMap())
+	asrt.Equal(map[string]any{"service": "billing", "replicas": int64(3), "attempt": int64(2)},
+		entries[1].ContextMap())
+}
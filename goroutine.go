@@ -0,0 +1,60 @@
+package log
+
+import (
+	"runtime"
+	"slices"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// goroutineKey is the key of the field added by Options.IncludeGoroutineID.
+const goroutineKey = "goroutine"
+
+// goroutineCore is a zapcore.Core wrapper adding the ID of the logging goroutine to every
+// entry, see Options.IncludeGoroutineID.
+type goroutineCore struct {
+	zapcore.Core
+}
+
+// With adds structured context to the wrapped core, keeping the goroutine field.
+func (c *goroutineCore) With(fields []zapcore.Field) zapcore.Core {
+	return &goroutineCore{Core: c.Core.With(fields)}
+}
+
+// Check registers this core if the wrapped core accepts the entry, so the goroutine ID is
+// only looked up for the entries written.
+func (c *goroutineCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(entry, nil) == nil {
+		return ce
+	}
+	return ce.AddCore(entry, c)
+}
+
+// Write writes the entry with the goroutine field. It runs on the logging goroutine.
+func (c *goroutineCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	fields = append(slices.Clip(fields), zap.Uint64(goroutineKey, goroutineID()))
+	return c.Core.Write(entry, fields)
+}
+
+// goroutineID returns the ID of the current goroutine, parsed from the "goroutine 42 [...]"
+// header of its stack trace. Go doesn't expose it otherwise, on purpose, so it's only meant
+// for debugging.
+func goroutineID() uint64 {
+	var buf [64]byte
+	stack := buf[:runtime.Stack(buf[:], false)]
+
+	const header = "goroutine "
+	if len(stack) < len(header) {
+		return 0
+	}
+
+	var id uint64
+	for _, c := range stack[len(header):] {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	return id
+}
@@ -0,0 +1,117 @@
+package log
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// ActiveGzipFlushSize is the amount of compressed data buffered in memory before it's
+// written to an active log file when CompressActive is enabled.
+const ActiveGzipFlushSize = 64 * 1024
+
+// gzipFile compresses the entries written to an active log file with gzip.
+//
+// Compressed data is buffered and written to the file as complete gzip members, either
+// when ActiveGzipFlushSize is reached or on flush. Since lumberjack only rotates between
+// writes, every file always consists of complete members, which gzip readers decode as
+// a single stream.
+type gzipFile struct {
+	mu      sync.Mutex
+	file    io.Writer
+	buf     bytes.Buffer
+	zw      *gzip.Writer
+	pending bool // whether entries were written since the last flush
+}
+
+func newGzipFile(file io.Writer) *gzipFile {
+	g := &gzipFile{file: file}
+	g.zw = gzip.NewWriter(&g.buf)
+	return g
+}
+
+// Write compresses p into the current gzip member.
+func (g *gzipFile) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, err := g.zw.Write(p); err != nil {
+		return 0, err
+	}
+	g.pending = true
+
+	if g.buf.Len() >= ActiveGzipFlushSize {
+		if err := g.flushLocked(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush finalizes the current gzip member and writes it to the file.
+func (g *gzipFile) Flush() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.pending {
+		return nil
+	}
+	return g.flushLocked()
+}
+
+// flushLocked must be called with g.mu held. On failure, the current member is dropped.
+func (g *gzipFile) flushLocked() error {
+	defer func() {
+		g.buf.Reset()
+		g.zw.Reset(&g.buf)
+		g.pending = false
+	}()
+
+	if err := g.zw.Close(); err != nil {
+		return err
+	}
+	_, err := g.file.Write(g.buf.Bytes())
+	return err
+}
+
+// writeFile writes data to the log file, through its gzip stream if CompressActive is enabled.
+func (l *Log) writeFile(file logFile, data []byte) (int, error) {
+	if !l.opts.CompressActive {
+		return file.Write(data)
+	}
+	return l.activeGzip(file).Write(data)
+}
+
+// activeGzip returns the gzip stream of the log file, creating it if needed.
+func (l *Log) activeGzip(file logFile) *gzipFile {
+	l.activeMu.Lock()
+	defer l.activeMu.Unlock()
+
+	if l.active == nil {
+		l.active = make(map[logFile]*gzipFile)
+	}
+
+	g, ok := l.active[file]
+	if !ok {
+		g = newGzipFile(file)
+		l.active[file] = g
+	}
+	return g
+}
+
+// flushActive writes the pending compressed data of the log file, if any. With release,
+// the gzip stream is discarded as well, for files that are no longer written to.
+func (l *Log) flushActive(file logFile, release bool) error {
+	l.activeMu.Lock()
+	g, ok := l.active[file]
+	if ok && release {
+		delete(l.active, file)
+	}
+	l.activeMu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return g.Flush()
+}
@@ -0,0 +1,101 @@
+package log
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readGzipFile decompresses the whole gzip file at path.
+func readGzipFile(t *testing.T, path string) string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestCompressActive(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_compress_active"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithFilename("app").
+		WithDisableSplitError(false).
+		WithConsoleOutput(false).
+		WithCompressActive(true))
+	defer logger.Close()
+
+	logger.Info("first compressed message")
+	logger.Error("compressed error message")
+	require.NoError(t, logger.Sync())
+
+	path := filepath.Join(testDir, logger.generateFileName(logger.currDate, false))
+	asrt.True(strings.HasSuffix(path, ".log.gz"))
+
+	content := readGzipFile(t, path)
+	asrt.Contains(content, "first compressed message")
+	asrt.Contains(content, "compressed error message")
+
+	errContent := readGzipFile(t, filepath.Join(testDir, logger.generateFileName(logger.currDate, true)))
+	asrt.Contains(errContent, "compressed error message")
+
+	// Writing after Sync appends a new gzip member, the file stays valid
+	logger.Info("second compressed message")
+	require.NoError(t, logger.Sync())
+
+	content = readGzipFile(t, path)
+	asrt.Contains(content, "first compressed message")
+	asrt.Contains(content, "second compressed message")
+}
+
+func TestCompressActive_FlushSize(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_compress_active_flush"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(false).
+		WithCompressActive(true))
+	defer logger.Close()
+
+	logger.Info("entry start")
+	path := filepath.Join(testDir, logger.generateFileName(logger.currDate, false))
+	before, err := os.Stat(path)
+	require.NoError(t, err)
+
+	// Poorly compressible entries exceed the buffer and reach the file before Sync
+	random := make([]byte, 64)
+	for i := range 5000 {
+		_, _ = rand.Read(random)
+		logger.Infof("entry %d %x", i, random)
+	}
+	after, err := os.Stat(path)
+	require.NoError(t, err)
+	asrt.Greater(after.Size(), before.Size())
+
+	require.NoError(t, logger.Sync())
+	content := readGzipFile(t, path)
+	asrt.Contains(content, "entry start")
+	asrt.Contains(content, "entry 4999 ")
+}
@@ -0,0 +1,42 @@
+package log
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kydenul/log/internal"
+)
+
+// FileHeaderKey is the key set to true in the header line of log files written with
+// FileHeader, telling it apart from the entries.
+const FileHeaderKey = "log_header"
+
+// fileHeader returns the header line of new log files: the logger fields, then the
+// creation time and the format of the file, which take precedence over fields with the
+// same key. CSV files get the names of the columns instead.
+func (l *Log) fileHeader() ([]byte, error) {
+	if l.opts.fileFormat() == FormatCSV {
+		return []byte(strings.Join(internal.CSVColumns, ",") + "\n"), nil
+	}
+
+	header := make(map[string]any, len(l.opts.Fields)+3)
+	maps.Copy(header, l.opts.Fields)
+	header[FileHeaderKey] = true
+	header["created_at"] = time.Now().Format(time.RFC3339)
+	header["format"] = l.opts.fileFormat()
+
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(l.prefix), append(data, '\n')...), nil
+}
+
+// isEmptyFile reports whether the file at path doesn't exist yet or is empty.
+func isEmptyFile(path string) bool {
+	info, err := os.Stat(path)
+	return err != nil || info.Size() == 0
+}
@@ -0,0 +1,87 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kydenul/log/logtest"
+)
+
+func TestFileHeader(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_file_header"
+	defer os.RemoveAll(testDir)
+
+	newLogger := func() *Log {
+		return NewBuilder().
+			Directory(testDir).
+			Prefix("APP_").
+			Format(FormatJSON).
+			ConsoleOutput(false).
+			DisableSplitError(false).
+			Fields(map[string]any{"service": "checkout", "version": "1.2.3", "format": "ignored"}).
+			FileHeader(true).
+			Isolated(true).
+			Build()
+	}
+
+	logger := newLogger()
+	logger.Info("first")
+	logger.Error("second")
+	logger.Info("third")
+	require.NoError(t, logger.Sync())
+
+	// Appending to the existing files doesn't add another header
+	logger = newLogger()
+	logger.Info("fourth")
+	require.NoError(t, logger.Sync())
+
+	for _, errorLog := range []bool{false, true} {
+		content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, errorLog)))
+		require.NoError(t, err)
+
+		headers := 0
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		for i, line := range lines {
+			entry, err := logtest.ParseJSONLog(line, "APP_")
+			require.NoError(t, err)
+			if entry[FileHeaderKey] != true {
+				continue
+			}
+
+			headers++
+			asrt.Zero(i, "the header must be the first line")
+			asrt.Equal("checkout", entry["service"])
+			asrt.Equal("1.2.3", entry["version"])
+			asrt.Equal(FormatJSON, entry["format"])
+			asrt.NotEmpty(entry["created_at"])
+			asrt.NotContains(entry, "msg")
+		}
+		asrt.Equal(1, headers, "error log: %v, content: %s", errorLog, content)
+	}
+}
+
+func TestFileHeader_Disabled(t *testing.T) {
+	t.Parallel()
+
+	testDir := "./logs/test_logs_file_header_disabled"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(false).
+		WithIsolated(true))
+	logger.Info("no header")
+	require.NoError(t, logger.Sync())
+
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), FileHeaderKey)
+}
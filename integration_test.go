@@ -15,6 +15,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/kydenul/log/logtest"
 )
 
 // TestYAMLConfigurationIntegration tests YAML configuration functionality (now powered by Viper)
@@ -900,18 +902,12 @@ flush_interval: 1s
 			jsonLogCount := 0
 			for _, line := range lines {
 				line = strings.TrimSpace(line)
-				if line == "" || strings.HasPrefix(line, "#") {
+				if line == "" {
 					continue
 				}
 
-				// Remove the prefix (e.g., "ZIWI_") to get the JSON part
-				if strings.Contains(line, "{") {
-					jsonStart := strings.Index(line, "{")
-					jsonPart := line[jsonStart:]
-					var logEntry map[string]any
-					if json.Unmarshal([]byte(jsonPart), &logEntry) == nil {
-						jsonLogCount++
-					}
+				if _, err := logtest.ParseJSONLog(line, logger.opts.Prefix); err == nil {
+					jsonLogCount++
 				}
 			}
 			assert.True(t, jsonLogCount > 0, "Should contain valid JSON log entries")
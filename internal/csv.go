@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// CSVColumns are the columns of the rows written by the "csv" format, in this order.
+var CSVColumns = []string{"time", "level", "msg", "caller", "fields"}
+
+var csvPool = buffer.NewPool()
+
+// csvEncoder encodes entries as CSV rows with the CSVColumns. The fields, including the
+// fields added to the logger, the logger name and the stacktrace, are encoded as a JSON
+// object in the "fields" column, empty if there are none.
+type csvEncoder struct {
+	zapcore.Encoder // JSON encoder of the fields only
+	cfg             zapcore.EncoderConfig
+}
+
+func newCSVEncoder(cfg zapcore.EncoderConfig) *csvEncoder {
+	fieldsCfg := cfg
+	fieldsCfg.TimeKey = zapcore.OmitKey
+	fieldsCfg.LevelKey = zapcore.OmitKey
+	fieldsCfg.MessageKey = zapcore.OmitKey
+	fieldsCfg.CallerKey = zapcore.OmitKey
+	fieldsCfg.FunctionKey = zapcore.OmitKey
+
+	return &csvEncoder{Encoder: zapcore.NewJSONEncoder(fieldsCfg), cfg: cfg}
+}
+
+func (e *csvEncoder) Clone() zapcore.Encoder {
+	return &csvEncoder{Encoder: e.Encoder.Clone(), cfg: e.cfg}
+}
+
+// EncodeEntry encodes the entry as a CSV row, quoting the values as needed.
+func (e *csvEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	fieldsBuf, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return nil, err
+	}
+	fieldsJSON := strings.TrimSuffix(fieldsBuf.String(), e.lineEnding())
+	fieldsBuf.Free()
+	if fieldsJSON == "{}" {
+		fieldsJSON = ""
+	}
+
+	var timeCol, levelCol, callerCol stringArrayEncoder
+	e.cfg.EncodeTime(entry.Time, &timeCol)
+	e.cfg.EncodeLevel(entry.Level, &levelCol)
+	if entry.Caller.Defined {
+		e.cfg.EncodeCaller(entry.Caller, &callerCol)
+	}
+
+	buf := csvPool.Get()
+	w := csv.NewWriter(buf)
+	_ = w.Write([]string{timeCol.String(), levelCol.String(), entry.Message, callerCol.String(), fieldsJSON})
+	w.Flush()
+	if err := w.Error(); err != nil {
+		buf.Free()
+		return nil, err
+	}
+	return buf, nil
+}
+
+// lineEnding returns the line ending of the fields JSON encoder.
+func (e *csvEncoder) lineEnding() string {
+	if e.cfg.LineEnding == "" {
+		return zapcore.DefaultLineEnding
+	}
+	return e.cfg.LineEnding
+}
+
+// stringArrayEncoder collects the values appended by the time, level and caller encoders
+// as a single column value.
+type stringArrayEncoder struct {
+	values []string
+}
+
+func (s *stringArrayEncoder) String() string { return strings.Join(s.values, " ") }
+
+func (s *stringArrayEncoder) AppendBool(v bool)         { s.AppendString(strconv.FormatBool(v)) }
+func (s *stringArrayEncoder) AppendByteString(v []byte) { s.AppendString(string(v)) }
+func (s *stringArrayEncoder) AppendComplex128(v complex128) {
+	s.AppendString(strconv.FormatComplex(v, 'g', -1, 128))
+}
+func (s *stringArrayEncoder) AppendComplex64(v complex64) { s.AppendComplex128(complex128(v)) }
+func (s *stringArrayEncoder) AppendFloat64(v float64) {
+	s.AppendString(strconv.FormatFloat(v, 'f', -1, 64))
+}
+func (s *stringArrayEncoder) AppendFloat32(v float32) {
+	s.AppendString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+}
+func (s *stringArrayEncoder) AppendInt(v int)         { s.AppendInt64(int64(v)) }
+func (s *stringArrayEncoder) AppendInt64(v int64)     { s.AppendString(strconv.FormatInt(v, 10)) }
+func (s *stringArrayEncoder) AppendInt32(v int32)     { s.AppendInt64(int64(v)) }
+func (s *stringArrayEncoder) AppendInt16(v int16)     { s.AppendInt64(int64(v)) }
+func (s *stringArrayEncoder) AppendInt8(v int8)       { s.AppendInt64(int64(v)) }
+func (s *stringArrayEncoder) AppendString(v string)   { s.values = append(s.values, v) }
+func (s *stringArrayEncoder) AppendUint(v uint)       { s.AppendUint64(uint64(v)) }
+func (s *stringArrayEncoder) AppendUint64(v uint64)   { s.AppendString(strconv.FormatUint(v, 10)) }
+func (s *stringArrayEncoder) AppendUint32(v uint32)   { s.AppendUint64(uint64(v)) }
+func (s *stringArrayEncoder) AppendUint16(v uint16)   { s.AppendUint64(uint64(v)) }
+func (s *stringArrayEncoder) AppendUint8(v uint8)     { s.AppendUint64(uint64(v)) }
+func (s *stringArrayEncoder) AppendUintptr(v uintptr) { s.AppendUint64(uint64(v)) }
@@ -1,35 +1,161 @@
 package internal
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/zapcore"
 )
 
-// autoSyncSetup ensures that SetupAutoSync is only called once.
-var autoSyncSetup sync.Once
+var (
+	// autoSyncSetup ensures that SetupAutoSync is only called once.
+	autoSyncSetup sync.Once
+
+	// sighupHandlers counts the SIGHUP handlers registered by the application.
+	// While there is one, SIGHUP no longer terminates the process.
+	sighupHandlers atomic.Int32
+)
+
+// EncoderConfig configures the encoders created by NewBaseEncoder.
+type EncoderConfig struct {
+	TimeLayout  string // Layout of the timestamps with the "layout" time encoder
+	TimeEncoder string // Name of the time encoder, see TimeEncoders; empty means "layout"
+	PrettyJSON  bool   // Whether to indent JSON entries over multiple lines
+
+	DurationEncoder string // Name of the duration encoder, see DurationEncoders; empty means "seconds"
+
+	FieldOrder []string // Keys of the fields rendered first by console encoders, in this order
+}
+
+// TimeEncoders maps the names of the supported time encoders to their encoder. The
+// "layout" encoder, using EncoderConfig.TimeLayout, is not listed.
+var TimeEncoders = map[string]zapcore.TimeEncoder{
+	"epoch":       zapcore.EpochTimeEncoder,       // Seconds since the Unix epoch, as a float
+	"epochmillis": zapcore.EpochMillisTimeEncoder, // Milliseconds since the Unix epoch, as a float
+	"rfc3339":     zapcore.RFC3339TimeEncoder,     // 2006-01-02T15:04:05Z07:00
+	"rfc3339nano": zapcore.RFC3339NanoTimeEncoder, // 2006-01-02T15:04:05.999999999Z07:00
+	"iso8601":     zapcore.ISO8601TimeEncoder,     // 2006-01-02T15:04:05.000Z0700
+}
+
+// DurationEncoders maps the names of the supported duration encoders to their encoder.
+var DurationEncoders = map[string]zapcore.DurationEncoder{
+	"string":  zapcore.StringDurationEncoder,  // 1.5s
+	"seconds": zapcore.SecondsDurationEncoder, // 1.5, as a float
+	"millis":  zapcore.MillisDurationEncoder,  // 1500, as a float
+	"nanos":   zapcore.NanosDurationEncoder,   // 1500000000, as an integer
+}
 
 // NewBaseEncoder creates a new encoder.
-func NewBaseEncoder(format, timeLayout string) zapcore.Encoder {
+func NewBaseEncoder(format string, cfg EncoderConfig) zapcore.Encoder {
 	encoderConfig := zap.NewProductionEncoderConfig()
-	encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(timeLayout)
+	encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(cfg.TimeLayout)
+	if enc, ok := TimeEncoders[cfg.TimeEncoder]; ok {
+		encoderConfig.EncodeTime = enc
+	}
+	if enc, ok := DurationEncoders[cfg.DurationEncoder]; ok {
+		encoderConfig.EncodeDuration = enc
+	}
 
+	if strings.ToLower(format) == "csv" {
+		return newCSVEncoder(encoderConfig)
+	}
 	if strings.ToLower(format) == "json" {
+		if cfg.PrettyJSON {
+			return &prettyJSONEncoder{Encoder: zapcore.NewJSONEncoder(encoderConfig)}
+		}
 		return zapcore.NewJSONEncoder(encoderConfig)
 	}
+	if len(cfg.FieldOrder) > 0 {
+		return &orderedEncoder{Encoder: zapcore.NewConsoleEncoder(encoderConfig), order: cfg.FieldOrder}
+	}
 	return zapcore.NewConsoleEncoder(encoderConfig)
 }
 
+// orderedEncoder moves the fields with the keys of order to the front of the fields of
+// every entry, in this order, keeping the other fields in their original order.
+type orderedEncoder struct {
+	zapcore.Encoder
+	order []string
+}
+
+func (e *orderedEncoder) Clone() zapcore.Encoder {
+	return &orderedEncoder{Encoder: e.Encoder.Clone(), order: e.order}
+}
+
+// EncodeEntry encodes the entry with the fields reordered.
+func (e *orderedEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	return e.Encoder.EncodeEntry(entry, orderFields(fields, e.order))
+}
+
+// orderFields returns fields with the fields with the keys of order first, in this order,
+// followed by the other fields in their original order. Fields after a namespace are left
+// in place, since moving them would change their nesting.
+func orderFields(fields []zapcore.Field, order []string) []zapcore.Field {
+	end := len(fields)
+	for i, field := range fields {
+		if field.Type == zapcore.NamespaceType {
+			end = i
+			break
+		}
+	}
+
+	ordered := make([]zapcore.Field, 0, len(fields))
+	for _, key := range order {
+		for _, field := range fields[:end] {
+			if field.Key == key {
+				ordered = append(ordered, field)
+			}
+		}
+	}
+	for _, field := range fields[:end] {
+		if !slices.Contains(order, field.Key) {
+			ordered = append(ordered, field)
+		}
+	}
+	return append(ordered, fields[end:]...)
+}
+
+// prettyJSONEncoder indents the entries of a JSON encoder.
+type prettyJSONEncoder struct {
+	zapcore.Encoder
+}
+
+func (e *prettyJSONEncoder) Clone() zapcore.Encoder {
+	return &prettyJSONEncoder{Encoder: e.Encoder.Clone()}
+}
+
+// EncodeEntry encodes the entry as JSON, indented with two spaces.
+func (e *prettyJSONEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimSuffix(buf.Bytes(), []byte("\n")), "", "  "); err != nil {
+		return buf, nil //nolint:nilerr // Keep the compact entry rather than losing it
+	}
+
+	buf.Reset()
+	_, _ = buf.Write(indented.Bytes())
+	buf.AppendByte('\n')
+	return buf, nil
+}
+
 // SetupAutoSync sets up automatic synchronization of logs.
-func SetupAutoSync(syncFunc func()) {
+func SetupAutoSync(syncFunc func() error) {
 	autoSyncSetup.Do(func() {
 		// Create signal channel
 		signalChan := make(chan os.Signal, 1)
@@ -39,22 +165,40 @@ func SetupAutoSync(syncFunc func()) {
 
 		// Start a goroutine to handle signals
 		go func() {
-			<-signalChan
+			for sig := range signalChan {
+				// SIGHUP is handled by the application, e.g. to rotate log files
+				if sig == syscall.SIGHUP && sighupHandlers.Load() > 0 {
+					continue
+				}
 
-			// Call Sync() function when signal received
-			fmt.Println("Received termination signal, flushing logs...")
-			syncFunc()
+				// Call Sync() function when signal received
+				fmt.Println("Received termination signal, flushing logs...")
+				if err := syncFunc(); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to flush logs: %v\n", err)
+				}
 
-			// Stop signal channel
-			signal.Stop(signalChan)
+				// Stop signal channel
+				signal.Stop(signalChan)
 
-			// Send signal to default signal handler
-			p, _ := os.FindProcess(os.Getpid())
-			_ = p.Signal(syscall.SIGTERM)
+				// Send signal to default signal handler
+				p, _ := os.FindProcess(os.Getpid())
+				_ = p.Signal(syscall.SIGTERM)
+				return
+			}
 		}()
 	})
 }
 
+// AddSIGHUPHandler records that the application handles SIGHUP, so that the handler
+// installed by SetupAutoSync ignores it instead of terminating the process.
+// The returned function removes the record.
+func AddSIGHUPHandler() (remove func()) {
+	sighupHandlers.Add(1)
+
+	var once sync.Once
+	return func() { once.Do(func() { sighupHandlers.Add(-1) }) }
+}
+
 // ValidateTimeLayout validates the time layout string.
 // It returns an error if the layout string is invalid.
 func ValidateTimeLayout(layout string) error {
@@ -1,9 +1,15 @@
 package internal
 
 import (
+	"encoding/csv"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func Test_validateTimeLayout(t *testing.T) {
@@ -13,3 +19,113 @@ func Test_validateTimeLayout(t *testing.T) {
 	assert.Error(ValidateTimeLayout(""))
 	assert.Error(ValidateTimeLayout("2006-01-02 15:04:0563:22"))
 }
+
+func Test_AddSIGHUPHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	remove := AddSIGHUPHandler()
+	assert.Equal(int32(1), sighupHandlers.Load())
+
+	removeOther := AddSIGHUPHandler()
+	assert.Equal(int32(2), sighupHandlers.Load())
+
+	// Removing is idempotent
+	remove()
+	remove()
+	assert.Equal(int32(1), sighupHandlers.Load())
+
+	removeOther()
+	assert.Equal(int32(0), sighupHandlers.Load())
+}
+
+func Test_NewBaseEncoder_PrettyJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC), Message: "msg"}
+	fields := []zapcore.Field{zap.Int("id", 1)}
+
+	buf, err := NewBaseEncoder("json", EncoderConfig{TimeLayout: time.DateOnly}).EncodeEntry(entry, fields)
+	assert.NoError(err)
+	assert.Equal(`{"level":"info","ts":"2025-07-20","msg":"msg","id":1}`+"\n", buf.String())
+
+	// Clones keep the indentation
+	pretty := EncoderConfig{TimeLayout: time.DateOnly, PrettyJSON: true}
+	buf, err = NewBaseEncoder("json", pretty).Clone().EncodeEntry(entry, fields)
+	assert.NoError(err)
+	assert.Equal("{\n  \"level\": \"info\",\n  \"ts\": \"2025-07-20\",\n  \"msg\": \"msg\",\n  \"id\": 1\n}\n",
+		buf.String())
+
+	// The console format is never indented
+	buf, err = NewBaseEncoder("console", pretty).EncodeEntry(entry, fields)
+	assert.NoError(err)
+	assert.Equal("2025-07-20\tinfo\tmsg\t{\"id\": 1}\n", buf.String())
+}
+
+func Test_NewBaseEncoder_FieldOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC), Message: "msg"}
+	fields := []zapcore.Field{zap.Int("n", 1), zap.String("user_id", "u"), zap.String("request_id", "r")}
+	cfg := EncoderConfig{TimeLayout: time.DateOnly, FieldOrder: []string{"request_id", "user_id", "missing"}}
+
+	buf, err := NewBaseEncoder("console", cfg).Clone().EncodeEntry(entry, fields)
+	assert.NoError(err)
+	assert.Equal("2025-07-20\tinfo\tmsg\t{\"request_id\": \"r\", \"user_id\": \"u\", \"n\": 1}\n", buf.String())
+
+	// Fields after a namespace keep their nesting
+	fields = []zapcore.Field{zap.Int("n", 1), zap.Namespace("ns"), zap.String("request_id", "r")}
+	buf, err = NewBaseEncoder("console", cfg).EncodeEntry(entry, fields)
+	assert.NoError(err)
+	assert.Equal("2025-07-20\tinfo\tmsg\t{\"n\": 1, \"ns\": {\"request_id\": \"r\"}}\n", buf.String())
+
+	// The JSON format is left in the order of the fields
+	buf, err = NewBaseEncoder("json", cfg).EncodeEntry(entry,
+		[]zapcore.Field{zap.Int("n", 1), zap.String("request_id", "r")})
+	assert.NoError(err)
+	assert.Equal(`{"level":"info","ts":"2025-07-20","msg":"msg","n":1,"request_id":"r"}`+"\n", buf.String())
+}
+
+func Test_NewBaseEncoder_CSV(t *testing.T) {
+	assert := assert.New(t)
+
+	entry := zapcore.Entry{
+		Level:   zapcore.WarnLevel,
+		Time:    time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC),
+		Message: `say "hi", then` + "\nleave",
+		Caller:  zapcore.NewEntryCaller(0, "/src/app/main.go", 42, true),
+	}
+	enc := NewBaseEncoder("csv", EncoderConfig{TimeLayout: time.DateOnly})
+	enc.AddString("service", "api") // Fields of the logger
+
+	buf, err := enc.Clone().EncodeEntry(entry, []zapcore.Field{zap.String("quote", `a "b"`), zap.Int("n", 1)})
+	assert.NoError(err)
+	assert.Equal(`2025-07-20,warn,"say ""hi"", then`+"\n"+
+		`leave",app/main.go:42,"{""service"":""api"",""quote"":""a \""b\"""",""n"":1}"`+"\n",
+		buf.String())
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	assert.NoError(err)
+	assert.Equal([][]string{{
+		"2025-07-20", "warn", `say "hi", then` + "\nleave", "app/main.go:42",
+		`{"service":"api","quote":"a \"b\"","n":1}`,
+	}}, records)
+
+	// Without caller and fields, the columns are empty
+	entry.Caller = zapcore.EntryCaller{}
+	buf, err = NewBaseEncoder("csv", EncoderConfig{TimeLayout: time.DateOnly}).EncodeEntry(entry, nil)
+	assert.NoError(err)
+	records, err = csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	assert.NoError(err)
+	assert.Len(records[0], len(CSVColumns))
+	assert.Empty(records[0][3])
+	assert.Empty(records[0][4])
+
+	// Errors and stacktraces go to the fields
+	entry.Stack = "main.main()"
+	buf, err = NewBaseEncoder("csv", EncoderConfig{TimeLayout: time.DateOnly}).EncodeEntry(entry,
+		[]zapcore.Field{zap.Error(errors.New("boom"))})
+	assert.NoError(err)
+	records, err = csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	assert.NoError(err)
+	assert.Equal(`{"error":"boom","stacktrace":"main.main()"}`, records[0][4])
+}
@@ -1,27 +1,33 @@
 package log
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
 	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/kydenul/log/internal"
 	"github.com/spf13/viper"
 )
 
 const (
-	MaxRetries = 3                     // Maximum retries for file write operations
-	BriefDelay = time.Millisecond * 10 // Brief delay before retry
+	MaxRetries = 3                     // Default attempts of file write operations, see Options.WriteMaxRetries
+	BriefDelay = time.Millisecond * 10 // Default delay before retry, see Options.WriteRetryDelay
 )
 
 // discardWriter is a writer that discards all data written to it
@@ -34,15 +40,9 @@ func (discardWriter) Write(p []byte) (int, error) {
 var (
 	// Global logger instance using atomic.Value for lock-free access
 	defaultLogger atomic.Value // *ZiwiLog
-	// Log prefix
-	logPrefix string
 
-	// Buffer pool to reduce memory allocations
-	bufferPool = sync.Pool{
-		New: func() any {
-			return &buffer.Buffer{}
-		},
-	}
+	// Loggers synced on exit in addition to the default logger, see RegisterAutoSync
+	autoSynced sync.Map // map[*Log]struct{}
 )
 
 // Initialize global logger instance
@@ -50,7 +50,7 @@ func init() {
 	logger := NewLog(NewOptions())
 	defaultLogger.Store(logger)
 
-	internal.SetupAutoSync(logger.Sync)
+	internal.SetupAutoSync(syncOnExit)
 }
 
 // Preset represents a predefined configuration set for different environments
@@ -83,17 +83,49 @@ type Log struct {
 	zapcore.Encoder
 
 	log       *zap.Logger
-	logDir    string // log file directory
-	file      *lumberjack.Logger
-	errFile   *lumberjack.Logger
+	sugar     *zap.SugaredLogger // cached sugared view of log, shared by all sugar methods
+	prefix    string             // prepended to every encoded entry of this instance
+	level     zap.AtomicLevel    // runtime-adjustable log level
+	logDir    string             // log file directory
+	file      logFile
+	errFile   logFile
+	fatalFile logFile
 	currDate  string // current date
 	dateCheck int64  // atomic timestamp for date checking optimization
 	opts      *Options
 	mu        sync.RWMutex // protects file operations
+	released  bool         // whether the files are released by Close, protected by mu
+
+	compressor  *backupCompressor     // zstd compressor of rotated files, nil unless zstd is used
+	active      map[logFile]*gzipFile // gzip streams of the active files, if CompressActive
+	activeMu    sync.Mutex            // protects active
+	sinks       []io.Closer           // extra outputs (syslog, remote), closed by Close
+	remote      *remoteWriter         // remote output, if Options.RemoteAddr, also in sinks
+	disableFile bool                  // whether file output is replaced by syslog output
+	errToStderr bool                  // whether errors are written to stderr by EncodeEntry
+	tees        []*Log                // loggers combined by Tee, synced by Sync
+	parent      *Log                  // logger owning the files, for loggers created by WithOptions
+	fields      []zapcore.Field       // fields added by WithField and WithFields, by key
+	fieldsBase  *zap.Logger           // logger the fields are added to, nil without fields
+	everyState  sync.Map              // throttling state of the Every methods, by call site
+	scopes      []*zapcore.Level      // levels of the active WithLevelScope scopes, oldest first
+	scopeBase   zapcore.Level         // level before the first active scope
+	scopesMu    sync.Mutex            // protects scopes and scopeBase
+	queue       chan queuedWrite      // entries written to the files in the background, if AsyncQueueSize
+	queueState  asyncQueue            // closing state of queue
+	stats       logStats              // counters returned by Stats
+	extraMu     sync.Mutex            // serializes the writes to Options.ExtraWriters
+
+	deadletter   *os.File   // Options.DeadletterFile, opened on the first failed write
+	deadletterMu sync.Mutex // protects deadletter
+
+	closed   atomic.Bool                    // set by Close, the entries logged afterwards are dropped
+	sampling atomic.Pointer[sampleSettings] // sampling in effect, nil if disabled, see setSampling
 }
 
 // NewLog creates a new logger instance and sets it as the global default logger.
 // This allows both instance-based calls (logger.Info()) and global calls (log.Info()).
+// Unless Options.Isolated is set, it also redirects the standard library logger to it.
 //
 // Returns:
 //
@@ -106,47 +138,118 @@ func NewLog(opts *Options) *Log {
 
 	// 2. Validate options once and fix invalid values
 	if err := opts.Validate(); err != nil {
-		fmt.Fprintf(os.Stderr, "Invalid logger options: %v. Using fallback values.\n", err)
+		fmt.Fprintf(opts.internalErrors(), "Invalid logger options: %v. Using fallback values.\n", err)
 
 		// Fix invalid options with defaults
 		if opts.Directory == "" {
 			opts.Directory = DefaultDirectory
 		}
+		if opts.MaxFilenameLength < 0 || opts.MaxFilenameLength > maxFilenameLengthLimit {
+			opts.MaxFilenameLength = DefaultMaxFilenameLength
+		}
+		if !isValidRelativeTo(opts.RelativeTo) {
+			opts.RelativeTo = DefaultRelativeTo
+		}
+		if opts.FilenamePattern != "" && validateFilenamePattern(opts.FilenamePattern) != nil {
+			opts.FilenamePattern = ""
+		}
+		if !isValidTimeEncoder(opts.TimeEncoder) {
+			opts.TimeEncoder = DefaultTimeEncoder
+		}
+		if _, ok := internal.DurationEncoders[opts.DurationEncoder]; !ok {
+			opts.DurationEncoder = DefaultDurationEncoder
+		}
 		if opts.Level == "" || !isValidLevel(opts.Level) {
 			opts.Level = DefaultLevel.String()
 		}
-		if opts.Format != DefaultFormat && opts.Format != "json" {
+		if !isValidFormat(opts.Format) {
 			opts.Format = DefaultFormat
 		}
+		if !isValidFormat(opts.ConsoleFormat) {
+			opts.ConsoleFormat = ""
+		}
+		if !isValidFormat(opts.FileFormat) {
+			opts.FileFormat = ""
+		}
+		if !isValidLevel(opts.ConsoleLevel) {
+			opts.ConsoleLevel = ""
+		}
+		if !isValidLevel(opts.FileLevel) {
+			opts.FileLevel = ""
+		}
+		if opts.StacktraceLevel != "" && !isValidLevelString(opts.StacktraceLevel) {
+			opts.StacktraceLevel = DefaultStacktraceLevel.String()
+		}
+		if opts.ExtraCallerSkip < 0 {
+			opts.ExtraCallerSkip = DefaultExtraCallerSkip
+		}
+		if opts.MaxFieldLength < 0 {
+			opts.MaxFieldLength = DefaultMaxFieldLength
+		}
 		if opts.MaxSize <= 0 {
 			opts.MaxSize = DefaultMaxSize
 		}
 		if opts.MaxBackups <= 0 {
 			opts.MaxBackups = DefaultMaxBackups
 		}
+		if opts.DirMode&^os.ModePerm != 0 {
+			opts.DirMode = DefaultDirMode
+		}
+		if opts.FileMode&^os.ModePerm != 0 {
+			opts.FileMode = DefaultFileMode
+		}
+		if opts.WriteTimeout < 0 {
+			opts.WriteTimeout = DefaultWriteTimeout
+		}
+		if opts.WriteMaxRetries < 0 {
+			opts.WriteMaxRetries = DefaultWriteMaxRetries
+		}
+		if opts.WriteRetryDelay < 0 {
+			opts.WriteRetryDelay = DefaultWriteRetryDelay
+		}
+		if opts.AsyncQueueSize < 0 {
+			opts.AsyncQueueSize = DefaultAsyncQueueSize
+		}
+		if opts.SampleTick < 0 {
+			opts.SampleTick = DefaultSampleTick
+		}
 	}
 
-	// 3. Set log prefix
-	logPrefix = opts.Prefix
-
-	// 4. Set time layout, Default time layout
+	// 3. Set time layout, Default time layout
 	timeLayout := DefaultTimeLayout
 	if err := internal.ValidateTimeLayout(opts.TimeLayout); err == nil {
 		timeLayout = opts.TimeLayout
 	} else {
-		fmt.Fprintf(os.Stderr,
+		fmt.Fprintf(opts.internalErrors(),
 			"Invalid time layout '%s', using default: %s\n", opts.TimeLayout, DefaultTimeLayout)
 	}
 
-	// 5. Create our custom ZiwiLog with the base encoder
+	encCfg := internal.EncoderConfig{
+		TimeLayout:      timeLayout,
+		TimeEncoder:     opts.TimeEncoder,
+		DurationEncoder: opts.DurationEncoder,
+		FieldOrder:      opts.FieldOrder,
+	}
+
+	// 4. Create our custom ZiwiLog with the base encoder
 	logger := &Log{
-		Encoder:   internal.NewBaseEncoder(opts.Format, timeLayout),
+		Encoder:   internal.NewBaseEncoder(opts.fileFormat(), encCfg),
+		prefix:    opts.Prefix,
 		opts:      opts,
-		logDir:    opts.Directory,
+		logDir:    opts.logDirectory(),
 		dateCheck: time.Now().Unix(),
 	}
 
-	// 6. Create the zap logger with our custom core, ZiwiLog encoder
+	// lumberjack only supports gzip, other algorithms are applied after rotation
+	if opts.compressAlgorithm() == CompressZstd {
+		logger.compressor = newBackupCompressor(opts.MaxSize, opts.MaxBackups, opts.internalErrors())
+	}
+
+	if opts.AsyncQueueSize > 0 {
+		logger.startQueue(opts.AsyncQueueSize)
+	}
+
+	// 5. Create the zap logger with our custom core, ZiwiLog encoder
 	zapLevel := DefaultLevel
 	_ = zapLevel.UnmarshalText([]byte(opts.Level))
 
@@ -158,40 +261,525 @@ func NewLog(opts *Options) *Log {
 		writeSyncer = zapcore.AddSync(&discardWriter{}) // Discard console output
 	}
 
-	core := zapcore.NewCore(
-		logger,      // Our custom encoder
-		writeSyncer, // Conditional output
-		zap.NewAtomicLevelAt(zapLevel),
-	)
+	logger.level = zap.NewAtomicLevelAt(zapLevel)
 
-	// Wrap with sampling core if enabled
-	if opts.EnableSampling {
-		core = zapcore.NewSamplerWithOptions(
-			core,
-			time.Second, // Sample per second
-			opts.SampleInitial,
-			opts.SampleThereafter,
+	// The console and the files follow Level unless they have a level of their own
+	consoleLevel := outputLevel(opts.ConsoleLevel, logger.level)
+	fileLevel := outputLevel(opts.FileLevel, logger.level)
+
+	// The console core is only needed if something is written to the console
+	consoleFormat := opts.consoleFormat()
+	splitConsole := (consoleFormat != opts.fileFormat() || opts.prettyConsole() ||
+		opts.ConsoleLevel != opts.FileLevel) && (opts.ConsoleOutput || opts.ErrorToStderr)
+
+	var core zapcore.Core
+	if !splitConsole {
+		logger.errToStderr = opts.ErrorToStderr
+		core = zapcore.NewCore(
+			logger,      // Our custom encoder
+			writeSyncer, // Conditional output
+			fileLevel,   // Adjustable at runtime via SetLevel, unless FileLevel is set
+		)
+	} else {
+		// Different formats need different encoders: our custom encoder only writes the
+		// files, and the console is written by a core of its own
+		consoleCfg := encCfg
+		consoleCfg.PrettyJSON = opts.PrettyJSON // Never applies to the files
+		console := &consoleEncoder{
+			Encoder:       internal.NewBaseEncoder(consoleFormat, consoleCfg),
+			prefix:        opts.Prefix,
+			errorToStderr: opts.ErrorToStderr,
+			onError:       logger.reportWriteError,
+		}
+		core = zapcore.NewTee(
+			zapcore.NewCore(logger, zapcore.AddSync(&discardWriter{}), fileLevel),
+			zapcore.NewCore(console, writeSyncer, consoleLevel),
 		)
 	}
 
-	log := zap.New(
-		core,
-		zap.AddStacktrace(zapcore.PanicLevel),
-		zap.AddCallerSkip(1),
-		zap.WithCaller(!opts.DisableCaller),
-	)
+	// Send to syslog as well if enabled, falling back to files only if it's unreachable
+	if opts.Syslog.Enabled {
+		syslogCore, closer, err := newSyslogCore(
+			opts.Syslog, opts.Prefix, internal.NewBaseEncoder(opts.Format, encCfg), logger.level)
+		if err != nil {
+			fmt.Fprintf(opts.internalErrors(), "Failed to set up syslog output: %v. Logging to files only.\n", err)
+		} else {
+			core = zapcore.NewTee(core, syslogCore)
+			logger.sinks = append(logger.sinks, closer)
+			logger.disableFile = opts.Syslog.Only
+		}
+	}
+
+	// Stream to a remote collector as well if configured. Entries are sent without
+	// the prefix, so JSON lines stay parseable by the collector.
+	if opts.RemoteAddr != "" {
+		remote := newRemoteWriter(opts.RemoteProtocol, opts.RemoteAddr,
+			logger.reportWriteError, logger.reportError)
+		core = zapcore.NewTee(core, zapcore.NewCore(
+			internal.NewBaseEncoder(opts.Format, encCfg), remote, logger.level))
+		logger.sinks = append(logger.sinks, remote)
+		logger.remote = remote
+	}
+
+	// Wrap with the sampling core, grouping entries by the custom key if any. It's set up even
+	// with sampling disabled, so sampling can be enabled later by setSampling.
+	logger.sampling.Store(newSampleSettings(opts.EnableSampling, opts.SampleKeyFunc,
+		opts.sampleTick(), opts.SampleInitial, opts.SampleThereafter))
+	core = newKeySampler(core, &logger.sampling, logger.samplingHook)
+
+	// Truncate oversized field values, including the global fields
+	if opts.MaxFieldLength > 0 {
+		core = &truncateCore{Core: core, maxLength: opts.MaxFieldLength}
+	}
+
+	// Flatten nested field values, before truncating the leaf values
+	if opts.FlattenNested {
+		core = &flattenCore{Core: core, separator: opts.flattenSeparator()}
+	}
 
-	// 7. Assign the zap logger to our ZiwiLog
+	// Tag the entries with the logging goroutine and the calling function
+	if opts.IncludeGoroutineID {
+		core = &goroutineCore{Core: core}
+	}
+	if opts.CallerWithFunction {
+		core = &callerFuncCore{Core: core}
+	}
+
+	// Add the fields set by WithGlobalFields
+	core = &globalFieldsCore{Core: core}
+
+	// Drop the entries once the logger is closed
+	core = &closedCore{Core: core, closed: &logger.closed}
+
+	log := zap.New(core, zapOptions(logger)...).With(contextFields(opts)...)
+
+	// 6. Assign the zap logger to our ZiwiLog
 	logger.log = log
-	zap.RedirectStdLog(logger.log)
+	logger.sugar = log.Sugar()
 
-	// 8. Set this logger as the global default logger
+	// 7. Set this logger as the global default logger, unless it's isolated
 	// This enables both logger.Info() and log.Info() usage patterns
-	ReplaceLogger(logger)
+	if !opts.Isolated {
+		zap.RedirectStdLog(logger.log)
+		ReplaceLogger(logger)
+	}
+
+	return logger
+}
+
+// NewLogStrict is like NewLog, but returns the errors of Options.Validate instead of
+// replacing the invalid options with fallback values, so fail-fast deployments catch
+// misconfigurations at startup rather than missing logs later. A nil opts is valid.
+//
+// Example Usage:
+//
+//	logger, err := log.NewLogStrict(opts)
+//	if err != nil {
+//	    return fmt.Errorf("invalid log configuration: %w", err)
+//	}
+func NewLogStrict(opts *Options) (*Log, error) {
+	if opts != nil {
+		if err := opts.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid logger options: %w", err)
+		}
+	}
+
+	return NewLog(opts), nil
+}
+
+// NewLogWithCore creates a new logger instance backed by the given zapcore.Core and sets it
+// as the global default logger, unless Options.Isolated is set. It is an escape hatch for
+// advanced users who compose their own cores (multi-output, custom sampling, network sinks)
+// instead of using the built-in file/console core.
+//
+// Prefix, caller, stacktrace and MaxFieldLength options are still applied; the prefix is
+// prepended to the entry message. Level, format, file, rotation, sampling and console output
+// options are ignored in this mode since the provided core is responsible for them.
+func NewLogWithCore(core zapcore.Core, opts *Options) *Log {
+	if opts == nil {
+		opts = NewOptions()
+	}
+
+	if core == nil {
+		core = zapcore.NewNopCore()
+	}
+
+	if opts.Prefix != "" {
+		core = &prefixCore{Core: core, prefix: opts.Prefix}
+	}
+	if opts.MaxFieldLength > 0 {
+		core = &truncateCore{Core: core, maxLength: opts.MaxFieldLength}
+	}
+	if opts.FlattenNested {
+		core = &flattenCore{Core: core, separator: opts.flattenSeparator()}
+	}
+	if opts.IncludeGoroutineID {
+		core = &goroutineCore{Core: core}
+	}
+	if opts.CallerWithFunction {
+		core = &callerFuncCore{Core: core}
+	}
+	core = &globalFieldsCore{Core: core}
+
+	logger := &Log{
+		Encoder: internal.NewBaseEncoder(opts.Format, internal.EncoderConfig{TimeLayout: DefaultTimeLayout}),
+		level:   zap.NewAtomicLevelAt(DefaultLevel),
+		opts:    opts,
+	}
+	core = &closedCore{Core: core, closed: &logger.closed}
+	logger.log = zap.New(core, zapOptions(logger)...).With(contextFields(opts)...)
+	logger.sugar = logger.log.Sugar()
+
+	if !opts.Isolated {
+		ReplaceLogger(logger)
+	}
+
+	return logger
+}
+
+// Tee creates a new logger writing every entry to all of the given loggers, and sets it as
+// the global default logger. Each entry goes through every logger's core independently, so
+// each keeps its own level, format, prefix and outputs, e.g. a local file logger and a remote
+// network logger. Sync flushes all of them.
+//
+// Caller, stacktrace and Isolated options are taken from the first logger. Nil loggers are
+// ignored.
+//
+// Example Usage:
+//
+//	local := log.NewBuilder().Directory("/var/log/app").Build()
+//	remote := log.NewBuilder().Level("warn").Format("json").Remote("tcp", "logstash:5000").Build()
+//	logger := log.Tee(local, remote)
+//	defer logger.Sync()
+func Tee(loggers ...*Log) *Log {
+	var (
+		cores []zapcore.Core
+		tees  []*Log
+	)
+	for _, l := range loggers {
+		if l != nil {
+			cores = append(cores, l.log.Core())
+			tees = append(tees, l)
+		}
+	}
+
+	opts := NewOptions()
+	if len(tees) > 0 {
+		opts = tees[0].opts
+	}
+
+	core := zapcore.NewTee(cores...)
+	logger := &Log{
+		Encoder: internal.NewBaseEncoder(opts.Format, internal.EncoderConfig{TimeLayout: DefaultTimeLayout}),
+		level:   zap.NewAtomicLevelAt(zapcore.LevelOf(core)), // Lowest level of the loggers
+		opts:    opts,
+		tees:    tees,
+	}
+	logger.log = zap.New(&closedCore{Core: core, closed: &logger.closed}, zapOptions(logger)...)
+	logger.sugar = logger.log.Sugar()
+
+	if !opts.Isolated {
+		ReplaceLogger(logger)
+	}
 
 	return logger
 }
 
+// WithOptions returns a new logger with the zap options applied, like zap.Logger.WithOptions,
+// e.g. to add fields, hooks or a core wrapper after construction. The new logger writes to
+// the same files and outputs, and shares the level; Sync, Rotate and Stats act on the files
+// of l. The receiver is left unchanged, and unlike NewLog, the new logger doesn't replace
+// the default logger.
+//
+// Example Usage:
+//
+//	reqLogger := logger.WithOptions(zap.Fields(zap.String("request_id", id)))
+func (l *Log) WithOptions(opts ...zap.Option) *Log {
+	if l.fieldsBase == nil {
+		return l.derive(l.log.WithOptions(opts...), nil, nil)
+	}
+
+	// Keep the fields of WithField replaceable by the children of the new logger
+	base := l.fieldsBase.WithOptions(opts...)
+	return l.derive(base.With(l.fields...), base, l.fields)
+}
+
+// WithField returns a child logger adding the field to every entry, like logrus's WithField.
+// A field of the same key added by an ancestor with WithField or WithFields is replaced
+// rather than repeated. The child logger writes to the same files like WithOptions.
+//
+// Example Usage:
+//
+//	reqLogger := logger.WithField("request_id", id)
+func (l *Log) WithField(key string, value any) *Log {
+	return l.withFields([]zapcore.Field{zap.Any(key, value)})
+}
+
+// WithFields returns a child logger adding the fields to every entry, like logrus's
+// WithFields, in the order of their sorted keys. Fields of the same keys added by an
+// ancestor with WithField or WithFields are replaced rather than repeated.
+func (l *Log) WithFields(fields map[string]any) *Log {
+	zapFields := make([]zapcore.Field, 0, len(fields))
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		zapFields = append(zapFields, zap.Any(key, fields[key]))
+	}
+	return l.withFields(zapFields)
+}
+
+// withFields returns a child logger with the fields of l and fields, the latter replacing
+// the former with the same key. All of them are added to the logger the fields of l were
+// added to, since zap can't remove a field once added.
+func (l *Log) withFields(fields []zapcore.Field) *Log {
+	base := l.fieldsBase
+	if base == nil {
+		base = l.log
+	}
+
+	merged := slices.Clone(l.fields)
+	for _, field := range fields {
+		i := slices.IndexFunc(merged, func(f zapcore.Field) bool { return f.Key == field.Key })
+		if i < 0 {
+			merged = append(merged, field)
+			continue
+		}
+		merged[i] = field
+	}
+
+	return l.derive(base.With(merged...), base, merged)
+}
+
+// derive returns a logger logging through log and writing to the files of l, with fields
+// added to fieldsBase by WithField and WithFields.
+func (l *Log) derive(log *zap.Logger, fieldsBase *zap.Logger, fields []zapcore.Field) *Log {
+	parent := l
+	if l.parent != nil {
+		parent = l.parent
+	}
+
+	return &Log{
+		Encoder:    l.Encoder,
+		log:        log,
+		sugar:      log.Sugar(),
+		prefix:     l.prefix,
+		level:      l.level,
+		opts:       l.opts,
+		parent:     parent,
+		fields:     fields,
+		fieldsBase: fieldsBase,
+	}
+}
+
+// Zap returns the underlying *zap.Logger, for libraries expecting one. It writes through the
+// same pipeline as l: the prefix, level, sampling, outputs and file rotation still apply,
+// since the files are written by the encoder. The reported caller is the code calling the
+// zap logger. Every call returns a new zap logger, so keep the result rather than calling
+// Zap on every log call.
+//
+// Example Usage:
+//
+//	grpczap.ReplaceGrpcLoggerV2(logger.Zap())
+func (l *Log) Zap() *zap.Logger {
+	return l.log.WithOptions(zap.AddCallerSkip(-l.opts.callerSkip()))
+}
+
+// Sugar returns the underlying logger as a *zap.SugaredLogger, like Zap.
+func (l *Log) Sugar() *zap.SugaredLogger { return l.Zap().Sugar() }
+
+// hostname returns the host name, resolved once.
+var hostname = sync.OnceValue(func() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return name
+})
+
+// contextFields returns the fields added to every entry of a logger created with opts.
+func contextFields(opts *Options) []zap.Field {
+	var fields []zap.Field
+	if opts.IncludeHostPID {
+		fields = append(fields, zap.String("host", hostname()), zap.Int("pid", os.Getpid()))
+	}
+	for _, key := range slices.Sorted(maps.Keys(opts.Fields)) {
+		fields = append(fields, zap.Any(key, opts.Fields[key]))
+	}
+	return fields
+}
+
+// zapOptions builds the zap options shared by all logger constructors of logger.
+func zapOptions(logger *Log) []zap.Option {
+	opts := logger.opts
+	zapOpts := []zap.Option{
+		zap.AddCallerSkip(opts.callerSkip()),
+		zap.WithCaller(!opts.DisableCaller),
+		zap.WithFatalHook(fatalHook{logger: logger}),
+	}
+
+	if opts.InternalErrorWriter != nil {
+		zapOpts = append(zapOpts, zap.ErrorOutput(zapcore.AddSync(opts.InternalErrorWriter)))
+	}
+
+	if !opts.DisableStacktrace {
+		stacktraceLevel := DefaultStacktraceLevel
+		if opts.StacktraceLevel != "" {
+			_ = stacktraceLevel.UnmarshalText([]byte(opts.StacktraceLevel))
+		}
+		zapOpts = append(zapOpts, zap.AddStacktrace(stacktraceLevel))
+	}
+
+	return zapOpts
+}
+
+// prefixCore is a zapcore.Core wrapper that prepends a prefix to every entry message.
+// It is used when the encoder is not ours, so the prefix cannot be added in EncodeEntry.
+type prefixCore struct {
+	zapcore.Core
+	prefix string
+}
+
+// With adds structured context to the wrapped core, keeping the prefix.
+func (c *prefixCore) With(fields []zapcore.Field) zapcore.Core {
+	return &prefixCore{Core: c.Core.With(fields), prefix: c.prefix}
+}
+
+// Check registers this core rather than the wrapped one if the wrapped core accepts the entry,
+// so the prefix is added in Write. The checked entry is shared by all cores of a Tee, so
+// rewriting its message here would prefix the entries of the other cores as well.
+func (c *prefixCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(entry, nil) == nil {
+		return ce
+	}
+	return ce.AddCore(entry, c)
+}
+
+// Write prepends the prefix to the message and writes it to the wrapped core.
+func (c *prefixCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.prefix + entry.Message
+	return c.Core.Write(entry, fields)
+}
+
+// NewNop returns a no-op logger that writes nowhere and never touches the filesystem.
+// It is useful for tests and libraries that need a *Log but shouldn't emit anything.
+// Unlike NewLog, it does not replace the global default logger.
+//
+// Note: Panic and Fatal methods still panic and exit respectively, matching zap's behavior.
+func NewNop() *Log {
+	log := zap.NewNop()
+	return &Log{
+		Encoder: internal.NewBaseEncoder(DefaultFormat, internal.EncoderConfig{TimeLayout: DefaultTimeLayout}),
+		log:     log,
+		sugar:   log.Sugar(),
+		level:   zap.NewAtomicLevelAt(DefaultLevel),
+		opts:    NewOptions(),
+	}
+}
+
+// SetLevel changes the log level at runtime without recreating the logger.
+// It returns an error if the level string is invalid.
+//
+// Note: for loggers created by NewLogWithCore the level is governed by the provided core.
+func (l *Log) SetLevel(level string) error {
+	if !isValidLevel(level) {
+		return fmt.Errorf("invalid level: %s, expected: debug, info, warn, error, dpanic, panic or fatal", level)
+	}
+
+	var zapLevel zapcore.Level
+	_ = zapLevel.UnmarshalText([]byte(level))
+	l.level.SetLevel(zapLevel)
+
+	return nil
+}
+
+// Level returns the current log level.
+func (l *Log) Level() string { return l.level.Level().String() }
+
+// setSampling replaces the sampling settings, resetting the counters, or disables sampling.
+// The entries are still grouped by Options.SampleKeyFunc if set. It has no effect on the
+// loggers created by NewLogWithCore, which leave sampling to their core.
+func (l *Log) setSampling(enable bool, initial, thereafter int, tick time.Duration) {
+	if l.parent != nil {
+		l.parent.setSampling(enable, initial, thereafter, tick)
+		return
+	}
+
+	l.sampling.Store(newSampleSettings(enable, l.opts.SampleKeyFunc, tick, initial, thereafter))
+}
+
+// WithLevelScope sets the log level until the returned function is called, e.g. to log
+// verbosely around a suspicious operation without a permanent change:
+//
+//	defer logger.WithLevelScope("debug")()
+//
+// Scopes may overlap, including from several goroutines: the most recently started scope
+// still active wins, and when the last one ends, the level in effect before the first one is
+// restored, overriding SetLevel calls made in between. Calling the returned function more
+// than once has no effect. An invalid level leaves the level unchanged.
+func (l *Log) WithLevelScope(level string) (restore func()) {
+	if l.parent != nil {
+		return l.parent.WithLevelScope(level)
+	}
+
+	zapLevel, ok := parseLevel(level)
+	if !ok {
+		return func() {}
+	}
+
+	l.scopesMu.Lock()
+	defer l.scopesMu.Unlock()
+
+	if len(l.scopes) == 0 {
+		l.scopeBase = l.level.Level()
+	}
+	scope := &zapLevel
+	l.scopes = append(l.scopes, scope)
+	l.level.SetLevel(zapLevel)
+
+	var once sync.Once
+	return func() { once.Do(func() { l.endLevelScope(scope) }) }
+}
+
+// endLevelScope removes scope from the active scopes and applies the level of the most
+// recent remaining one, or the level before the first scope if none remains.
+func (l *Log) endLevelScope(scope *zapcore.Level) {
+	l.scopesMu.Lock()
+	defer l.scopesMu.Unlock()
+
+	l.scopes = slices.DeleteFunc(l.scopes, func(s *zapcore.Level) bool { return s == scope })
+	if len(l.scopes) == 0 {
+		l.level.SetLevel(l.scopeBase)
+		return
+	}
+	l.level.SetLevel(*l.scopes[len(l.scopes)-1])
+}
+
+// Enabled reports whether entries at the given level are logged, so callers can skip
+// building costly payloads for disabled levels. It returns false for invalid levels.
+//
+// Example Usage:
+//
+//	if logger.Enabled("debug") {
+//		logger.Debugw("Request dump", "body", dumpBody(req))
+//	}
+func (l *Log) Enabled(level string) bool {
+	zapLevel, ok := parseLevel(level)
+	return ok && l.log.Core().Enabled(zapLevel)
+}
+
+// Config returns a copy of the effective options of the logger, reflecting any
+// level changes made at runtime. The copy is safe to mutate.
+func (l *Log) Config() Options {
+	opts := *l.opts
+	opts.Level = l.Level()
+	return opts
+}
+
+// LogConfig emits the effective configuration of the logger at info level.
+// Calling it on startup helps diagnose issues like "why is debug not showing".
+func (l *Log) LogConfig() {
+	opts := l.Config()
+	l.Infow("Effective log configuration", opts.fields()...)
+}
+
 // isValidLevel checks if the provided level is valid
 func isValidLevel(level string) bool {
 	return slices.Contains(
@@ -206,36 +794,132 @@ func isValidLevel(level string) bool {
 		}, level)
 }
 
+// parseLevel returns the level named level, and whether it's valid. Unlike
+// zapcore.ParseLevel, it doesn't allocate, for the level checks of hot paths.
+func parseLevel(level string) (zapcore.Level, bool) {
+	switch level {
+	case "debug":
+		return zapcore.DebugLevel, true
+	case "info":
+		return zapcore.InfoLevel, true
+	case "warn":
+		return zapcore.WarnLevel, true
+	case "error":
+		return zapcore.ErrorLevel, true
+	case "dpanic":
+		return zapcore.DPanicLevel, true
+	case "panic":
+		return zapcore.PanicLevel, true
+	case "fatal":
+		return zapcore.FatalLevel, true
+	default:
+		return zapcore.InvalidLevel, false
+	}
+}
+
+// outputLevel returns the level enabler of an output: the level named level if set, or base,
+// the level of the logger, otherwise.
+func outputLevel(level string, base zap.AtomicLevel) zapcore.LevelEnabler {
+	if zapLevel, ok := parseLevel(level); ok {
+		return zapLevel
+	}
+	return base
+}
+
 // EncodeEntry encodes the entry and fields into a buffer.
 func (l *Log) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	return l.encodeEntry(l.Encoder, entry, fields)
+}
+
+// Clone copies the base encoder with its context fields. The copy keeps writing
+// to the files of this logger.
+func (l *Log) Clone() zapcore.Encoder {
+	return &contextEncoder{Encoder: l.Encoder.Clone(), log: l}
+}
+
+// contextEncoder is a copy of the Log encoder holding additional context fields,
+// e.g. added by the With methods of the core. It writes to the files of log.
+type contextEncoder struct {
+	zapcore.Encoder
+	log *Log
+}
+
+func (e *contextEncoder) Clone() zapcore.Encoder {
+	return &contextEncoder{Encoder: e.Encoder.Clone(), log: e.log}
+}
+
+func (e *contextEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	return e.log.encodeEntry(e.Encoder, entry, fields)
+}
+
+// encodeEntry encodes the entry and fields with enc, writes them to the log files and
+// returns them for the console output.
+func (l *Log) encodeEntry(
+	enc zapcore.Encoder, entry zapcore.Entry, fields []zapcore.Field,
+) (*buffer.Buffer, error) {
 	// Get buffer from base encoder
-	buf, err := l.Encoder.EncodeEntry(entry, fields)
+	buf, err := enc.EncodeEntry(entry, fields)
 	if err != nil {
 		return nil, fmt.Errorf("EncodeEntry error: %w", err)
 	}
 
-	// Optimize prefix addition using buffer operations instead of string concatenation
-	if logPrefix != "" {
-		// Get a temporary buffer from pool for prefix operation
-		tempBuf, _ := bufferPool.Get().(*buffer.Buffer)
-		tempBuf.Reset()
-		defer bufferPool.Put(tempBuf)
+	if l.prefix != "" {
+		buf = prependPrefix(buf, l.prefix)
+	}
+
+	// File output is replaced by syslog output
+	if !l.disableFile {
+		if err := l.writeFiles(entry, buf.Bytes()); err != nil {
+			return nil, err
+		}
+	}
 
-		// Write prefix + original content efficiently
-		tempBuf.AppendString(logPrefix)
-		_, _ = tempBuf.Write(buf.Bytes())
+	if len(l.opts.ExtraWriters) > 0 {
+		l.writeExtra(buf.Bytes())
+	}
 
-		// Replace original buffer content
-		buf.Reset()
-		_, _ = buf.Write(tempBuf.Bytes())
+	// Errors go to stderr instead of the console writer, so they are never printed twice
+	if l.errToStderr && entry.Level >= zapcore.ErrorLevel {
+		writeToStderr(buf, l.reportWriteError)
 	}
 
+	return buf, nil
+}
+
+// writeExtra writes the encoded entry data to the ExtraWriters, reporting failed writes.
+func (l *Log) writeExtra(data []byte) {
+	l.extraMu.Lock()
+	defer l.extraMu.Unlock()
+
+	for i, w := range l.opts.ExtraWriters {
+		if w == nil {
+			continue
+		}
+		if _, err := w.Write(data); err != nil {
+			l.reportWriteError(fmt.Errorf("failed to write to extra writer %d: %w", i, err), data)
+		}
+	}
+}
+
+// writeFiles writes the encoded entry data to the main log file, and to the error log file
+// for entries at error level and above, setting up the files for the current date if
+// needed. With an async queue, the data is queued instead.
+func (l *Log) writeFiles(entry zapcore.Entry, data []byte) error {
+	if l.queue != nil {
+		l.enqueue(entry, data)
+		return nil
+	}
+	return l.writeFilesNow(entry, data)
+}
+
+// writeFilesNow writes the encoded entry data to the log files.
+func (l *Log) writeFilesNow(entry zapcore.Entry, data []byte) error {
 	// Optimized date checking - only check every few seconds
 	now := time.Now()
 	currentTimestamp := now.Unix()
 	if currentTimestamp-atomic.LoadInt64(&l.dateCheck) >= 3600 { // Check every hour
 		if err := l.setupLogFiles(now.Format(time.DateOnly)); err != nil {
-			return nil, err
+			return err
 		}
 		atomic.StoreInt64(&l.dateCheck, currentTimestamp)
 	} else {
@@ -245,65 +929,188 @@ func (l *Log) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.
 		l.mu.RUnlock()
 		if !fileExists {
 			if err := l.setupLogFiles(now.Format(time.DateOnly)); err != nil {
-				return nil, err
+				return err
 			}
 		}
 	}
 
+	// Error entries are synced to disk right after being written, if SyncOnError is set
+	syncFile := func(file logFile) {
+		if !l.opts.SyncOnError || entry.Level < zapcore.ErrorLevel {
+			return
+		}
+		if err := l.syncFile(file); err != nil {
+			l.reportWriteError(fmt.Errorf("failed to sync log file %s: %w", logFileName(file), err), data)
+		}
+	}
+
+	// Entries at error level and above (DPanic, Panic, Fatal) are also written to the error log file
+	var errFile logFile
+	if entry.Level >= zapcore.ErrorLevel && !l.opts.DisableSplitError {
+		l.mu.RLock()
+		errFile = l.errFile
+		l.mu.RUnlock()
+	}
+
+	// With ErrorsOnlyInErrorFile, they are written to the error log file only
+	errorOnly := errFile != nil && l.opts.ErrorsOnlyInErrorFile
+
+	l.mu.RLock()
+	file := l.file
+	l.mu.RUnlock()
+	if file == nil {
+		return nil // Closed while the entry was in flight
+	}
+
 	// Write to main log file with error handling
-	data := buf.Bytes()
-	if err := l.writeToFile(l.file, data); err != nil {
-		// Log write errors to stderr as fallback
-		fmt.Fprintf(os.Stderr, "Failed to write to log file: %v\n", err)
+	if !errorOnly {
+		if err := l.writeToFile(file, data); err != nil {
+			l.reportFileWriteError(fmt.Errorf("failed to write to log file: %w", err), data)
+		} else {
+			l.stats.written.Add(1)
+			syncFile(file)
+		}
+	}
+
+	if errFile != nil {
+		if err := l.writeToFile(errFile, data); err != nil {
+			l.reportFileWriteError(fmt.Errorf("failed to write to error log file: %w", err), data)
+		} else {
+			if errorOnly {
+				l.stats.written.Add(1)
+			}
+			syncFile(errFile)
+		}
 	}
 
-	// For error level logs, also write to error log file
-	if entry.Level == zapcore.ErrorLevel && !l.opts.DisableSplitError {
+	// Panic and fatal entries are also written to the fatal log file, before zap panics or
+	// the fatal hook exits
+	if entry.Level >= zapcore.PanicLevel && l.opts.SplitFatal {
 		l.mu.RLock()
-		errFile := l.errFile
+		fatalFile := l.fatalFile
 		l.mu.RUnlock()
-		if errFile != nil {
-			if err := l.writeToFile(errFile, data); err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to write to error log file: %v\n", err)
+		if fatalFile != nil {
+			if err := l.writeToFile(fatalFile, data); err != nil {
+				l.reportFileWriteError(fmt.Errorf("failed to write to fatal log file: %w", err), data)
+			} else {
+				syncFile(fatalFile)
 			}
 		}
 	}
 
-	return buf, nil
+	return nil
+}
+
+// prependPrefix returns the encoded entry in buf with prefix prepended. The entry is moved
+// within buf to make room for the prefix, so no other buffer is needed and buf is returned.
+func prependPrefix(buf *buffer.Buffer, prefix string) *buffer.Buffer {
+	n := buf.Len()
+	buf.AppendString(prefix) // Grow buf by the length of the prefix
+
+	bs := buf.Bytes()
+	copy(bs[len(prefix):], bs[:n])
+	copy(bs, prefix)
+	return buf
 }
 
+// reportWriteError counts an entry that ultimately failed to be written, and reports it
+// like reportError.
+func (l *Log) reportWriteError(err error, entry []byte) {
+	l.stats.writeErrors.Add(1)
+	l.reportError(err, entry)
+}
+
+// reportError reports err to the OnWriteError callback with entry, or to InternalErrorWriter
+// as fallback if none is set.
+func (l *Log) reportError(err error, entry []byte) {
+	if l.opts.OnWriteError != nil {
+		l.opts.OnWriteError(err, bytes.Clone(entry))
+		return
+	}
+	fmt.Fprintf(l.opts.internalErrors(), "%v\n", err)
+}
+
+// gzipBackups reports whether lumberjack should gzip rotated files itself.
+func (l *Log) gzipBackups() bool { return l.opts.compressAlgorithm() == CompressGzip }
+
 // writeToFile writes data to the specified file with retry logic
-func (l *Log) writeToFile(file *lumberjack.Logger, data []byte) error {
+func (l *Log) writeToFile(file logFile, data []byte) error {
 	if file == nil {
 		return errors.New("file is nil")
 	}
 
-	// Simple retry logic for file write
-	for retries := range MaxRetries {
-		if _, err := file.Write(data); err != nil {
-			if retries == MaxRetries-1 {
+	// Simple retry logic for file write, bounded by WriteTimeout
+	attempts := max(l.opts.WriteMaxRetries, 0) + 1
+	delay := l.opts.WriteRetryDelay
+	start := time.Now()
+	for attempt := range attempts {
+		if _, err := l.writeFile(file, data); err != nil {
+			if attempt == attempts-1 {
 				return fmt.Errorf("failed to write after retries: %w", err)
 			}
+			if l.opts.WriteTimeout > 0 && time.Since(start)+delay > l.opts.WriteTimeout {
+				return fmt.Errorf("failed to write within %s: %w", l.opts.WriteTimeout, err)
+			}
 
-			time.Sleep(BriefDelay) // Brief delay before retry
+			time.Sleep(delay) // Brief delay before retry
 			continue
 		}
+
+		if l.compressor != nil {
+			l.compressor.wrote(logFileName(file), len(data))
+		}
 		return nil
 	}
 	return nil
 }
 
-// testFileCreation tests if a lumberjack logger can successfully create and write to its file.
+// testFileCreation tests if a log file can successfully be created and written to, by
+// opening it for appending without writing anything, so the file starts empty.
 // This is used to validate that the filename and path are valid before committing to use them.
-func (l *Log) testFileCreation(logger *lumberjack.Logger) error {
+// New files get their header here when FileHeader is set.
+func (l *Log) testFileCreation(logger logFile) error {
 	if logger == nil {
 		return errors.New("logger is nil")
 	}
 
-	// Test by writing a small test message
-	testData := []byte("# Log file test\n")
-	if _, err := logger.Write(testData); err != nil {
-		return fmt.Errorf("failed to write test data to log file '%s': %w", logger.Filename, err)
+	path := logFileName(logger)
+	if path == "" { // Not a file, nothing to create
+		return nil
+	}
+	isNew := isEmptyFile(path)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, l.fileMode(logger))
+	if err != nil {
+		return fmt.Errorf("failed to open log file '%s': %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file '%s': %w", path, err)
+	}
+
+	// The umask applies to new files, and existing files keep their mode otherwise
+	if l.opts.FileMode != 0 {
+		if err := os.Chmod(path, l.opts.FileMode); err != nil {
+			return fmt.Errorf("failed to chmod log file '%s': %w", path, err)
+		}
+	}
+
+	if !l.opts.FileHeader || !isNew {
+		return nil
+	}
+
+	header, err := l.fileHeader()
+	if err != nil {
+		return fmt.Errorf("failed to encode the header of log file '%s': %w", path, err)
+	}
+	if _, err := l.writeFile(logger, header); err != nil {
+		return fmt.Errorf("failed to write the header of log file '%s': %w", path, err)
+	}
+
+	// Compressed data must reach the file, so the header comes first
+	if l.opts.CompressActive {
+		if err := l.flushActive(logger, false); err != nil {
+			return fmt.Errorf("failed to write the header of log file '%s': %w", path, err)
+		}
 	}
 
 	return nil
@@ -325,12 +1132,27 @@ func (l *Log) testFileCreation(logger *lumberjack.Logger) error {
 //   - Main log without Filename: "{date}.log" (backward compatible)
 //   - Error log with Filename: "{filename}-{date}_error.log"
 //   - Error log without Filename: "{date}_error.log" (backward compatible)
+//   - With FilenamePattern: the expanded pattern, which may include subdirectories
 func (l *Log) generateFileName(date string, isErrorLog bool) string {
+	if isErrorLog {
+		return l.levelFileName(date, "error")
+	}
+	return l.levelFileName(date, "main")
+}
+
+// levelFileName generates the filename of the log file of the given level: "main", or
+// "error" or "fatal" for the files receiving a copy of the entries of these levels, named
+// like the main log file with an "_error" or "_fatal" suffix.
+func (l *Log) levelFileName(date string, level string) string {
+	if l.opts.FilenamePattern != "" {
+		return l.activeFileName(l.expandFilenamePattern(date, level))
+	}
+
 	var baseName string
 
 	if l.opts.Filename != "" {
 		// Use custom prefix - sanitize it first to ensure it's safe
-		sanitized := sanitizeFilename(l.opts.Filename)
+		sanitized := sanitizeFilenameMax(l.opts.Filename, l.opts.MaxFilenameLength)
 		if sanitized != "" {
 			baseName = sanitized + "-" + date
 		} else {
@@ -346,10 +1168,64 @@ func (l *Log) generateFileName(date string, isErrorLog bool) string {
 		}
 	}
 
-	if isErrorLog {
-		return baseName + "_error.log"
+	if level != "main" {
+		return l.activeFileName(baseName + "_" + level + ".log")
+	}
+	return l.activeFileName(baseName + ".log")
+}
+
+// PlannedFiles returns the paths of the log files the logger writes to today: the main log
+// file, then the error and fatal log files if enabled. The files and directories are not
+// created, so the paths can be checked ahead of time, e.g. for permissions. It returns nil if
+// file output is disabled.
+func (l *Log) PlannedFiles() []string {
+	if l.parent != nil {
+		return l.parent.PlannedFiles()
+	}
+	if l.disableFile {
+		return nil
+	}
+
+	date := time.Now().Format(time.DateOnly)
+	files := []string{filepath.Join(l.logDir, l.levelFileName(date, "main"))}
+	if !l.opts.DisableSplitError {
+		files = append(files, filepath.Join(l.logDir, l.levelFileName(date, "error")))
+	}
+	if l.opts.SplitFatal {
+		files = append(files, filepath.Join(l.logDir, l.levelFileName(date, "fatal")))
+	}
+	return files
+}
+
+// expandFilenamePattern replaces the tokens of FilenamePattern for the given date and
+// level of log file ("main", "error" or "fatal").
+func (l *Log) expandFilenamePattern(date string, level string) string {
+	name := l.opts.FilenamePattern
+	if level != "main" && !strings.Contains(name, "{level}") {
+		// Keep the error and fatal log files apart from the main one
+		ext := filepath.Ext(name)
+		name = strings.TrimSuffix(name, ext) + "_" + level + ext
+	}
+
+	name = strings.NewReplacer(
+		"{name}", sanitizeFilenameMax(l.opts.Filename, l.opts.MaxFilenameLength),
+		"{date}", date,
+		"{level}", level,
+		"{pid}", strconv.Itoa(os.Getpid()),
+		"{host}", sanitizeFilename(hostname()),
+	).Replace(name)
+
+	// An empty {name} must not make the path absolute
+	return strings.TrimLeft(filepath.Clean(filepath.FromSlash(name)), string(filepath.Separator))
+}
+
+// activeFileName adds the ".gz" extension to the log file name if the active
+// files are compressed.
+func (l *Log) activeFileName(name string) string {
+	if l.opts.CompressActive {
+		return name + ".gz"
 	}
-	return baseName + ".log"
+	return name
 }
 
 // setupLogFiles ensures log files are properly configured with thread safety.
@@ -359,15 +1235,21 @@ func (l *Log) setupLogFiles(date string) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	// The files released by Close aren't reopened by the entries still in flight
+	if l.released {
+		return nil
+	}
+
 	// If the date hasn't changed and the file exists, no need to reconfigure
 	if l.currDate == date &&
 		l.file != nil &&
-		(l.errFile != nil || l.opts.DisableSplitError) {
+		(l.errFile != nil || l.opts.DisableSplitError) &&
+		(l.fatalFile != nil || !l.opts.SplitFatal) {
 		return nil
 	}
 
 	// Ensure log directory exists
-	if err := os.MkdirAll(l.logDir, 0o755); err != nil { //nolint:gosec
+	if err := os.MkdirAll(l.logDir, l.dirMode()); err != nil {
 		return fmt.Errorf("create log dir error: %w", err)
 	}
 
@@ -376,37 +1258,39 @@ func (l *Log) setupLogFiles(date string) error {
 		fileName := l.generateFileName(date, false)
 		fullPath := filepath.Join(l.logDir, fileName)
 
-		// Create lumberjack logger with error handling
-		mainLogger := &lumberjack.Logger{
-			Filename:   fullPath,
-			MaxSize:    l.opts.MaxSize,    // megabytes
-			MaxBackups: l.opts.MaxBackups, // number of backups
-			Compress:   l.opts.Compress,   // compress rotated files
+		// Create the subdirectories of the filename pattern
+		if err := os.MkdirAll(filepath.Dir(fullPath), l.dirMode()); err != nil {
+			return fmt.Errorf("create log dir error: %w", err)
 		}
 
+		// Create log file with error handling
+		mainLogger := l.openLogFile(fullPath)
+
 		// Test file creation by attempting to write to it
 		if err := l.testFileCreation(mainLogger); err != nil {
 			// Fallback to default filename format if custom filename fails
-			fmt.Fprintf(os.Stderr,
+			fmt.Fprintf(l.opts.internalErrors(),
 				"Failed to create log file with custom filename '%s': %v. Falling back to default format.\n",
 				fileName, err)
 
 			// Generate fallback filename (without custom prefix)
-			fallbackFileName := DefaultFilename + "-" + date + ".log"
+			_ = releaseLogFile(mainLogger)
+			fallbackFileName := l.activeFileName(DefaultFilename + "-" + date + ".log")
 			fallbackPath := filepath.Join(l.logDir, fallbackFileName)
-			mainLogger = &lumberjack.Logger{
-				Filename:   fallbackPath,
-				MaxSize:    l.opts.MaxSize,
-				MaxBackups: l.opts.MaxBackups,
-				Compress:   l.opts.Compress,
-			}
+			mainLogger = l.openLogFile(fallbackPath)
 
 			// Test fallback file creation
 			if err := l.testFileCreation(mainLogger); err != nil {
+				_ = releaseLogFile(mainLogger)
 				return fmt.Errorf("failed to create fallback log file: %w", err)
 			}
 		}
 
+		// Finish the compressed stream of the previous day's file, and release it
+		if l.file != nil {
+			_ = l.flushActive(l.file, true)
+			_ = releaseLogFile(l.file)
+		}
 		l.file = mainLogger
 	}
 
@@ -415,243 +1299,515 @@ func (l *Log) setupLogFiles(date string) error {
 		errFileName := l.generateFileName(date, true)
 		errFullPath := filepath.Join(l.logDir, errFileName)
 
-		// Create error log lumberjack logger with error handling
-		errLogger := &lumberjack.Logger{
-			Filename:   errFullPath,
-			MaxSize:    l.opts.MaxSize,    // megabytes
-			MaxBackups: l.opts.MaxBackups, // number of backups
-			Compress:   l.opts.Compress,   // compress rotated files
+		if err := os.MkdirAll(filepath.Dir(errFullPath), l.dirMode()); err != nil {
+			return fmt.Errorf("create log dir error: %w", err)
 		}
 
+		// Create error log file with error handling
+		errLogger := l.openLogFile(errFullPath)
+
 		// Test error file creation
 		if err := l.testFileCreation(errLogger); err != nil {
 			// Fallback to default error filename format if custom filename fails
-			fmt.Fprintf(os.Stderr,
+			fmt.Fprintf(l.opts.internalErrors(),
 				"Failed to create error log file with custom filename '%s': %v. Falling back to default format.\n",
 				errFileName, err,
 			)
 
 			// Generate fallback error filename (without custom prefix)
-			fallbackErrFileName := DefaultFilename + "-" + date + "_error.log"
+			_ = releaseLogFile(errLogger)
+			fallbackErrFileName := l.activeFileName(DefaultFilename + "-" + date + "_error.log")
 			fallbackErrPath := filepath.Join(l.logDir, fallbackErrFileName)
-			errLogger = &lumberjack.Logger{
-				Filename:   fallbackErrPath,
-				MaxSize:    l.opts.MaxSize,
-				MaxBackups: l.opts.MaxBackups,
-				Compress:   l.opts.Compress,
-			}
+			errLogger = l.openLogFile(fallbackErrPath)
 
 			// Test fallback error file creation
 			if err := l.testFileCreation(errLogger); err != nil {
+				_ = releaseLogFile(errLogger)
 				return fmt.Errorf("failed to create fallback error log file: %w", err)
 			}
 		}
 
+		if l.errFile != nil {
+			_ = l.flushActive(l.errFile, true)
+			_ = releaseLogFile(l.errFile)
+		}
 		l.errFile = errLogger
 	}
 
+	// Set fatal log file (if needed), falling back to the default format like the error log file
+	if l.opts.SplitFatal && (l.currDate != date || l.fatalFile == nil) {
+		fatalFullPath := filepath.Join(l.logDir, l.levelFileName(date, "fatal"))
+		if err := os.MkdirAll(filepath.Dir(fatalFullPath), l.dirMode()); err != nil {
+			return fmt.Errorf("create log dir error: %w", err)
+		}
+
+		fatalLogger := l.openLogFile(fatalFullPath)
+		if err := l.testFileCreation(fatalLogger); err != nil {
+			fmt.Fprintf(l.opts.internalErrors(),
+				"Failed to create fatal log file '%s': %v. Falling back to default format.\n",
+				fatalFullPath, err,
+			)
+
+			_ = releaseLogFile(fatalLogger)
+			fallbackFatalFileName := l.activeFileName(DefaultFilename + "-" + date + "_fatal.log")
+			fatalLogger = l.openLogFile(filepath.Join(l.logDir, fallbackFatalFileName))
+			if err := l.testFileCreation(fatalLogger); err != nil {
+				_ = releaseLogFile(fatalLogger)
+				return fmt.Errorf("failed to create fallback fatal log file: %w", err)
+			}
+		}
+
+		if l.fatalFile != nil {
+			_ = l.flushActive(l.fatalFile, true)
+			_ = releaseLogFile(l.fatalFile)
+		}
+		l.fatalFile = fatalLogger
+	}
+
 	// Update current date only after successful file setup
 	l.currDate = date
 	return nil
 }
 
-// Sync flushs any buffered log entries. Applications should take care to call Sync before exiting.
-func Sync() { DefaultLogger().Sync() }
+// Sync flushs any buffered log entries of the default logger. It keeps the log files open,
+// use CloseLogger to release them. Applications should take care to call Sync before exiting.
+func Sync() error { return DefaultLogger().Sync() }
+
+// MustSync flushs any buffered log entries of the default logger, ignoring any error.
+//
+// Deprecated: Use Sync and handle the returned error instead.
+func MustSync() { _ = Sync() }
+
+// Sync flushs any buffered log entries, keeping the log files open.
+// Applications should take care to call Sync, or Close, before exiting.
+// Sync doesn't release the log files, call Close for a logger that is no longer used.
+// It returns the zap sync error and any file flush errors joined together.
+func (l *Log) Sync() error {
+	var errs []error
+
+	if err := l.log.Sync(); err != nil && !isIgnorableSyncError(err) {
+		errs = append(errs, fmt.Errorf("sync logger: %w", err))
+	}
+
+	if l.parent != nil {
+		return errors.Join(append(errs, l.parent.Sync())...)
+	}
+
+	// Queued entries must be written before the files are flushed
+	l.drainQueue()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Finalize the compressed streams, so the files are valid gzip files
+	for _, file := range []logFile{l.file, l.errFile, l.fatalFile} {
+		if err := l.flushActive(file, false); err != nil {
+			errs = append(errs, fmt.Errorf("flush compressed log file: %w", err))
+		}
+	}
+
+	for _, tee := range l.tees {
+		if err := tee.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
 
-// Sync flushs any buffered log entries. Applications should take care to call Sync before exiting.
-func (l *Log) Sync() {
-	_ = l.log.Sync()
+// Rotate forces a rotation of the main, error and fatal log files: the active files are renamed
+// to timestamped backups and fresh files are opened in their place, with backups compressed
+// and pruned according to the rotation settings. This is useful after external triggers
+// such as logrotate, or before archiving. Files that were never opened, or that don't support
+// rotation, are left untouched. With DisableRotation, the files are reopened instead, so
+// files moved away are recreated.
+func (l *Log) Rotate() error {
+	if l.parent != nil {
+		return l.parent.Rotate()
+	}
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	if l.file != nil {
-		_ = l.file.Close()
+	var errs []error
+	for _, file := range []logFile{l.file, l.errFile, l.fatalFile} {
+		if file == nil {
+			continue
+		}
+
+		if err := l.flushActive(file, false); err != nil {
+			errs = append(errs, fmt.Errorf("flush compressed log file %s: %w", logFileName(file), err))
+		}
+
+		r, ok := file.(rotator)
+		if !ok {
+			continue
+		}
+		if err := r.Rotate(); err != nil {
+			errs = append(errs, fmt.Errorf("rotate log file %s: %w", logFileName(file), err))
+			continue
+		}
+
+		if l.compressor != nil {
+			l.compressor.rotated(logFileName(file))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// MustSync flushs any buffered log entries, ignoring any error.
+//
+// Deprecated: Use Sync and handle the returned error instead.
+func (l *Log) MustSync() { _ = l.Sync() }
+
+// Close flushes any buffered log entries and releases the log files, the syslog and remote
+// connections, the deadletter file and the goroutine of the async queue, returning any
+// error. A log file shared with other loggers is closed once the last of them releases it,
+// so a logger created afterwards for the same file opens it with its own rotation settings.
+//
+// Close is terminal: the entries logged afterwards, including through the loggers derived
+// from l and the loggers combined by Tee, are dropped. Closing a closed logger is a no-op.
+func (l *Log) Close() error {
+	if l.parent != nil {
+		return l.parent.Close()
+	}
+
+	// Entries logged from now on are dropped
+	if l.closed.Swap(true) {
+		return nil
+	}
+
+	// The queued entries are written before the files are released
+	l.stopQueue()
+
+	errs := []error{l.Sync()}
+
+	l.mu.Lock()
+	for _, file := range []*logFile{&l.file, &l.errFile, &l.fatalFile} {
+		if *file == nil {
+			continue
+		}
+		if err := l.flushActive(*file, true); err != nil {
+			errs = append(errs, fmt.Errorf("flush compressed log file %s: %w", logFileName(*file), err))
+		}
+		if err := releaseLogFile(*file); err != nil {
+			errs = append(errs, fmt.Errorf("close log file %s: %w", logFileName(*file), err))
+		}
+		*file = nil
+	}
+	l.released = true
+	l.mu.Unlock()
+
+	if err := l.closeDeadletter(); err != nil {
+		errs = append(errs, err)
 	}
 
-	if l.errFile != nil {
-		_ = l.errFile.Close()
+	for _, sink := range l.sinks {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close output: %w", err))
+		}
+	}
+
+	for _, tee := range l.tees {
+		if err := tee.Close(); err != nil {
+			errs = append(errs, err)
+		}
 	}
+
+	return errors.Join(errs...)
+}
+
+// isIgnorableSyncError reports whether err is the well-known error returned when
+// syncing a terminal or pipe (e.g. os.Stdout), which doesn't support fsync.
+func isIgnorableSyncError(err error) bool {
+	return errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY)
 }
 
-func Debug(args ...any) { DefaultLogger().log.Sugar().Debug(args...) }
+func Debug(args ...any) { DefaultLogger().sugar.Debug(args...) }
 
-func (l *Log) Debug(args ...any) { l.log.Sugar().Debug(args...) }
+func (l *Log) Debug(args ...any) { l.sugar.Debug(args...) }
 
-func Info(args ...any) { DefaultLogger().log.Sugar().Info(args...) }
+func Info(args ...any) { DefaultLogger().sugar.Info(args...) }
 
-func (l *Log) Info(args ...any) { l.log.Sugar().Info(args...) }
+func (l *Log) Info(args ...any) { l.sugar.Info(args...) }
 
-func Warn(args ...any) { DefaultLogger().log.Sugar().Warn(args...) }
+func Warn(args ...any) { DefaultLogger().sugar.Warn(args...) }
 
-func (l *Log) Warn(args ...any) { l.log.Sugar().Warn(args...) }
+func (l *Log) Warn(args ...any) { l.sugar.Warn(args...) }
 
-func Error(args ...any) { DefaultLogger().log.Sugar().Error(args...) }
+func Error(args ...any) { DefaultLogger().sugar.Error(args...) }
 
-func (l *Log) Error(args ...any) { l.log.Sugar().Error(args...) }
+func (l *Log) Error(args ...any) { l.sugar.Error(args...) }
 
-func Panic(args ...any) { DefaultLogger().log.Sugar().Panic(args...) }
+func Panic(args ...any) { DefaultLogger().sugar.Panic(args...) }
 
-func (l *Log) Panic(args ...any) { l.log.Sugar().Panic(args...) }
+func (l *Log) Panic(args ...any) { l.sugar.Panic(args...) }
 
-func Fatal(args ...any) { DefaultLogger().log.Sugar().Fatal(args...) }
+func Fatal(args ...any) { DefaultLogger().sugar.Fatal(args...) }
 
-func (l *Log) Fatal(args ...any) { l.log.Sugar().Fatal(args...) }
+func (l *Log) Fatal(args ...any) { l.sugar.Fatal(args...) }
 
-func Debugln(args ...any) { DefaultLogger().log.Sugar().Debugln(args...) }
+func Debugln(args ...any) { DefaultLogger().sugar.Debugln(args...) }
 
-func (l *Log) Debugln(args ...any) { l.log.Sugar().Debugln(args...) }
+func (l *Log) Debugln(args ...any) { l.sugar.Debugln(args...) }
 
-func Infoln(args ...any) { DefaultLogger().log.Sugar().Infoln(args...) }
+func Infoln(args ...any) { DefaultLogger().sugar.Infoln(args...) }
 
-func (l *Log) Infoln(args ...any) { l.log.Sugar().Infoln(args...) }
+func (l *Log) Infoln(args ...any) { l.sugar.Infoln(args...) }
 
-func Warnln(args ...any) { DefaultLogger().log.Sugar().Warnln(args...) }
+func Warnln(args ...any) { DefaultLogger().sugar.Warnln(args...) }
 
-func (l *Log) Warnln(args ...any) { l.log.Sugar().Warnln(args...) }
+func (l *Log) Warnln(args ...any) { l.sugar.Warnln(args...) }
 
-func Errorln(args ...any) { DefaultLogger().log.Sugar().Errorln(args...) }
+func Errorln(args ...any) { DefaultLogger().sugar.Errorln(args...) }
 
-func (l *Log) Errorln(args ...any) { l.log.Sugar().Errorln(args...) }
+func (l *Log) Errorln(args ...any) { l.sugar.Errorln(args...) }
 
-func Panicln(args ...any) { DefaultLogger().log.Sugar().Panicln(args...) }
+func Panicln(args ...any) { DefaultLogger().sugar.Panicln(args...) }
 
-func (l *Log) Panicln(args ...any) { l.log.Sugar().Panicln(args...) }
+func (l *Log) Panicln(args ...any) { l.sugar.Panicln(args...) }
 
-func Fatalln(args ...any) { DefaultLogger().log.Sugar().Fatalln(args...) }
+func Fatalln(args ...any) { DefaultLogger().sugar.Fatalln(args...) }
 
-func (l *Log) Fatalln(args ...any) { l.log.Sugar().Fatalln(args...) }
+func (l *Log) Fatalln(args ...any) { l.sugar.Fatalln(args...) }
 
 // Debugw logs a message with some additional context.
 // The variadic key-value pairs are treated as they are in With.
 func Debugw(msg string, keysAndValues ...any) {
-	DefaultLogger().log.Sugar().Debugw(msg, keysAndValues...)
+	DefaultLogger().sugar.Debugw(msg, keysAndValues...)
 }
 
 // Debugw logs a message with some additional context.
 // The variadic key-value pairs are treated as they are in With.
 func (l *Log) Debugw(msg string, keysAndValues ...any) {
-	l.log.Sugar().Debugw(msg, keysAndValues...)
+	l.sugar.Debugw(msg, keysAndValues...)
 }
 
 // Infow logs a message with some additional context.
 // The variadic key-value pairs are treated as they are in With.
 func Infow(msg string, keysAndValues ...any) {
-	DefaultLogger().log.Sugar().Infow(msg, keysAndValues...)
+	DefaultLogger().sugar.Infow(msg, keysAndValues...)
 }
 
 // Infow logs a message with some additional context.
 // The variadic key-value pairs are treated as they are in With.
 func (l *Log) Infow(msg string, keysAndValues ...any) {
-	l.log.Sugar().Infow(msg, keysAndValues...)
+	l.sugar.Infow(msg, keysAndValues...)
 }
 
 // Warnw logs a message with some additional context.
 // The variadic key-value pairs are treated as they are in With.
 func Warnw(msg string, keysAndValues ...any) {
-	DefaultLogger().log.Sugar().Warnw(msg, keysAndValues...)
+	DefaultLogger().sugar.Warnw(msg, keysAndValues...)
 }
 
 // Warnw logs a message with some additional context.
 // The variadic key-value pairs are treated as they are in With.
 func (l *Log) Warnw(msg string, keysAndValues ...any) {
-	l.log.Sugar().Warnw(msg, keysAndValues...)
+	l.sugar.Warnw(msg, keysAndValues...)
 }
 
 // Errorw logs a message with some additional context.
 // The variadic key-value pairs are treated as they are in With.
 func Errorw(msg string, keysAndValues ...any) {
-	DefaultLogger().log.Sugar().Errorw(msg, keysAndValues...)
+	DefaultLogger().sugar.Errorw(msg, keysAndValues...)
 }
 
 // Errorw logs a message with some additional context.
 // The variadic key-value pairs are treated as they are in With.
 func (l *Log) Errorw(msg string, keysAndValues ...any) {
-	l.log.Sugar().Errorw(msg, keysAndValues...)
+	l.sugar.Errorw(msg, keysAndValues...)
 }
 
 // Panicw logs a message with some additional context, then panics.
 // The variadic key-value pairs are treated as they are in With.
 func Panicw(msg string, keysAndValues ...any) {
-	DefaultLogger().log.Sugar().Panicw(msg, keysAndValues...)
+	DefaultLogger().sugar.Panicw(msg, keysAndValues...)
 }
 
 // Panicw logs a message with some additional context, then panics.
 // The variadic key-value pairs are treated as they are in With.
 func (l *Log) Panicw(msg string, keysAndValues ...any) {
-	l.log.Sugar().Panicw(msg, keysAndValues...)
+	l.sugar.Panicw(msg, keysAndValues...)
 }
 
 // Fatalw logs a message with some additional context, then calls os.Exit.
 // The variadic key-value pairs are treated as they are in With.
 func Fatalw(msg string, keysAndValues ...any) {
-	DefaultLogger().log.Sugar().Fatalw(msg, keysAndValues...)
+	DefaultLogger().sugar.Fatalw(msg, keysAndValues...)
 }
 
 // Fatalw logs a message with some additional context, then calls os.Exit.
 // The variadic key-value pairs are treated as they are in With.
 func (l *Log) Fatalw(msg string, keysAndValues ...any) {
-	l.log.Sugar().Fatalw(msg, keysAndValues...)
+	l.sugar.Fatalw(msg, keysAndValues...)
 }
 
 // Debugf formats the message according to the format specifier and logs it.
 func Debugf(template string, args ...any) {
-	DefaultLogger().log.Sugar().Debugf(template, args...)
+	DefaultLogger().sugar.Debugf(template, args...)
 }
 
 // Debugf formats the message according to the format specifier and logs it.
 func (l *Log) Debugf(template string, args ...any) {
-	l.log.Sugar().Debugf(template, args...)
+	l.sugar.Debugf(template, args...)
 }
 
 // Infof formats the message according to the format specifier and logs it.
 func Infof(template string, args ...any) {
-	DefaultLogger().log.Sugar().Infof(template, args...)
+	DefaultLogger().sugar.Infof(template, args...)
 }
 
 // Infof formats the message according to the format specifier and logs it.
 func (l *Log) Infof(template string, args ...any) {
-	l.log.Sugar().Infof(template, args...)
+	l.sugar.Infof(template, args...)
 }
 
 // Warnf formats the message according to the format specifier and logs it.
 func Warnf(template string, args ...any) {
-	DefaultLogger().log.Sugar().Warnf(template, args...)
+	DefaultLogger().sugar.Warnf(template, args...)
 }
 
 // Warnf formats the message according to the format specifier and logs it.
 func (l *Log) Warnf(template string, args ...any) {
-	l.log.Sugar().Warnf(template, args...)
+	l.sugar.Warnf(template, args...)
 }
 
 // Errorf formats the message according to the format specifier and logs it.
 func Errorf(template string, args ...any) {
-	DefaultLogger().log.Sugar().Errorf(template, args...)
+	DefaultLogger().sugar.Errorf(template, args...)
 }
 
 // Errorf formats the message according to the format specifier and logs it.
 func (l *Log) Errorf(template string, args ...any) {
-	l.log.Sugar().Errorf(template, args...)
+	l.sugar.Errorf(template, args...)
 }
 
 // Panicf formats the message according to the format specifier and panics.
 func Panicf(template string, args ...any) {
-	DefaultLogger().log.Sugar().Panicf(template, args...)
+	DefaultLogger().sugar.Panicf(template, args...)
 }
 
 // Panicf formats the message according to the format specifier and panics.
 func (l *Log) Panicf(template string, args ...any) {
-	l.log.Sugar().Panicf(template, args...)
+	l.sugar.Panicf(template, args...)
 }
 
 // Fatalf formats the message according to the format specifier and calls os.Exit.
 func Fatalf(template string, args ...any) {
-	DefaultLogger().log.Sugar().Fatalf(template, args...)
+	DefaultLogger().sugar.Fatalf(template, args...)
 }
 
 // Fatalf formats the message according to the format specifier and calls os.Exit.
 func (l *Log) Fatalf(template string, args ...any) {
-	l.log.Sugar().Fatalf(template, args...)
+	l.sugar.Fatalf(template, args...)
+}
+
+// Debugwf formats the message according to the format specifier and logs it, with some
+// additional context like Debugw.
+func Debugwf(keysAndValues []any, template string, args ...any) {
+	l := DefaultLogger()
+	if l.log.Core().Enabled(zapcore.DebugLevel) {
+		l.sugar.Debugw(formatMessage(template, args), keysAndValues...)
+	}
+}
+
+// Debugwf formats the message according to the format specifier and logs it, with some
+// additional context like Debugw.
+func (l *Log) Debugwf(keysAndValues []any, template string, args ...any) {
+	if l.log.Core().Enabled(zapcore.DebugLevel) {
+		l.sugar.Debugw(formatMessage(template, args), keysAndValues...)
+	}
+}
+
+// Infowf formats the message according to the format specifier and logs it, with some
+// additional context like Infow.
+func Infowf(keysAndValues []any, template string, args ...any) {
+	l := DefaultLogger()
+	if l.log.Core().Enabled(zapcore.InfoLevel) {
+		l.sugar.Infow(formatMessage(template, args), keysAndValues...)
+	}
+}
+
+// Infowf formats the message according to the format specifier and logs it, with some
+// additional context like Infow.
+//
+// Example Usage:
+//
+//	logger.Infowf([]any{"user", id}, "processed %d items", n)
+func (l *Log) Infowf(keysAndValues []any, template string, args ...any) {
+	if l.log.Core().Enabled(zapcore.InfoLevel) {
+		l.sugar.Infow(formatMessage(template, args), keysAndValues...)
+	}
+}
+
+// Warnwf formats the message according to the format specifier and logs it, with some
+// additional context like Warnw.
+func Warnwf(keysAndValues []any, template string, args ...any) {
+	l := DefaultLogger()
+	if l.log.Core().Enabled(zapcore.WarnLevel) {
+		l.sugar.Warnw(formatMessage(template, args), keysAndValues...)
+	}
+}
+
+// Warnwf formats the message according to the format specifier and logs it, with some
+// additional context like Warnw.
+func (l *Log) Warnwf(keysAndValues []any, template string, args ...any) {
+	if l.log.Core().Enabled(zapcore.WarnLevel) {
+		l.sugar.Warnw(formatMessage(template, args), keysAndValues...)
+	}
+}
+
+// Errorwf formats the message according to the format specifier and logs it, with some
+// additional context like Errorw.
+func Errorwf(keysAndValues []any, template string, args ...any) {
+	l := DefaultLogger()
+	if l.log.Core().Enabled(zapcore.ErrorLevel) {
+		l.sugar.Errorw(formatMessage(template, args), keysAndValues...)
+	}
+}
+
+// Errorwf formats the message according to the format specifier and logs it, with some
+// additional context like Errorw.
+func (l *Log) Errorwf(keysAndValues []any, template string, args ...any) {
+	if l.log.Core().Enabled(zapcore.ErrorLevel) {
+		l.sugar.Errorw(formatMessage(template, args), keysAndValues...)
+	}
+}
+
+// Panicwf formats the message according to the format specifier and panics, with some
+// additional context like Panicw.
+func Panicwf(keysAndValues []any, template string, args ...any) {
+	DefaultLogger().sugar.Panicw(formatMessage(template, args), keysAndValues...)
+}
+
+// Panicwf formats the message according to the format specifier and panics, with some
+// additional context like Panicw.
+func (l *Log) Panicwf(keysAndValues []any, template string, args ...any) {
+	l.sugar.Panicw(formatMessage(template, args), keysAndValues...)
+}
+
+// Fatalwf formats the message according to the format specifier and calls os.Exit, with some
+// additional context like Fatalw.
+func Fatalwf(keysAndValues []any, template string, args ...any) {
+	DefaultLogger().sugar.Fatalw(formatMessage(template, args), keysAndValues...)
+}
+
+// Fatalwf formats the message according to the format specifier and calls os.Exit, with some
+// additional context like Fatalw.
+func (l *Log) Fatalwf(keysAndValues []any, template string, args ...any) {
+	l.sugar.Fatalw(formatMessage(template, args), keysAndValues...)
+}
+
+// formatMessage formats the message of the "wf" methods like the "f" methods do: the
+// template is used as is if there are no arguments. Callers check the level first, so the
+// message is only formatted if it's logged.
+func formatMessage(template string, args []any) string {
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
 }
 
 // DefaultLogger returns the default global logger instance
@@ -663,13 +1819,67 @@ func DefaultLogger() *Log {
 	return NewLog(nil)
 }
 
-// ReplaceLogger replaces the default logger with a new instance
+// ReplaceLogger replaces the default logger with a new instance.
+// The new default logger is synced on exit in place of the previous one.
 func ReplaceLogger(l *Log) {
 	if l != nil {
 		defaultLogger.Store(l)
 	}
 }
 
+// ReplaceLoggerAndClose replaces the default logger with a new instance and closes the
+// previous one, releasing its file handles. This helps long-running applications that
+// hot-swap logger configuration avoid file descriptor exhaustion. The entries still logged
+// through the previous logger are dropped, see Log.Close.
+func ReplaceLoggerAndClose(l *Log) error {
+	if l == nil {
+		return nil
+	}
+
+	old, ok := defaultLogger.Swap(l).(*Log)
+	if !ok || old == nil || old == l {
+		return nil
+	}
+
+	return old.Close()
+}
+
+// RegisterAutoSync registers l to be synced when the process receives SIGINT or SIGTERM,
+// like the default logger, so entries buffered by loggers that are not the default
+// aren't lost on exit.
+func RegisterAutoSync(l *Log) {
+	if l != nil {
+		autoSynced.Store(l, struct{}{})
+	}
+}
+
+// syncOnExit syncs the current default logger and every logger registered with
+// RegisterAutoSync, once each.
+func syncOnExit() error {
+	var errs []error
+
+	def, _ := defaultLogger.Load().(*Log)
+	if def != nil {
+		if err := def.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	autoSynced.Range(func(key, _ any) bool {
+		if l, _ := key.(*Log); l != def {
+			if err := l.Sync(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return true
+	})
+
+	return errors.Join(errs...)
+}
+
+// CloseLogger syncs and closes the current default logger.
+func CloseLogger() error { return DefaultLogger().Close() }
+
 // LoadFromYAML loads configuration from a YAML file using Viper.
 // This function provides backward compatibility while leveraging Viper's powerful
 // configuration management capabilities for enhanced parsing and validation.
@@ -788,6 +1998,9 @@ func LoadFromTOML(tomlPath string) (*Options, error) { return LoadFromFile(tomlP
 //   - .toml (TOML format)
 //   - Other formats supported by Viper
 //
+// Environment variables are ignored, use LoadFromFileWithEnv to let them override
+// file values.
+//
 // Example Usage:
 //
 //	// Load YAML configuration
@@ -810,15 +2023,56 @@ func LoadFromTOML(tomlPath string) (*Options, error) { return LoadFromFile(tomlP
 //	    log.Fatal("Failed to load config:", err)
 //	}
 //	logger = log.NewLog(opts)
-func LoadFromFile(configPath string) (*Options, error) {
+func LoadFromFile(configPath string) (*Options, error) { return loadFromFile(configPath, "") }
+
+// LoadFromFileWithEnv loads configuration like LoadFromFile, with the environment variables
+// named after prefix (DefaultEnvPrefix if empty) taking precedence over file values, which
+// themselves take precedence over the defaults. See LoadFromEnv for the mapping.
+//
+// Example Usage:
+//
+//	opts, err := log.LoadFromFileWithEnv("config.yaml", "") // LOG_LEVEL=debug overrides level
+//	if err != nil {
+//	    log.Fatal("Failed to load config:", err)
+//	}
+//	logger := log.NewLog(opts)
+func LoadFromFileWithEnv(configPath, prefix string) (*Options, error) {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	return loadFromFile(configPath, prefix)
+}
+
+// ValidateConfigFile loads and validates the configuration file at configPath, e.g. in a
+// pre-deploy check. Like LoadFromFile, environment variables are ignored, and it neither
+// creates a logger nor touches the filesystem beyond reading the file. Invalid values are
+// reported with the errors of Options.Validate.
+//
+// Example Usage:
+//
+//	if err := log.ValidateConfigFile("deploy/log.yaml"); err != nil {
+//	    fmt.Fprintln(os.Stderr, err)
+//	    os.Exit(1)
+//	}
+func ValidateConfigFile(configPath string) error {
+	_, err := loadFromFile(configPath, "")
+	return err
+}
+
+// loadFromFile loads configuration from the file at configPath, with the environment
+// variables named after envPrefix overriding file values unless it is empty.
+func loadFromFile(configPath, envPrefix string) (*Options, error) {
 	// Start with default options
 	opts := NewOptions()
 	if opts == nil {
 		return nil, errors.New("failed to create default options")
 	}
 
-	// Create a new viper instance
+	// Create a new viper instance, with environment variables overriding file values
 	v := viper.New()
+	if envPrefix != "" {
+		bindEnv(v, envPrefix)
+	}
 
 	// Set the config file path
 	v.SetConfigFile(configPath)
@@ -842,6 +2096,11 @@ func LoadFromFile(configPath string) (*Options, error) {
 		)
 	}
 
+	// Anchor a relative directory to the configuration file
+	if opts.RelativeTo == RelativeToConfig && !filepath.IsAbs(opts.Directory) {
+		opts.Directory = filepath.Join(filepath.Dir(configPath), opts.Directory)
+	}
+
 	// Validate the loaded configuration
 	if err := opts.Validate(); err != nil {
 		return nil, fmt.Errorf(
@@ -855,6 +2114,208 @@ func LoadFromFile(configPath string) (*Options, error) {
 	return opts, nil
 }
 
+// configFormats lists the formats accepted by LoadFromBytes and LoadFromReader.
+var configFormats = []string{"yaml", "yml", "json", "toml"}
+
+// LoadFromBytes loads configuration from data in the given format ("yaml", "yml", "json" or
+// "toml"), e.g. a default configuration embedded with go:embed or fetched from a remote
+// configuration service. It behaves like LoadFromFile, ignoring environment variables.
+//
+// Example Usage:
+//
+//	//go:embed log.yaml
+//	var defaultConfig []byte
+//
+//	opts, err := log.LoadFromBytes(defaultConfig, "yaml")
+//	if err != nil {
+//	    log.Fatal("Failed to load config:", err)
+//	}
+//	logger := log.NewLog(opts)
+func LoadFromBytes(data []byte, format string) (*Options, error) {
+	return LoadFromReader(bytes.NewReader(data), format)
+}
+
+// LoadFromReader loads configuration read from r in the given format ("yaml", "yml", "json"
+// or "toml"). It behaves like LoadFromFile, ignoring environment variables.
+func LoadFromReader(r io.Reader, format string) (*Options, error) {
+	format = strings.ToLower(format)
+	if !slices.Contains(configFormats, format) {
+		return nil, fmt.Errorf("unsupported configuration format %q, must be one of: %s",
+			format, strings.Join(configFormats, ", "))
+	}
+
+	opts := NewOptions()
+	if opts == nil {
+		return nil, errors.New("failed to create default options")
+	}
+
+	v := viper.New()
+	v.SetConfigType(format)
+
+	if err := v.ReadConfig(r); err != nil {
+		return nil, fmt.Errorf("failed to read %s configuration: %w", format, err)
+	}
+
+	if err := v.Unmarshal(opts); err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse %s configuration: %w. "+
+				"Please check your configuration syntax and ensure all field names match the expected configuration options",
+			format,
+			err,
+		)
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration values in %s configuration: %w", format, err)
+	}
+
+	return opts, nil
+}
+
+// LoadFromEnv loads configuration from environment variables only, starting from
+// the default options. This is the standard 12-factor configuration pattern.
+//
+// Each Options field maps to the environment variable named after its configuration
+// key, upper-cased and prefixed with prefix and an underscore. If prefix is empty,
+// DefaultEnvPrefix ("LOG") is used. For example, with the default prefix:
+//
+//	Prefix            -> LOG_PREFIX
+//	Directory         -> LOG_DIRECTORY
+//	Filename          -> LOG_FILENAME
+//	FilenamePattern   -> LOG_FILENAME_PATTERN
+//	MaxFilenameLength -> LOG_MAX_FILENAME_LENGTH
+//	RelativeTo        -> LOG_RELATIVE_TO
+//	Level             -> LOG_LEVEL
+//	TimeLayout        -> LOG_TIME_LAYOUT
+//	TimeEncoder       -> LOG_TIME_ENCODER
+//	DurationEncoder   -> LOG_DURATION_ENCODER
+//	Format            -> LOG_FORMAT
+//	ConsoleFormat     -> LOG_CONSOLE_FORMAT
+//	FileFormat        -> LOG_FILE_FORMAT
+//	ConsoleLevel      -> LOG_CONSOLE_LEVEL
+//	FileLevel         -> LOG_FILE_LEVEL
+//	PrettyJSON        -> LOG_PRETTY_JSON
+//	FieldOrder        -> LOG_FIELD_ORDER
+//	DisableCaller     -> LOG_DISABLE_CALLER
+//	DisableStacktrace -> LOG_DISABLE_STACKTRACE
+//	DisableSplitError -> LOG_DISABLE_SPLIT_ERROR
+//	ErrorsOnlyInErrorFile -> LOG_ERRORS_ONLY_IN_ERROR_FILE
+//	SplitFatal        -> LOG_SPLIT_FATAL
+//	ExtraCallerSkip   -> LOG_EXTRA_CALLER_SKIP
+//	StacktraceLevel   -> LOG_STACKTRACE_LEVEL
+//	IncludeHostPID    -> LOG_INCLUDE_HOST_PID
+//	IncludeGoroutineID -> LOG_INCLUDE_GOROUTINE_ID
+//	CallerWithFunction -> LOG_CALLER_WITH_FUNCTION
+//	Fields            -> LOG_FIELDS
+//	MaxFieldLength    -> LOG_MAX_FIELD_LENGTH
+//	FlattenNested     -> LOG_FLATTEN_NESTED
+//	FlattenSeparator  -> LOG_FLATTEN_SEPARATOR
+//	Isolated          -> LOG_ISOLATED
+//	MaxSize           -> LOG_MAX_SIZE
+//	MaxBackups        -> LOG_MAX_BACKUPS
+//	Compress          -> LOG_COMPRESS
+//	CompressAlgorithm -> LOG_COMPRESS_ALGORITHM
+//	CompressActive    -> LOG_COMPRESS_ACTIVE
+//	DisableRotation   -> LOG_DISABLE_ROTATION
+//	FileHeader        -> LOG_FILE_HEADER
+//	DirMode           -> LOG_DIR_MODE
+//	FileMode          -> LOG_FILE_MODE
+//	EnableSampling    -> LOG_ENABLE_SAMPLING
+//	SampleInitial     -> LOG_SAMPLE_INITIAL
+//	SampleThereafter  -> LOG_SAMPLE_THEREAFTER
+//	SampleTick        -> LOG_SAMPLE_TICK
+//	ConsoleOutput     -> LOG_CONSOLE_OUTPUT
+//	ErrorToStderr     -> LOG_ERROR_TO_STDERR
+//	Syslog.Enabled    -> LOG_SYSLOG_ENABLED
+//	Syslog.Network    -> LOG_SYSLOG_NETWORK
+//	Syslog.Address    -> LOG_SYSLOG_ADDRESS
+//	Syslog.Facility   -> LOG_SYSLOG_FACILITY
+//	Syslog.Tag        -> LOG_SYSLOG_TAG
+//	Syslog.Only       -> LOG_SYSLOG_ONLY
+//	RemoteAddr        -> LOG_REMOTE_ADDR
+//	RemoteProtocol    -> LOG_REMOTE_PROTOCOL
+//	WriteTimeout      -> LOG_WRITE_TIMEOUT
+//	WriteMaxRetries   -> LOG_WRITE_MAX_RETRIES
+//	WriteRetryDelay   -> LOG_WRITE_RETRY_DELAY
+//	AsyncQueueSize    -> LOG_ASYNC_QUEUE_SIZE
+//	SyncOnError       -> LOG_SYNC_ON_ERROR
+//	DeadletterFile    -> LOG_DEADLETTER_FILE
+//
+// Example Usage:
+//
+//	opts, err := log.LoadFromEnv("MYAPP_LOG") // MYAPP_LOG_LEVEL=debug
+//	if err != nil {
+//	    log.Fatal("Failed to load config from env:", err)
+//	}
+//	logger := log.NewLog(opts)
+func LoadFromEnv(prefix string) (*Options, error) {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+
+	opts := NewOptions()
+	if opts == nil {
+		return nil, errors.New("failed to create default options")
+	}
+
+	v := viper.New()
+	bindEnv(v, prefix)
+
+	if err := v.Unmarshal(opts); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration from environment (prefix %s): %w", prefix, err)
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration values in environment (prefix %s): %w", prefix, err)
+	}
+
+	return opts, nil
+}
+
+// SaveToFile writes the options to a configuration file using Viper.
+// The format is determined by the file extension (.yaml, .yml, .json or .toml),
+// and the keys match those expected by LoadFromFile, so the file can be loaded back.
+// This is useful to generate a starter configuration from a Builder or preset.
+//
+// Parameters:
+//   - configPath: Path of the configuration file to write; an existing file is overwritten
+//
+// Returns:
+//   - error: Error if the format is unsupported or the file can't be written
+//
+// Example Usage:
+//
+//	opts := log.NewBuilder().Production().Options()
+//	if err := opts.SaveToFile("log.yaml"); err != nil {
+//	    log.Fatal("Failed to save config:", err)
+//	}
+func (opt *Options) SaveToFile(configPath string) error {
+	v := viper.New()
+
+	visitOptionFields(reflect.ValueOf(opt).Elem(), "", func(key string, value reflect.Value) {
+		// Unset lists are left out, so they are loaded back as nil rather than empty
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return
+		}
+		v.Set(key, value.Interface())
+	})
+
+	if err := v.WriteConfigAs(configPath); err != nil {
+		return fmt.Errorf("failed to write configuration file %s: %w", configPath, err)
+	}
+
+	return nil
+}
+
+// bindEnv binds every Options configuration key to its prefixed environment variable.
+// Explicit binding is required because viper's AutomaticEnv only applies to keys it
+// already knows about when unmarshaling.
+func bindEnv(v *viper.Viper, prefix string) {
+	visitOptionFields(reflect.ValueOf(Options{}), "", func(key string, _ reflect.Value) {
+		_ = v.BindEnv(key, prefix+"_"+strings.ToUpper(strings.ReplaceAll(key, ".", "_")))
+	})
+}
+
 // Quick creates a logger with default configuration for quick setup.
 // This is the fastest way to get a working logger with sensible defaults.
 //
@@ -1,9 +1,17 @@
 package log
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	stdlog "log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -12,6 +20,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 func TestLog_Option(t *testing.T) {
@@ -291,6 +304,587 @@ func TestFileWriteRetry(t *testing.T) {
 }
 
 // Test setup log files functionality
+func TestOnWriteError(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_on_write_error"
+	defer os.RemoveAll(testDir)
+
+	var (
+		mu       sync.Mutex
+		errs     []error
+		reported [][]byte
+	)
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(false).
+		WithOnWriteError(func(err error, entry []byte) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+			reported = append(reported, entry)
+		}))
+	defer logger.Close()
+
+	logger.Info("written")
+	asrt.Empty(errs)
+
+	// Point the log file below a regular file, so every write fails
+	blocker := filepath.Join(testDir, "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0o644))
+	logger.mu.Lock()
+	logger.file = &lumberjack.Logger{Filename: filepath.Join(blocker, "unwritable.log")}
+	logger.mu.Unlock()
+
+	logger.Warn("lost on a broken disk")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, errs, 1)
+	asrt.Contains(errs[0].Error(), "failed to write to log file")
+	asrt.Contains(string(reported[0]), "lost on a broken disk")
+}
+
+func TestNewLogStrict(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_new_log_strict"
+	defer os.RemoveAll(testDir)
+
+	newOptions := func() *Options {
+		opts := NewOptions().
+			WithDirectory(testDir).
+			WithConsoleOutput(false).
+			WithIsolated(true).
+			WithInternalErrorWriter(io.Discard)
+		opts.Level = "verbose"
+		return opts
+	}
+
+	// Strict mode rejects the bad level
+	logger, err := NewLogStrict(newOptions())
+	asrt.Nil(logger)
+	require.Error(t, err)
+	asrt.Contains(err.Error(), "invalid level: verbose")
+
+	logger, err = NewBuilder().Directory(testDir).Isolated(true).StacktraceLevel("verbose").BuildStrict()
+	asrt.Nil(logger)
+	asrt.ErrorContains(err, "invalid stacktrace level: verbose")
+
+	// Lenient mode repairs it
+	lenient := NewLog(newOptions())
+	defer lenient.Close()
+	asrt.Equal(DefaultLevel.String(), lenient.Level())
+
+	// Valid options create the logger
+	valid, err := NewLogStrict(newOptions().WithLevel("warn"))
+	require.NoError(t, err)
+	defer valid.Close()
+	asrt.Equal("warn", valid.Level())
+}
+
+func TestInternalErrorWriter(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_internal_error_writer"
+	defer os.RemoveAll(testDir)
+
+	var internal bytes.Buffer
+	opts := NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(false).
+		WithIsolated(true).
+		WithInternalErrorWriter(&internal)
+	opts.MaxSize = -1 // Replaced by the default, with a diagnostic
+
+	logger := NewLog(opts)
+	defer logger.Close()
+	asrt.Contains(internal.String(), "Invalid logger options: invalid max size: -1")
+	asrt.Equal(DefaultMaxSize, logger.opts.MaxSize)
+
+	// Write errors without OnWriteError are reported there as well
+	logger.Info("written")
+	internal.Reset()
+	blocker := filepath.Join(testDir, "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0o644))
+	logger.mu.Lock()
+	logger.file = &lumberjack.Logger{Filename: filepath.Join(blocker, "unwritable.log")}
+	logger.mu.Unlock()
+
+	logger.Warn("lost on a broken disk")
+	asrt.Contains(internal.String(), "failed to write to log file")
+}
+
+// failingWriter is an io.Writer failing every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("writer closed") }
+
+func TestExtraWriters(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_extra_writers"
+	defer os.RemoveAll(testDir)
+
+	var (
+		recent   bytes.Buffer
+		mu       sync.Mutex
+		errs     []error
+		reported [][]byte
+	)
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("EXTRA_").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		ExtraWriters(&recent, nil, failingWriter{}).
+		OnWriteError(func(err error, entry []byte) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+			reported = append(reported, entry)
+		}).
+		Isolated(true).
+		Build()
+
+	// Writes are serialized, so a bytes.Buffer is enough
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Infow("concurrent", "i", i)
+		}()
+	}
+	wg.Wait()
+	logger.Warnw("captured", "user_id", 42)
+	require.NoError(t, logger.Sync())
+
+	// The extra writer gets the same prefixed entries as the file
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	asrt.Equal(string(content), recent.String())
+	asrt.Equal(11, strings.Count(recent.String(), "\n"))
+	asrt.Contains(recent.String(), `EXTRA_{"level":"warn"`)
+	asrt.Contains(recent.String(), `"msg":"captured","user_id":42`)
+
+	// Failed writes are reported for each entry
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, errs, 11)
+	asrt.Contains(errs[0].Error(), "failed to write to extra writer 2: writer closed")
+	asrt.Contains(string(reported[10]), "captured")
+	asrt.Equal(uint64(11), logger.Stats().WriteErrors)
+}
+
+// captureOutput replaces *f (os.Stdout or os.Stderr) with a pipe and returns a function
+// restoring it and returning everything written in between.
+func captureOutput(t *testing.T, f **os.File) func() string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	orig := *f
+	*f = w
+
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	return func() string {
+		*f = orig
+		_ = w.Close()
+		return <-out
+	}
+}
+
+// Not parallel: replaces os.Stdout and os.Stderr
+func TestErrorToStderr(t *testing.T) {
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_error_to_stderr"
+	defer os.RemoveAll(testDir)
+
+	stdout := captureOutput(t, &os.Stdout)
+	stderr := captureOutput(t, &os.Stderr)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(true).
+		WithErrorToStderr(true))
+
+	logger.Info("info to stdout")
+	logger.Warn("warn to stdout")
+	logger.Error("error to stderr")
+	_ = logger.Sync()
+
+	stdoutData, stderrData := stdout(), stderr()
+
+	asrt.Contains(stderrData, "error to stderr")
+	asrt.NotContains(stderrData, "info to stdout")
+	asrt.NotContains(stderrData, "warn to stdout")
+
+	// Error entries are not printed twice
+	asrt.Contains(stdoutData, "info to stdout")
+	asrt.Contains(stdoutData, "warn to stdout")
+	asrt.NotContains(stdoutData, "error to stderr")
+
+	// Files still receive every entry
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	asrt.Contains(string(content), "info to stdout")
+	asrt.Contains(string(content), "error to stderr")
+}
+
+// Not parallel: replaces os.Stdout
+func TestConsoleAndFileFormat(t *testing.T) {
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_console_file_format"
+	defer os.RemoveAll(testDir)
+
+	stdout := captureOutput(t, &os.Stdout)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		ConsoleOutput(true).
+		ConsoleFormat(FormatConsole).
+		FileFormat(FormatJSON).
+		Build()
+
+	logger.Infow("structured message", "user_id", 42)
+	logger.Errorw("structured error", "code", "E42")
+	_ = logger.Sync()
+
+	stdoutData := stdout()
+
+	// The console is human readable
+	lines := strings.Split(strings.TrimSpace(stdoutData), "\n")
+	require.Len(t, lines, 2)
+	asrt.Contains(lines[0], "\tinfo\t")
+	asrt.Contains(lines[0], "structured message")
+	asrt.Contains(lines[0], `{"user_id": 42}`)
+	asrt.Contains(lines[1], "\terror\t")
+	for _, line := range lines {
+		asrt.False(json.Valid([]byte(line)), "console line should not be JSON: %s", line)
+	}
+
+	// The file is structured JSON
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), "file line should be JSON: %s", line)
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 2)
+	asrt.Equal("structured message", entries[0]["msg"])
+	asrt.InDelta(42, entries[0]["user_id"], 0)
+	asrt.Equal("error", entries[1]["level"])
+}
+
+// Not parallel: replaces os.Stdout
+func TestConsoleAndFileLevel(t *testing.T) {
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_console_file_level"
+	defer os.RemoveAll(testDir)
+
+	stdout := captureOutput(t, &os.Stdout)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(true).
+		ConsoleLevel("warn").
+		FileLevel("debug").
+		Isolated(true).
+		Build()
+
+	logger.Debug("cache miss")
+	logger.Info("request served")
+	logger.Warn("slow request")
+	asrt.True(logger.Enabled("debug"))
+
+	// Both levels are fixed, the level of the logger no longer applies
+	logger.SetLevel("error")
+	logger.Debug("cache miss again")
+	_ = logger.Sync()
+
+	stdoutData := stdout()
+	lines := strings.Split(strings.TrimSpace(stdoutData), "\n")
+	require.Len(t, lines, 1, "only warnings reach the console: %s", stdoutData)
+	asrt.Contains(lines[0], "slow request")
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 4, "every entry reaches the file")
+	asrt.Equal("debug", entries[0]["level"])
+	asrt.Equal("cache miss", entries[0]["msg"])
+	asrt.Equal("cache miss again", entries[3]["msg"])
+}
+
+func TestFileLevel_NoConsole(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_file_level_no_console"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		Level("warn").
+		FileLevel("debug").
+		Isolated(true).
+		Build()
+
+	logger.Debug("cache miss")
+	require.NoError(t, logger.Sync())
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 1)
+	asrt.Equal("cache miss", entries[0]["msg"])
+}
+
+func TestCSVFormat(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_csv_format"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatCSV).
+		ConsoleOutput(false).
+		FileHeader(true).
+		Fields(map[string]any{"service": "etl"}).
+		Isolated(true).
+		Build()
+
+	logger.Infow(`imported "orders", 3 files`, "rows", 1200, "path", "a,b.csv")
+	logger.Warn("second\nline")
+	require.NoError(t, logger.Sync())
+
+	file, err := os.Open(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	asrt.Equal([]string{"time", "level", "msg", "caller", "fields"}, records[0])
+
+	asrt.Equal("info", records[1][1])
+	asrt.Equal(`imported "orders", 3 files`, records[1][2])
+	asrt.Contains(records[1][3], "log_test.go")
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal([]byte(records[1][4]), &fields))
+	asrt.Equal(map[string]any{"service": "etl", "rows": float64(1200), "path": "a,b.csv"}, fields)
+
+	asrt.Equal("warn", records[2][1])
+	asrt.Equal("second\nline", records[2][2])
+}
+
+func TestTimeEncoder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoder string
+		pattern string // for string timestamps
+		unit    time.Duration
+	}{
+		{encoder: "", pattern: `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3}$`},
+		{encoder: TimeEncoderLayout, pattern: `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3}$`},
+		{encoder: TimeEncoderEpoch, unit: time.Second},
+		{encoder: TimeEncoderEpochMillis, unit: time.Millisecond},
+		{encoder: TimeEncoderRFC3339, pattern: `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(Z|[+-]\d{2}:\d{2})$`},
+		{encoder: TimeEncoderRFC3339Nano, pattern: `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d{1,9})?(Z|[+-]\d{2}:\d{2})$`},
+		{encoder: TimeEncoderISO8601, pattern: `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}(Z|[+-]\d{4})$`},
+	}
+
+	for _, tt := range tests {
+		t.Run("encoder_"+tt.encoder, func(t *testing.T) {
+			t.Parallel()
+			asrt := assert.New(t)
+
+			testDir := "./logs/test_logs_time_encoder_" + tt.encoder
+			defer os.RemoveAll(testDir)
+
+			logger := NewBuilder().
+				Directory(testDir).
+				Prefix("").
+				Format(FormatJSON).
+				TimeEncoder(tt.encoder).
+				ConsoleOutput(false).
+				Build()
+			before := time.Now()
+			logger.Info("timestamped")
+			require.NoError(t, logger.Sync())
+
+			entries := readJSONEntries(t, logger, testDir)
+			require.Len(t, entries, 1)
+
+			if tt.unit != 0 {
+				// Epoch encoders produce numbers in the given unit
+				ts, ok := entries[0]["ts"].(float64)
+				require.True(t, ok, "ts should be a number: %v", entries[0]["ts"])
+				asrt.InDelta(float64(before.UnixNano())/float64(tt.unit), ts, float64(time.Second/tt.unit))
+				return
+			}
+
+			ts, ok := entries[0]["ts"].(string)
+			require.True(t, ok, "ts should be a string: %v", entries[0]["ts"])
+			asrt.Regexp(tt.pattern, ts)
+		})
+	}
+}
+
+func TestDurationEncoder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoder string
+		want    any
+	}{
+		{"", 1.5},
+		{DurationEncoderString, "1.5s"},
+		{DurationEncoderSeconds, 1.5},
+		{DurationEncoderMillis, float64(1500)},
+		{DurationEncoderNanos, float64(1500000000)},
+	}
+
+	for _, tt := range tests {
+		t.Run("encoder_"+tt.encoder, func(t *testing.T) {
+			t.Parallel()
+			asrt := assert.New(t)
+
+			testDir := "./logs/test_logs_duration_encoder_" + tt.encoder
+			defer os.RemoveAll(testDir)
+
+			logger := NewBuilder().
+				Directory(testDir).
+				Prefix("").
+				Format(FormatJSON).
+				DurationEncoder(tt.encoder).
+				ConsoleOutput(false).
+				Build()
+			logger.Infow("request handled", "elapsed", 1500*time.Millisecond)
+			logger.InfoF("typed field", Duration("elapsed", 1500*time.Millisecond))
+			require.NoError(t, logger.Sync())
+
+			entries := readJSONEntries(t, logger, testDir)
+			require.Len(t, entries, 2)
+			asrt.Equal(tt.want, entries[0]["elapsed"])
+			asrt.Equal(tt.want, entries[1]["elapsed"])
+		})
+	}
+
+	err := NewOptions().WithDurationEncoder("hours").Validate()
+	assert.ErrorContains(t, err, "invalid duration encoder: hours")
+	assert.Equal(t, DurationEncoderSeconds, NewOptions().WithDurationEncoder("").DurationEncoder)
+}
+
+func Test_Options_Validate_TimeEncoder(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	for _, encoder := range []string{"", "layout", "epoch", "epochmillis", "rfc3339", "rfc3339nano", "iso8601"} {
+		opt := NewOptions()
+		opt.TimeEncoder = encoder
+		asrt.NoError(opt.Validate(), encoder)
+	}
+
+	err := NewOptions().WithTimeEncoder("unix").Validate()
+	asrt.ErrorContains(err, "invalid time encoder: unix")
+	asrt.Equal(TimeEncoderLayout, NewOptions().WithTimeEncoder("").TimeEncoder)
+}
+
+// Not parallel: replaces os.Stdout
+func TestPrettyJSON(t *testing.T) {
+	asrt := assert.New(t)
+
+	for _, pretty := range []bool{false, true} {
+		testDir := fmt.Sprintf("./logs/test_logs_pretty_json_%t", pretty)
+		defer os.RemoveAll(testDir)
+
+		stdout := captureOutput(t, &os.Stdout)
+
+		logger := NewBuilder().
+			Directory(testDir).
+			Prefix("").
+			Format(FormatJSON).
+			PrettyJSON(pretty).
+			ConsoleOutput(true).
+			Build()
+		logger.Infow("pretty message", "user_id", 42)
+		_ = logger.Sync()
+
+		stdoutData := stdout()
+
+		// The console is indented only when enabled, and stays valid JSON
+		asrt.True(json.Valid([]byte(stdoutData)), "console output should be JSON: %s", stdoutData)
+		if pretty {
+			asrt.Contains(stdoutData, "{\n  \"level\": \"info\",\n")
+			asrt.Contains(stdoutData, "\n  \"user_id\": 42\n}\n")
+		} else {
+			asrt.Equal(1, strings.Count(stdoutData, "\n"))
+		}
+
+		// The file always has one compact entry per line
+		entries := readJSONEntries(t, logger, testDir)
+		require.Len(t, entries, 1)
+		asrt.Equal("pretty message", entries[0]["msg"])
+	}
+
+	// Only the JSON format is indented
+	asrt.False(NewOptions().WithPrettyJSON(true).prettyConsole())
+	asrt.True(NewOptions().WithPrettyJSON(true).WithConsoleFormat(FormatJSON).prettyConsole())
+}
+
+func TestFieldOrder(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_field_order"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatConsole).
+		ConsoleOutput(false).
+		FieldOrder("request_id", "user_id").
+		Build()
+
+	logger.Infow("first", "status", 200, "user_id", 7, "request_id", "r-1")
+	logger.Infow("second", "request_id", "r-2", "path", "/", "user_id", 8)
+	logger.InfoF("typed", Int("user_id", 9), String("method", "GET"))
+	require.NoError(t, logger.Sync())
+
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+
+	// Pinned keys come first whatever the order of the call, the other fields keep theirs
+	asrt.Contains(string(content), `first	{"request_id": "r-1", "user_id": 7, "status": 200}`)
+	asrt.Contains(string(content), `second	{"request_id": "r-2", "user_id": 8, "path": "/"}`)
+	asrt.Contains(string(content), `typed	{"user_id": 9, "method": "GET"}`)
+}
+
 func TestSetupLogFiles(t *testing.T) {
 	t.Parallel()
 	asrt := assert.New(t)
@@ -326,6 +920,44 @@ func TestSetupLogFiles(t *testing.T) {
 	asrt.Equal(currentDate, logger.currDate)
 }
 
+func TestSetupLogFiles_EmptyFiles(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_setup_empty"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("EMPTY_").
+		WithDisableSplitError(false).
+		WithConsoleOutput(false).
+		WithIsolated(true))
+	defer logger.Sync()
+	date := time.Now().Format(time.DateOnly)
+
+	// The files are created empty, without any test data
+	require.NoError(t, logger.setupLogFiles(date))
+	for _, errorLog := range []bool{false, true} {
+		info, err := os.Stat(filepath.Join(testDir, logger.generateFileName(date, errorLog)))
+		require.NoError(t, err)
+		asrt.Zero(info.Size())
+		if runtime.GOOS != "windows" {
+			asrt.Equal(os.FileMode(0o600), info.Mode().Perm(), "same permissions as lumberjack")
+		}
+	}
+
+	// The first line is the first entry
+	logger.Info("first entry")
+	require.NoError(t, logger.Sync())
+
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(date, false)))
+	require.NoError(t, err)
+	asrt.True(strings.HasPrefix(string(content), "EMPTY_"), "content: %s", content)
+	asrt.Contains(string(content), "first entry")
+	asrt.Equal(1, strings.Count(string(content), "\n"))
+}
+
 // Test generateFileName functionality - comprehensive test suite
 func TestGenerateFileName(t *testing.T) {
 	t.Parallel()
@@ -993,6 +1625,85 @@ func TestConcurrentMultiInstance_SameFilename(t *testing.T) {
 	}
 }
 
+func TestConcurrentMultiInstance_SameFilenameLineIntegrity(t *testing.T) {
+	t.Parallel()
+
+	for _, disableRotation := range []bool{false, true} {
+		t.Run(fmt.Sprintf("disable rotation %t", disableRotation), func(t *testing.T) {
+			t.Parallel()
+			asrt := assert.New(t)
+
+			testDir := fmt.Sprintf("./logs/test_logs_concurrent_integrity_%t", disableRotation)
+			defer os.RemoveAll(testDir)
+
+			const numInstances = 4
+			const numGoroutines = 8
+			const numOperations = 100
+
+			loggers := make([]*Log, numInstances)
+			for i := range numInstances {
+				loggers[i] = NewBuilder().
+					Directory(testDir).
+					Filename("shared-app").
+					Prefix("").
+					Format(FormatJSON).
+					ConsoleOutput(false).
+					DisableSplitError(false).
+					DisableRotation(disableRotation).
+					Isolated(true).
+					Build()
+			}
+
+			// Entries larger than a page, so a torn write would show
+			payload := strings.Repeat("0123456789", 500)
+			var wg sync.WaitGroup
+			for i, logger := range loggers {
+				for j := range numGoroutines {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for k := range numOperations {
+							id := fmt.Sprintf("%d-%d-%d", i, j, k)
+							logger.Infow("integrity", "id", id, "payload", payload)
+							if k%10 == 0 {
+								logger.Errorw("integrity error", "id", id, "payload", payload)
+							}
+						}
+					}()
+				}
+			}
+			wg.Wait()
+			for _, logger := range loggers {
+				require.NoError(t, logger.Sync())
+			}
+
+			planned := loggers[0].PlannedFiles()
+			require.Len(t, planned, 2)
+			for path, want := range map[string]int{
+				planned[0]: numInstances * numGoroutines * numOperations * 11 / 10,
+				planned[1]: numInstances * numGoroutines * numOperations / 10,
+			} {
+				content, err := os.ReadFile(path)
+				require.NoError(t, err)
+
+				lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+				asrt.Len(lines, want, path)
+
+				ids := make(map[string]bool, len(lines))
+				for n, line := range lines {
+					var entry map[string]any
+					if !asrt.NoError(json.Unmarshal([]byte(line), &entry), "line %d of %s is torn", n+1, path) {
+						break
+					}
+					asrt.Equal(payload, entry["payload"])
+					ids[fmt.Sprint(entry["level"], entry["id"])] = true
+				}
+				asrt.Len(ids, want, "every entry is written once")
+			}
+		})
+	}
+}
+
 // Test thread safety and file access conflict handling
 func TestConcurrentMultiInstance_ThreadSafety(t *testing.T) {
 	t.Parallel()
@@ -1293,6 +2004,9 @@ func TestConcurrentMultiInstance_FilenameSanitization(t *testing.T) {
 				}(j)
 			}
 			innerWg.Wait()
+
+			// Error entries go to the error log file
+			logger.Errorw("Sanitization test error", "filename_index", index)
 		}(i, filename)
 	}
 
@@ -1537,8 +2251,8 @@ func TestSetupLogFilesWithFilename(t *testing.T) {
 	asrt.Equal(currentDate, logger.currDate)
 
 	// Check that the file paths contain the custom filename
-	asrt.Contains(logger.file.Filename, "testapp-2025-07-20.log")
-	asrt.Contains(logger.errFile.Filename, "testapp-2025-07-20_error.log")
+	asrt.Contains(logFileName(logger.file), "testapp-2025-07-20.log")
+	asrt.Contains(logFileName(logger.errFile), "testapp-2025-07-20_error.log")
 
 	// Test backward compatibility with empty filename
 	opts = NewOptions().
@@ -1553,10 +2267,10 @@ func TestSetupLogFilesWithFilename(t *testing.T) {
 	asrt.NoError(err)
 
 	// Verify files use default naming format
-	asrt.Contains(logger.file.Filename, "2025-07-20.log")
-	asrt.Contains(logger.errFile.Filename, "2025-07-20_error.log")
-	asrt.NotContains(logger.file.Filename, "testapp")
-	asrt.NotContains(logger.errFile.Filename, "testapp")
+	asrt.Contains(logFileName(logger.file), "2025-07-20.log")
+	asrt.Contains(logFileName(logger.errFile), "2025-07-20_error.log")
+	asrt.NotContains(logFileName(logger.file), "testapp")
+	asrt.NotContains(logFileName(logger.errFile), "testapp")
 }
 
 // Integration test for setupLogFiles method with custom filename behavior
@@ -1589,8 +2303,8 @@ func TestSetupLogFiles_Integration_CustomFilename(t *testing.T) {
 		// Verify file paths are correctly generated
 		expectedMainPath := filepath.Join(testDir, "myservice-2025-07-20.log")
 		expectedErrorPath := filepath.Join(testDir, "myservice-2025-07-20_error.log")
-		asrt.Equal(expectedMainPath, logger.file.Filename)
-		asrt.Equal(expectedErrorPath, logger.errFile.Filename)
+		asrt.Equal(expectedMainPath, logFileName(logger.file))
+		asrt.Equal(expectedErrorPath, logFileName(logger.errFile))
 
 		// Test actual file creation by writing to them
 		testData := []byte("Integration test log entry\n")
@@ -1627,7 +2341,7 @@ func TestSetupLogFiles_Integration_CustomFilename(t *testing.T) {
 
 		// Verify file path is correctly generated
 		expectedMainPath := filepath.Join(testDir, "singlelog-2025-07-21.log")
-		asrt.Equal(expectedMainPath, logger.file.Filename)
+		asrt.Equal(expectedMainPath, logFileName(logger.file))
 
 		// Test actual file creation
 		testData := []byte("Single log integration test\n")
@@ -1656,8 +2370,8 @@ func TestSetupLogFiles_Integration_CustomFilename(t *testing.T) {
 		// Verify sanitized filenames are used
 		expectedMainPath := filepath.Join(testDir, "app_service_test_-2025-07-22.log")
 		expectedErrorPath := filepath.Join(testDir, "app_service_test_-2025-07-22_error.log")
-		asrt.Equal(expectedMainPath, logger.file.Filename)
-		asrt.Equal(expectedErrorPath, logger.errFile.Filename)
+		asrt.Equal(expectedMainPath, logFileName(logger.file))
+		asrt.Equal(expectedErrorPath, logFileName(logger.errFile))
 
 		// Test actual file creation with sanitized names
 		testData := []byte("Sanitized filename test\n")
@@ -1830,8 +2544,8 @@ func TestSetupLogFiles_Integration_DateChange(t *testing.T) {
 
 		// Verify initial setup
 		asrt.Equal(firstDate, logger.currDate)
-		firstMainFile := logger.file.Filename
-		firstErrorFile := logger.errFile.Filename
+		firstMainFile := logFileName(logger.file)
+		firstErrorFile := logFileName(logger.errFile)
 		asrt.Contains(firstMainFile, "rotatingapp-2025-07-20.log")
 		asrt.Contains(firstErrorFile, "rotatingapp-2025-07-20_error.log")
 
@@ -1849,8 +2563,8 @@ func TestSetupLogFiles_Integration_DateChange(t *testing.T) {
 
 		// Verify date change was handled
 		asrt.Equal(secondDate, logger.currDate)
-		secondMainFile := logger.file.Filename
-		secondErrorFile := logger.errFile.Filename
+		secondMainFile := logFileName(logger.file)
+		secondErrorFile := logFileName(logger.errFile)
 		asrt.Contains(secondMainFile, "rotatingapp-2025-07-21.log")
 		asrt.Contains(secondErrorFile, "rotatingapp-2025-07-21_error.log")
 
@@ -1904,8 +2618,8 @@ func TestSetupLogFiles_Integration_DateChange(t *testing.T) {
 
 		originalMainFile := logger.file
 		originalErrorFile := logger.errFile
-		originalMainPath := logger.file.Filename
-		originalErrorPath := logger.errFile.Filename
+		originalMainPath := logFileName(logger.file)
+		originalErrorPath := logFileName(logger.errFile)
 
 		// Write initial data
 		initialData := []byte("Initial log entry\n")
@@ -1919,8 +2633,8 @@ func TestSetupLogFiles_Integration_DateChange(t *testing.T) {
 		// Verify files are the same instances (not recreated)
 		asrt.Equal(originalMainFile, logger.file, "Main file should not be recreated for same date")
 		asrt.Equal(originalErrorFile, logger.errFile, "Error file should not be recreated for same date")
-		asrt.Equal(originalMainPath, logger.file.Filename, "Main file path should remain the same")
-		asrt.Equal(originalErrorPath, logger.errFile.Filename, "Error file path should remain the same")
+		asrt.Equal(originalMainPath, logFileName(logger.file), "Main file path should remain the same")
+		asrt.Equal(originalErrorPath, logFileName(logger.errFile), "Error file path should remain the same")
 
 		// Write additional data to verify files are still functional
 		additionalData := []byte("Additional log entry\n")
@@ -1949,8 +2663,8 @@ func TestSetupLogFiles_Integration_DateChange(t *testing.T) {
 		err := logger.setupLogFiles(firstDate)
 		asrt.NoError(err)
 
-		firstMainFile := logger.file.Filename
-		firstErrorFile := logger.errFile.Filename
+		firstMainFile := logFileName(logger.file)
+		firstErrorFile := logFileName(logger.errFile)
 		asrt.Equal(filepath.Join(testDir, "2025-08-01.log"), firstMainFile)
 		asrt.Equal(filepath.Join(testDir, "2025-08-01_error.log"), firstErrorFile)
 
@@ -1964,8 +2678,8 @@ func TestSetupLogFiles_Integration_DateChange(t *testing.T) {
 		err = logger.setupLogFiles(secondDate)
 		asrt.NoError(err)
 
-		secondMainFile := logger.file.Filename
-		secondErrorFile := logger.errFile.Filename
+		secondMainFile := logFileName(logger.file)
+		secondErrorFile := logFileName(logger.errFile)
 		asrt.Equal(filepath.Join(testDir, "2025-08-02.log"), secondMainFile)
 		asrt.Equal(filepath.Join(testDir, "2025-08-02_error.log"), secondErrorFile)
 
@@ -2781,6 +3495,79 @@ sample_thereafter: 2000
 	asrt.NoError(err)
 }
 
+func TestFromConfigFile_StacktraceAndCaller(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		config     string
+		wantStack  bool
+		wantCaller bool
+	}{
+		{
+			name:       "stacktraces and caller enabled",
+			config:     "disable_stacktrace: false\nstacktrace_level: error\ndisable_caller: false\n",
+			wantStack:  true,
+			wantCaller: true,
+		},
+		{
+			name:       "stacktraces disabled",
+			config:     "disable_stacktrace: true\nstacktrace_level: error\ndisable_caller: false\n",
+			wantStack:  false,
+			wantCaller: true,
+		},
+		{
+			name:       "caller disabled",
+			config:     "disable_stacktrace: false\nstacktrace_level: error\ndisable_caller: true\n",
+			wantStack:  true,
+			wantCaller: false,
+		},
+		{
+			name:       "default stacktrace level excludes errors",
+			config:     "disable_stacktrace: false\n",
+			wantStack:  false,
+			wantCaller: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			asrt := assert.New(t)
+
+			tempDir := t.TempDir()
+			configFile := filepath.Join(tempDir, "config.yaml")
+			config := fmt.Sprintf("directory: %q\nconsole_output: false\n%s", tempDir, tt.config)
+			require.NoError(t, os.WriteFile(configFile, []byte(config), 0o644))
+
+			logger, err := FromConfigFile(configFile)
+			require.NoError(t, err)
+
+			logger.Error("config driven error")
+			require.NoError(t, logger.Sync())
+
+			content, err := os.ReadFile(filepath.Join(tempDir, logger.generateFileName(logger.currDate, false)))
+			require.NoError(t, err)
+
+			// The caller is part of the entry line, the stacktrace follows on the next lines
+			_, entry, found := strings.Cut(string(content), "\terror\t")
+			require.True(t, found)
+			entryLine, stack, _ := strings.Cut(entry, "\n")
+
+			if tt.wantCaller {
+				asrt.Contains(entryLine, "log_test.go:")
+			} else {
+				asrt.NotContains(entryLine, "log_test.go:")
+			}
+			if tt.wantStack {
+				asrt.Contains(stack, "TestFromConfigFile_StacktraceAndCaller")
+			} else {
+				asrt.Empty(stack)
+			}
+		})
+	}
+}
+
 func TestLoadFromYAML_MinimalConfiguration(t *testing.T) {
 	t.Parallel()
 	asrt := assert.New(t)
@@ -2925,17 +3712,225 @@ format = "console"`,
 	}
 }
 
-func TestQuick(t *testing.T) {
-	logger := Quick()
-	if logger == nil {
-		t.Fatal("Quick() returned nil logger")
-	}
+func TestLoadFromFileWithEnv(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+	content := "level: info\nformat: console\ndirectory: " + tempDir + "\nmax_size: 10\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+
+	envDir := filepath.Join(tempDir, "env")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_FORMAT", "json")
+	t.Setenv("LOG_DIRECTORY", envDir)
+	t.Setenv("LOG_MAX_BACKUPS", "7")        // Not present in the file
+	t.Setenv("LOG_CONSOLE_OUTPUT", "false") // Not present in the file
+
+	opts, err := LoadFromFileWithEnv(configPath, "")
+	require.NoError(t, err)
 
-	// Test that we can log without errors
-	logger.Info("Test message from Quick()")
+	assert.Equal(t, "debug", opts.Level)
+	assert.Equal(t, "json", opts.Format)
+	assert.Equal(t, envDir, opts.Directory)
+	assert.Equal(t, 7, opts.MaxBackups)
+	assert.False(t, opts.ConsoleOutput)
+
+	// Values without an env override still come from the file
+	assert.Equal(t, 10, opts.MaxSize)
+
+	// A custom prefix replaces DefaultEnvPrefix
+	t.Setenv("MYAPP_LOG_LEVEL", "warn")
+	opts, err = LoadFromFileWithEnv(configPath, "MYAPP_LOG")
+	require.NoError(t, err)
+	assert.Equal(t, "warn", opts.Level)
+	assert.Equal(t, "console", opts.Format)
 }
 
-func TestWithPreset(t *testing.T) {
+func TestLoadFromFile_IgnoresEnv(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("level: info\nformat: console\n"), 0o600))
+
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("LOG_FORMAT", "json")
+
+	opts, err := LoadFromFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "info", opts.Level)
+	assert.Equal(t, "console", opts.Format)
+
+	opts, err = LoadFromBytes([]byte("level: info\n"), "yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "info", opts.Level)
+	assert.Equal(t, DefaultFormat, opts.Format)
+}
+
+func TestLoadFromFileWithEnv_InvalidOverride(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("level: info\n"), 0o600))
+
+	t.Setenv("LOG_LEVEL", "verbose")
+
+	_, err := LoadFromFileWithEnv(configPath, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid level")
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	tempDir := t.TempDir()
+	logDir := filepath.Join(tempDir, "logs")
+
+	valid := filepath.Join(tempDir, "valid.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte("level: warn\nformat: json\ndirectory: "+logDir+"\n"), 0o600))
+
+	// Environment variables don't affect the validation of the file
+	t.Setenv("LOG_LEVEL", "verbose")
+
+	require.NoError(t, ValidateConfigFile(valid))
+	assert.NoDirExists(t, logDir, "validating doesn't create the log directory")
+
+	syntax := filepath.Join(tempDir, "syntax.yaml")
+	require.NoError(t, os.WriteFile(syntax, []byte("level: [warn\n"), 0o600))
+	err := ValidateConfigFile(syntax)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read configuration file")
+
+	semantic := filepath.Join(tempDir, "semantic.json")
+	require.NoError(t, os.WriteFile(semantic, []byte(`{"level": "verbose", "max_size": -1}`), 0o600))
+	err = ValidateConfigFile(semantic)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid level: verbose")
+	assert.Contains(t, err.Error(), "invalid max size")
+
+	assert.Error(t, ValidateConfigFile(filepath.Join(tempDir, "missing.yaml")))
+}
+
+func TestLoadFromBytes(t *testing.T) {
+	t.Parallel()
+
+	configs := map[string]string{
+		"yaml": "level: debug\nformat: json\nmax_size: 42\nsyslog:\n  tag: embedded\n",
+		"yml":  "level: debug\nformat: json\nmax_size: 42\nsyslog:\n  tag: embedded\n",
+		"json": `{"level": "debug", "format": "json", "max_size": 42, "syslog": {"tag": "embedded"}}`,
+		"TOML": "level = \"debug\"\nformat = \"json\"\nmax_size = 42\n[syslog]\ntag = \"embedded\"\n",
+	}
+	for format, content := range configs {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := LoadFromBytes([]byte(content), format)
+			require.NoError(t, err)
+			assert.Equal(t, "debug", opts.Level)
+			assert.Equal(t, "json", opts.Format)
+			assert.Equal(t, 42, opts.MaxSize)
+			assert.Equal(t, "embedded", opts.Syslog.Tag)
+
+			// Unset values keep their defaults
+			assert.Equal(t, DefaultMaxBackups, opts.MaxBackups)
+		})
+	}
+}
+
+func TestLoadFromBytes_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := LoadFromBytes([]byte("level: info\n"), "ini")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported configuration format")
+
+	_, err = LoadFromBytes([]byte("level: [info\n"), "yaml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read yaml configuration")
+
+	_, err = LoadFromBytes([]byte(`{"level": "verbose"}`), "json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid level")
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	t.Setenv("MYAPP_LOG_LEVEL", "warn")
+	t.Setenv("MYAPP_LOG_PREFIX", "ENV_")
+	t.Setenv("MYAPP_LOG_ENABLE_SAMPLING", "true")
+	t.Setenv("MYAPP_LOG_SAMPLE_INITIAL", "5")
+	t.Setenv("MYAPP_LOG_SYSLOG_ENABLED", "true")
+	t.Setenv("MYAPP_LOG_SYSLOG_FACILITY", "local1")
+	t.Setenv("MYAPP_LOG_DIR_MODE", "0700")
+
+	opts, err := LoadFromEnv("MYAPP_LOG")
+	require.NoError(t, err)
+
+	assert.Equal(t, "warn", opts.Level)
+	assert.Equal(t, "ENV_", opts.Prefix)
+	assert.True(t, opts.EnableSampling)
+	assert.Equal(t, 5, opts.SampleInitial)
+	assert.True(t, opts.Syslog.Enabled)
+	assert.Equal(t, "local1", opts.Syslog.Facility)
+	assert.Equal(t, os.FileMode(0o700), opts.DirMode)
+
+	// Unset values keep their defaults
+	assert.Equal(t, DefaultFormat, opts.Format)
+	assert.Equal(t, DefaultMaxSize, opts.MaxSize)
+
+	// Empty prefix falls back to DefaultEnvPrefix
+	t.Setenv("LOG_FORMAT", "json")
+	opts, err = LoadFromEnv("")
+	require.NoError(t, err)
+	assert.Equal(t, "json", opts.Format)
+
+	// Invalid values are rejected
+	t.Setenv("LOG_MAX_SIZE", "-1")
+	_, err = LoadFromEnv("")
+	assert.Error(t, err)
+}
+
+func TestOptions_SaveToFile_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	opts := NewBuilder().
+		Production().
+		Prefix("SAVE_").
+		Directory(filepath.Join(tempDir, "logs")).
+		Filename("starter").
+		Level("warn").
+		MaxSize(42).
+		Sampling(true, 10, 20).
+		FieldOrder("request_id", "user_id").
+		Options()
+
+	for _, ext := range []string{"yaml", "yml", "json", "toml"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(tempDir, "log."+ext)
+			require.NoError(t, opts.SaveToFile(path))
+
+			loaded, err := LoadFromFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, opts, loaded)
+		})
+	}
+}
+
+func TestOptions_SaveToFile_UnsupportedExtension(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "log.conf")
+	err := NewOptions().SaveToFile(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to write configuration file")
+	assert.NoFileExists(t, path)
+}
+
+func TestQuick(t *testing.T) {
+	logger := Quick()
+	if logger == nil {
+		t.Fatal("Quick() returned nil logger")
+	}
+
+	// Test that we can log without errors
+	logger.Info("Test message from Quick()")
+}
+
+func TestWithPreset(t *testing.T) {
 	tests := []struct {
 		name   string
 		preset Preset
@@ -3620,6 +4615,35 @@ func TestSamplingFunctionality(t *testing.T) {
 			logger.Info("Test message", i)
 		}
 	})
+
+	t.Run("sampling hook observes dropped entries", func(t *testing.T) {
+		testDir := "./logs/test_logs_sampling_hook"
+		defer os.RemoveAll(testDir)
+
+		var sampled, dropped atomic.Int64
+		logger := NewBuilder().
+			Directory(testDir).
+			ConsoleOutput(false).
+			Sampling(true, 5, 1000).
+			SamplingHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+				if dec&zapcore.LogDropped != 0 {
+					dropped.Add(1)
+				}
+				if dec&zapcore.LogSampled != 0 {
+					sampled.Add(1)
+				}
+			}).
+			Build()
+		defer logger.Sync()
+
+		// Flood identical messages, only the first 5 pass within the tick
+		for range 100 {
+			logger.Info("Identical flood message")
+		}
+
+		assert.Equal(t, int64(5), sampled.Load())
+		assert.Equal(t, int64(95), dropped.Load())
+	})
 }
 
 func TestSamplingIntegration(t *testing.T) {
@@ -3752,3 +4776,1198 @@ func TestConsoleOutput_FileWritingBehavior(t *testing.T) {
 	asrt.True(logger1.opts.ConsoleOutput, "Logger1 should have console output enabled")
 	asrt.False(logger2.opts.ConsoleOutput, "Logger2 should have console output disabled")
 }
+
+func TestNewNop(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	logger := NewNop()
+	asrt.NotNil(logger)
+
+	var _ Logger = logger
+
+	// All non-terminating methods must be safe to call
+	logger.Debug("debug")
+	logger.Debugf("debug %d", 1)
+	logger.Debugw("debug", "key", "value")
+	logger.Debugln("debug")
+	logger.Info("info")
+	logger.Infof("info %d", 1)
+	logger.Infow("info", "key", "value")
+	logger.Infoln("info")
+	logger.Warn("warn")
+	logger.Warnf("warn %d", 1)
+	logger.Warnw("warn", "key", "value")
+	logger.Warnln("warn")
+	logger.Error("error")
+	logger.Errorf("error %d", 1)
+	logger.Errorw("error", "key", "value")
+	logger.Errorln("error")
+	logger.Sync()
+
+	asrt.Panics(func() { logger.Panic("panic") })
+
+	// The nop logger never sets up any files
+	asrt.Nil(logger.file)
+	asrt.Nil(logger.errFile)
+	asrt.Empty(logger.currDate)
+
+	// The nop logger must not replace the global default logger
+	asrt.NotSame(logger, DefaultLogger())
+}
+
+func TestNewLogWithCore(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	opts := NewOptions().WithPrefix("CORE_")
+
+	logger := NewLogWithCore(core, opts)
+	asrt.NotNil(logger)
+
+	logger.Debugw("debug message", "key", "value")
+	logger.Infof("info %d", 1)
+	logger.Error("error message")
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 3)
+
+	asrt.Equal("CORE_debug message", entries[0].Message)
+	asrt.Equal("value", entries[0].ContextMap()["key"])
+	asrt.Equal(zapcore.DebugLevel, entries[0].Level)
+	asrt.Equal("CORE_info 1", entries[1].Message)
+	asrt.Equal("CORE_error message", entries[2].Message)
+
+	// Caller is enabled by default and must point at this file, not the wrapper
+	asrt.True(entries[0].Caller.Defined)
+	asrt.Contains(entries[0].Caller.File, "log_test.go")
+
+	// No files are set up when a custom core is used
+	asrt.Nil(logger.file)
+	asrt.Nil(logger.errFile)
+}
+
+func TestNewLogWithCore_DisableCallerAndNoPrefix(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	opts := NewOptions().WithPrefix("").WithDisableCaller(true)
+
+	logger := NewLogWithCore(core, opts)
+	logger.Debug("filtered by core level")
+	logger.Info("plain message")
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	asrt.Equal("plain message", entries[0].Message)
+	asrt.False(entries[0].Caller.Defined)
+}
+
+// logViaWrapper is a helper as found in libraries wrapping *Log, adding a stack frame
+func logViaWrapper(logger *Log, msg string) { logger.Info(msg) }
+
+func TestExtraCallerSkip(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix("").WithExtraCallerSkip(1))
+
+	_, _, line, _ := runtime.Caller(0)
+	logViaWrapper(logger, "via wrapper")
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+
+	// The caller is the user's call site, not the wrapper
+	asrt.Contains(entries[0].Caller.File, "log_test.go")
+	asrt.Equal(line+1, entries[0].Caller.Line)
+
+	// Negative values are rejected
+	err := NewOptions().WithExtraCallerSkip(-1).Validate()
+	asrt.Error(err)
+	asrt.Contains(err.Error(), "invalid extra caller skip")
+}
+
+func TestExtraCallerSkip_Default(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions())
+	asrt.Equal(DefaultExtraCallerSkip, logger.opts.ExtraCallerSkip)
+
+	// Without the extra skip, the caller is the wrapper itself
+	logViaWrapper(logger, "via wrapper")
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	asrt.Contains(entries[0].Caller.Function, "logViaWrapper")
+}
+
+func TestExtraCallerSkip_OptionsLiteral(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_caller_skip_literal"
+	defer os.RemoveAll(testDir)
+
+	// A zero ExtraCallerSkip still reports the caller of the Log method, not the method itself
+	logger := NewLog(&Options{Directory: testDir, Format: FormatJSON, Isolated: true})
+	_, _, line, _ := runtime.Caller(0)
+	logger.Info("from struct literal")
+	require.NoError(t, logger.Sync())
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 1)
+	asrt.Contains(entries[0]["caller"], fmt.Sprintf("log_test.go:%d", line+1))
+}
+
+func TestStacktraceLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		opts      *Options
+		wantStack map[zapcore.Level]bool
+	}{
+		{
+			name:      "default attaches from panic",
+			opts:      NewOptions(),
+			wantStack: map[zapcore.Level]bool{zapcore.WarnLevel: false, zapcore.ErrorLevel: false},
+		},
+		{
+			name:      "error level attaches from error",
+			opts:      NewOptions().WithStacktraceLevel("error"),
+			wantStack: map[zapcore.Level]bool{zapcore.WarnLevel: false, zapcore.ErrorLevel: true},
+		},
+		{
+			name:      "disabled suppresses stacktraces",
+			opts:      NewOptions().WithStacktraceLevel("warn").WithDisableStacktrace(true),
+			wantStack: map[zapcore.Level]bool{zapcore.WarnLevel: false, zapcore.ErrorLevel: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			core, recorded := observer.New(zapcore.DebugLevel)
+			logger := NewLogWithCore(core, tt.opts)
+
+			logger.Warn("warn message")
+			logger.Error("error message")
+
+			entries := recorded.AllUntimed()
+			require.Len(t, entries, 2)
+			for _, entry := range entries {
+				if tt.wantStack[entry.Level] {
+					assert.Contains(t, entry.Stack, "TestStacktraceLevel", "level %s", entry.Level)
+				} else {
+					assert.Empty(t, entry.Stack, "level %s", entry.Level)
+				}
+			}
+		})
+	}
+
+	err := NewOptions().WithStacktraceLevel("verbose").Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid stacktrace level")
+}
+
+func TestIncludeHostPID(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_host_pid"
+	defer os.RemoveAll(testDir)
+
+	host, err := os.Hostname()
+	require.NoError(t, err)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		IncludeHostPID(true).
+		Build()
+	logger.Infow("tagged", "key", "value")
+	logger.Error("tagged error")
+	require.NoError(t, logger.Sync())
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		asrt.Equal(host, entry["host"])
+		asrt.InDelta(os.Getpid(), entry["pid"], 0)
+	}
+	asrt.Equal("value", entries[0]["key"])
+
+	// Disabled by default
+	core, recorded := observer.New(zapcore.InfoLevel)
+	NewLogWithCore(core, NewOptions()).Info("untagged")
+	require.Len(t, recorded.AllUntimed(), 1)
+	asrt.Empty(recorded.AllUntimed()[0].Context)
+}
+
+func TestIncludeGoroutineID(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_goroutine_id"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		IncludeGoroutineID(true).
+		Isolated(true).
+		Build()
+
+	var wg sync.WaitGroup
+	for i := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Infow("from goroutine", "worker", i)
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, logger.Sync())
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 2)
+	ids := make(map[float64]bool)
+	for _, entry := range entries {
+		id, ok := entry[goroutineKey].(float64)
+		require.True(t, ok, "goroutine field in %v", entry)
+		asrt.Positive(id)
+		ids[id] = true
+	}
+	asrt.Len(ids, 2, "each goroutine has its own ID")
+
+	// The ID is the one of the logging goroutine, also with a custom core
+	core, recorded := observer.New(zapcore.InfoLevel)
+	NewLogWithCore(core, NewOptions().WithIncludeGoroutineID(true)).Info("tagged")
+	require.Len(t, recorded.AllUntimed(), 1)
+	asrt.Equal(goroutineID(), recorded.AllUntimed()[0].ContextMap()[goroutineKey])
+
+	// Disabled by default
+	core, recorded = observer.New(zapcore.InfoLevel)
+	NewLogWithCore(core, NewOptions()).Info("untagged")
+	require.Len(t, recorded.AllUntimed(), 1)
+	asrt.NotContains(recorded.AllUntimed()[0].ContextMap(), goroutineKey)
+}
+
+// logThroughHelper logs msg from a helper, which a ExtraCallerSkip of 1 skips.
+func logThroughHelper(logger *Log, msg string) { logger.Info(msg) }
+
+func TestCallerWithFunction(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_caller_function"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		CallerWithFunction(true).
+		Isolated(true).
+		Build()
+	logger.Info("direct")
+	logThroughHelper(logger, "through helper")
+	require.NoError(t, logger.Sync())
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 2)
+	asrt.Equal("github.com/kydenul/log.TestCallerWithFunction", entries[0][funcKey])
+	asrt.Equal("github.com/kydenul/log.logThroughHelper", entries[1][funcKey])
+
+	// The function follows the extra caller skip, like the file and line
+	core, recorded := observer.New(zapcore.InfoLevel)
+	skipping := NewLogWithCore(core, NewOptions().WithCallerWithFunction(true).WithExtraCallerSkip(1))
+	logThroughHelper(skipping, "skipped helper")
+	require.Len(t, recorded.AllUntimed(), 1)
+	entry := recorded.AllUntimed()[0]
+	asrt.Equal("github.com/kydenul/log.TestCallerWithFunction", entry.ContextMap()[funcKey])
+	asrt.Equal(entry.Caller.Function, entry.ContextMap()[funcKey])
+
+	// Not added without caller, nor by default
+	core, recorded = observer.New(zapcore.InfoLevel)
+	NewLogWithCore(core, NewOptions().WithCallerWithFunction(true).WithDisableCaller(true)).Info("no caller")
+	NewLogWithCore(core, NewOptions()).Info("default")
+	require.Len(t, recorded.AllUntimed(), 2)
+	for _, entry := range recorded.AllUntimed() {
+		asrt.NotContains(entry.ContextMap(), funcKey)
+	}
+}
+
+func TestFilenamePattern(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_filename_pattern"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Filename("svc").
+		FilenamePattern("{name}/{date}/app-{level}-{pid}.log").
+		DisableSplitError(false).
+		ConsoleOutput(false).
+		Build()
+	logger.Info("main entry")
+	logger.Error("error entry")
+	require.NoError(t, logger.Sync())
+
+	date := time.Now().Format(time.DateOnly)
+	pid := strconv.Itoa(os.Getpid())
+
+	// Subdirectories are created and tokens substituted
+	mainFile := filepath.Join(testDir, "svc", date, "app-main-"+pid+".log")
+	errFile := filepath.Join(testDir, "svc", date, "app-error-"+pid+".log")
+	asrt.Equal(mainFile, filepath.Join(testDir, logger.generateFileName(date, false)))
+	asrt.Equal(errFile, filepath.Join(testDir, logger.generateFileName(date, true)))
+
+	content, err := os.ReadFile(mainFile)
+	require.NoError(t, err)
+	asrt.Contains(string(content), "main entry")
+	asrt.Contains(string(content), "error entry")
+
+	content, err = os.ReadFile(errFile)
+	require.NoError(t, err)
+	asrt.NotContains(string(content), "main entry")
+	asrt.Contains(string(content), "error entry")
+}
+
+func TestFilenamePattern_WithoutLevel(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	host, err := os.Hostname()
+	require.NoError(t, err)
+
+	logger := &Log{opts: NewOptions().WithFilenamePattern("{host}/{name}{date}.log")}
+
+	// The error log file gets a suffix, an empty name is left out
+	asrt.Equal(filepath.Join(sanitizeFilename(host), "2025-07-20.log"), logger.generateFileName("2025-07-20", false))
+	asrt.Equal(filepath.Join(sanitizeFilename(host), "2025-07-20_error.log"), logger.generateFileName("2025-07-20", true))
+
+	// A leading empty name doesn't make the path absolute
+	logger.opts.WithFilenamePattern("{name}/{date}.log")
+	asrt.Equal("2025-07-20.log", logger.generateFileName("2025-07-20", false))
+}
+
+func TestMaxFilenameLength(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_max_filename_length"
+	defer os.RemoveAll(testDir)
+
+	name := strings.Repeat("服务", 30) // 180 bytes
+	logger := NewBuilder().
+		Directory(testDir).
+		Filename(name).
+		MaxFilenameLength(149).
+		ConsoleOutput(false).
+		Build()
+	logger.Info("long filename")
+	require.NoError(t, logger.Sync())
+
+	// 149 bytes is in the middle of a rune, the name is cut before it
+	want := strings.Repeat("服务", 24) + "服-" + logger.currDate + ".log"
+	asrt.Equal(want, logger.generateFileName(logger.currDate, false))
+
+	content, err := os.ReadFile(filepath.Join(testDir, want))
+	require.NoError(t, err)
+	asrt.Contains(string(content), "long filename")
+
+	// The limit applies to the {name} token too
+	logger.opts.WithFilenamePattern("{name}.log").WithMaxFilenameLength(10)
+	asrt.Equal("服务服.log", logger.generateFileName(logger.currDate, false))
+}
+
+func TestFilenamePattern_Validation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		wantErr string
+	}{
+		{"{name}/{date}/app-{level}.log", ""},
+		{"{host}-{pid}-{date}.log", ""},
+		{"logs/{date}.log", ""},
+		{"{date}-{hour}.log", "unknown token {hour}"},
+		{"{Date}.log", "unknown token {Date}"},
+		{"/var/log/{date}.log", "relative to the log directory"},
+		{"../{date}.log", "relative to the log directory"},
+		{"{name}/../../{date}.log", "relative to the log directory"},
+	}
+
+	for _, tt := range tests {
+		err := NewOptions().WithFilenamePattern(tt.pattern).Validate()
+		if tt.wantErr == "" {
+			assert.NoError(t, err, tt.pattern)
+		} else if assert.Error(t, err, tt.pattern) {
+			assert.Contains(t, err.Error(), tt.wantErr, tt.pattern)
+		}
+	}
+}
+
+// syncCountingCore counts the calls to Sync of the wrapped core
+type syncCountingCore struct {
+	zapcore.Core
+	syncs atomic.Int32
+}
+
+func (c *syncCountingCore) Sync() error {
+	c.syncs.Add(1)
+	return c.Core.Sync()
+}
+
+// Not parallel: replaces the default logger
+func TestRegisterAutoSync(t *testing.T) {
+	asrt := assert.New(t)
+
+	original := DefaultLogger()
+	defer ReplaceLogger(original)
+
+	newCountingLogger := func() (*Log, *syncCountingCore) {
+		core := &syncCountingCore{Core: zapcore.NewNopCore()}
+		return NewLogWithCore(core, NewOptions()), core
+	}
+
+	_, otherCore := newCountingLogger()
+	registered, registeredCore := newCountingLogger()
+	RegisterAutoSync(registered)
+	defer autoSynced.Delete(registered)
+	def, defCore := newCountingLogger() // The new default logger
+
+	// Simulates the exit handler installed by init
+	asrt.NoError(syncOnExit())
+	asrt.Equal(int32(1), registeredCore.syncs.Load())
+	asrt.Equal(int32(1), defCore.syncs.Load())
+	asrt.Zero(otherCore.syncs.Load())
+
+	// A registered default logger is synced only once
+	RegisterAutoSync(def)
+	defer autoSynced.Delete(def)
+	asrt.NoError(syncOnExit())
+	asrt.Equal(int32(2), defCore.syncs.Load())
+
+	// Replacing the default logger syncs the new one instead
+	replacement, replacementCore := newCountingLogger()
+	ReplaceLogger(replacement)
+	autoSynced.Delete(def)
+	asrt.NoError(syncOnExit())
+	asrt.Equal(int32(1), replacementCore.syncs.Load())
+	asrt.Equal(int32(2), defCore.syncs.Load())
+	asrt.Equal(int32(3), registeredCore.syncs.Load())
+}
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	debugCore, debugRecorded := observer.New(zapcore.DebugLevel)
+	warnCore, warnRecorded := observer.New(zapcore.WarnLevel)
+
+	first := NewLogWithCore(debugCore, NewOptions().WithPrefix("FIRST_"))
+	second := NewLogWithCore(warnCore, NewOptions().WithPrefix(""))
+
+	logger := Tee(first, nil, second)
+	asrt.Same(logger, DefaultLogger())
+	asrt.Equal("debug", logger.Level())
+
+	logger.Debugw("debug message", "key", "value")
+	logger.Warn("warn message")
+	logger.Error("error message")
+
+	// Every logger receives the entries its own level allows, with its own prefix
+	entries := debugRecorded.AllUntimed()
+	require.Len(t, entries, 3)
+	asrt.Equal("FIRST_debug message", entries[0].Message)
+	asrt.Equal("value", entries[0].ContextMap()["key"])
+	asrt.Equal("FIRST_warn message", entries[1].Message)
+	asrt.Equal("FIRST_error message", entries[2].Message)
+
+	entries = warnRecorded.AllUntimed()
+	require.Len(t, entries, 2)
+	asrt.Equal("warn message", entries[0].Message)
+	asrt.Equal("error message", entries[1].Message)
+
+	// Caller points at this file, not at Tee or the wrapper
+	asrt.Contains(entries[0].Caller.File, "log_test.go")
+
+	asrt.NoError(logger.Sync())
+}
+
+func TestTee_SyncFlushesAll(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_tee"
+	defer os.RemoveAll(testDir)
+
+	fileLogger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(false).
+		WithFormat(FormatJSON))
+	core, recorded := observer.New(zapcore.InfoLevel)
+
+	logger := Tee(fileLogger, NewLogWithCore(core, NewOptions()))
+	logger.Info("fan out")
+	asrt.NoError(logger.Sync())
+
+	asrt.Len(recorded.AllUntimed(), 1)
+	content, err := os.ReadFile(filepath.Join(testDir, fileLogger.generateFileName(fileLogger.currDate, false)))
+	require.NoError(t, err)
+	asrt.Contains(string(content), `"msg":"fan out"`)
+}
+
+func TestLog_WithOptions(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix(""))
+
+	derived := logger.WithOptions(zap.Fields(zap.String("request_id", "r-1")))
+	asrt.NotSame(logger, derived)
+	asrt.NotSame(derived, DefaultLogger())
+
+	derived.Info("with field")
+	logger.Info("without field")
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+	asrt.Equal(map[string]any{"request_id": "r-1"}, entries[0].ContextMap())
+	asrt.Empty(entries[1].Context, "the receiver must be left unchanged")
+
+	// Caller points at this file, as for the receiver
+	asrt.Contains(entries[0].Caller.File, "log_test.go")
+}
+
+// contextKeys returns the keys of the context fields of the entry, in order.
+func contextKeys(entry observer.LoggedEntry) []string {
+	keys := make([]string, 0, len(entry.Context))
+	for _, field := range entry.Context {
+		keys = append(keys, field.Key)
+	}
+	return keys
+}
+
+func TestLog_WithField(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix(""))
+
+	child := logger.WithField("user", "u-1")
+	grandchild := child.WithField("user", "u-2").WithField("order", 42)
+
+	child.Info("child")
+	grandchild.Info("grandchild")
+	logger.Info("root")
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 3)
+	asrt.Equal(map[string]any{"user": "u-1"}, entries[0].ContextMap())
+	asrt.Equal(map[string]any{"user": "u-2", "order": int64(42)}, entries[1].ContextMap())
+	asrt.Equal([]string{"user", "order"}, contextKeys(entries[1]), "overridden, not repeated")
+	asrt.Empty(entries[2].Context, "the receiver must be left unchanged")
+	asrt.Contains(entries[1].Caller.File, "log_test.go")
+
+	// Options applied in between keep the fields replaceable
+	recorded.TakeAll()
+	logger.WithField("user", "u-1").
+		WithOptions(zap.Fields(zap.String("component", "db"))).
+		WithField("user", "u-3").
+		Info("through options")
+	entries = recorded.TakeAll()
+	require.Len(t, entries, 1)
+	asrt.ElementsMatch([]string{"user", "component"}, contextKeys(entries[0]))
+	asrt.Equal("u-3", entries[0].ContextMap()["user"])
+}
+
+func TestLog_WithFields(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_with_fields"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("").
+		WithFormat(FormatJSON).
+		WithConsoleOutput(false).
+		WithIsolated(true))
+
+	child := logger.WithFields(map[string]any{"zone": "eu", "app": "billing", "user": "u-1"})
+	grandchild := child.WithFields(map[string]any{"user": "u-2", "attempt": 2})
+
+	for range 5 {
+		child.Info("child")
+	}
+	grandchild.Info("grandchild")
+	require.NoError(t, logger.Sync())
+
+	// Fields are in the order of their sorted keys, whatever the map iteration order
+	content, err := os.ReadFile(logger.PlannedFiles()[0])
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 6)
+	for _, line := range lines[:5] {
+		asrt.Contains(line, `"app":"billing","user":"u-1","zone":"eu"`)
+	}
+	asrt.Contains(lines[5], `"app":"billing","user":"u-2","zone":"eu","attempt":2`)
+	asrt.Equal(1, strings.Count(lines[5], `"user"`), "overridden, not repeated")
+
+	// The children write to the files of the logger
+	asrt.Equal(uint64(6), logger.Stats().Written)
+}
+
+func TestLog_Zap(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_zap"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("").
+		WithFormat(FormatJSON).
+		WithLevel("info").
+		WithConsoleOutput(false).
+		WithIsolated(true))
+
+	zl := logger.Zap()
+	zl.Debug("filtered by level")
+	zl.Info("from zap", zap.Int("items", 3))
+	logger.Sugar().Warnw("from sugar", "user", "u-1")
+	require.NoError(t, logger.Sync())
+
+	// Entries go through the files of the logger, with the caller of the zap logger
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 2)
+	asrt.Equal("from zap", entries[0]["msg"])
+	asrt.InDelta(3, entries[0]["items"], 0)
+	asrt.Contains(entries[0]["caller"], "log_test.go")
+	asrt.Equal("warn", entries[1]["level"])
+	asrt.Equal("u-1", entries[1]["user"])
+	asrt.Contains(entries[1]["caller"], "log_test.go")
+	asrt.Equal(uint64(2), logger.Stats().Written)
+
+	// Level changes apply to the zap logger as well
+	require.NoError(t, logger.SetLevel("error"))
+	asrt.Nil(zl.Check(zapcore.WarnLevel, "filtered"))
+}
+
+func TestLog_WithOptions_SharesFiles(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_with_options"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("").
+		WithFormat(FormatJSON).
+		WithConsoleOutput(false))
+	derived := logger.WithOptions(zap.Fields(zap.String("component", "db")))
+
+	logger.Info("from parent")
+	derived.Info("from derived")
+	asrt.NoError(derived.Sync())
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 2)
+	asrt.NotContains(entries[0], "component")
+	asrt.Equal("db", entries[1]["component"])
+
+	// The level and the counters are shared with the receiver
+	asrt.NoError(logger.SetLevel("warn"))
+	asrt.False(derived.Enabled("info"))
+	asrt.Equal(logger.Stats(), derived.Stats())
+	asrt.Equal(uint64(2), derived.Stats().Written)
+	asrt.NoError(derived.Rotate())
+}
+
+// Not parallel: checks the default logger and the standard library logger
+func TestIsolated(t *testing.T) {
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_isolated"
+	defer os.RemoveAll(testDir)
+
+	defaultLogger := DefaultLogger()
+	stdWriter := stdlog.Writer()
+
+	first := NewBuilder().Directory(testDir).Filename("first").ConsoleOutput(false).Isolated(true).Build()
+	second := NewLog(NewOptions().WithDirectory(testDir).WithFilename("second").
+		WithConsoleOutput(false).WithIsolated(true))
+	core, recorded := observer.New(zapcore.InfoLevel)
+	withCore := NewLogWithCore(core, NewOptions().WithIsolated(true))
+
+	// No global state is mutated
+	asrt.Same(defaultLogger, DefaultLogger())
+	Tee(first, withCore)
+	asrt.Same(defaultLogger, DefaultLogger(), "a tee of isolated loggers is isolated too")
+	asrt.Equal(stdWriter, stdlog.Writer())
+
+	// The isolated loggers still work on their own
+	first.Info("first entry")
+	second.Info("second entry")
+	withCore.Info("core entry")
+	asrt.NoError(first.Sync())
+	asrt.NoError(second.Sync())
+	asrt.Len(recorded.AllUntimed(), 1)
+
+	content, err := os.ReadFile(filepath.Join(testDir, first.generateFileName(first.currDate, false)))
+	require.NoError(t, err)
+	asrt.Contains(string(content), "first entry")
+	asrt.NotContains(string(content), "second entry")
+}
+
+func TestLog_Close(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_close"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithDisableSplitError(false).
+		WithConsoleOutput(false))
+
+	logger.Info("before close")
+	logger.Error("error before close")
+
+	asrt.NoError(logger.Close())
+	// Closing twice is safe
+	asrt.NoError(logger.Close())
+	asrt.Nil(logger.file, "the files are released")
+	asrt.Nil(logger.errFile)
+
+	// Close is terminal: the entries logged afterwards are dropped, and the files stay closed
+	logger.Info("after close")
+	logger.WithField("derived", true).Error("derived after close")
+	logger.Zap().Info("zap after close")
+	asrt.NoError(logger.Sync())
+	asrt.Nil(logger.file)
+
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	asrt.Contains(string(content), "before close")
+	asrt.NotContains(string(content), "after close")
+	asrt.Equal(uint64(2), logger.Stats().Written)
+}
+
+func TestReplaceLoggerAndClose(t *testing.T) {
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_replace_close"
+	defer os.RemoveAll(testDir)
+
+	oldLogger := NewLog(NewOptions().WithDirectory(testDir).WithConsoleOutput(false))
+	oldLogger.Info("old logger message")
+
+	newLogger := NewNop()
+	asrt.NoError(ReplaceLoggerAndClose(newLogger))
+	asrt.Same(newLogger, DefaultLogger())
+
+	// Replacing with nil or the same instance is a no-op
+	asrt.NoError(ReplaceLoggerAndClose(nil))
+	asrt.NoError(ReplaceLoggerAndClose(newLogger))
+	asrt.Same(newLogger, DefaultLogger())
+
+	asrt.NoError(CloseLogger())
+}
+
+// failingSyncer is a WriteSyncer whose Sync always fails
+type failingSyncer struct{ discardWriter }
+
+func (failingSyncer) Sync() error { return errors.New("disk full") }
+
+func TestLog_SyncReturnsError(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_sync_error"
+	defer os.RemoveAll(testDir)
+
+	// Successful sync returns nil
+	logger := NewLog(NewOptions().WithDirectory(testDir).WithConsoleOutput(false))
+	logger.Info("sync ok")
+	asrt.NoError(logger.Sync())
+
+	// A failing sink surfaces its error
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}),
+		failingSyncer{},
+		zapcore.InfoLevel,
+	)
+	failing := NewLogWithCore(core, NewOptions())
+	failing.Info("sync fails")
+
+	err := failing.Sync()
+	asrt.Error(err)
+	asrt.Contains(err.Error(), "disk full")
+	asrt.Error(failing.Close())
+
+	// MustSync never panics even when Sync fails
+	asrt.NotPanics(failing.MustSync)
+}
+
+func TestLog_SetLevel(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	logger := NewLog(NewOptions().WithDirectory("./logs/test_logs_set_level").WithConsoleOutput(false))
+	defer os.RemoveAll("./logs/test_logs_set_level")
+
+	asrt.Equal("info", logger.Level())
+	asrt.False(logger.log.Core().Enabled(zapcore.DebugLevel))
+
+	asrt.NoError(logger.SetLevel("debug"))
+	asrt.Equal("debug", logger.Level())
+	asrt.True(logger.log.Core().Enabled(zapcore.DebugLevel))
+
+	asrt.Error(logger.SetLevel("verbose"))
+	asrt.Equal("debug", logger.Level())
+}
+
+func TestLog_Enabled(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_enabled"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().WithDirectory(testDir).WithLevel("warn").WithConsoleOutput(false))
+
+	asrt.False(logger.Enabled("debug"))
+	asrt.False(logger.Enabled("info"))
+	asrt.True(logger.Enabled("warn"))
+	asrt.True(logger.Enabled("error"))
+
+	// Enabled follows runtime level changes
+	asrt.NoError(logger.SetLevel("debug"))
+	asrt.True(logger.Enabled("debug"))
+	asrt.True(logger.Enabled("info"))
+
+	asrt.NoError(logger.SetLevel("error"))
+	asrt.False(logger.Enabled("warn"))
+	asrt.True(logger.Enabled("fatal"))
+
+	// Invalid levels are never enabled
+	asrt.False(logger.Enabled(""))
+	asrt.False(logger.Enabled("verbose"))
+
+	// Loggers created with a core follow the level of the core
+	core, _ := observer.New(zapcore.InfoLevel)
+	withCore := NewLogWithCore(core, NewOptions())
+	asrt.False(withCore.Enabled("debug"))
+	asrt.True(withCore.Enabled("info"))
+}
+
+func TestLog_WithLevelScope(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_level_scope"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().WithDirectory(testDir).WithLevel("info").
+		WithConsoleOutput(false).WithIsolated(true))
+
+	func() {
+		defer logger.WithLevelScope("debug")()
+		asrt.Equal("debug", logger.Level())
+		asrt.True(logger.Enabled("debug"))
+	}()
+	asrt.Equal("info", logger.Level())
+
+	// Nested scopes restore the enclosing level
+	outer := logger.WithLevelScope("warn")
+	inner := logger.WithLevelScope("debug")
+	asrt.Equal("debug", logger.Level())
+	inner()
+	asrt.Equal("warn", logger.Level())
+	inner() // No effect when called again
+	asrt.Equal("warn", logger.Level())
+	outer()
+	asrt.Equal("info", logger.Level())
+
+	// Overlapping scopes: the most recent active scope wins, the original level comes back last
+	first := logger.WithLevelScope("debug")
+	second := logger.WithLevelScope("error")
+	first()
+	asrt.Equal("error", logger.Level())
+	second()
+	asrt.Equal("info", logger.Level())
+
+	// Invalid levels are ignored
+	logger.WithLevelScope("verbose")()
+	asrt.Equal("info", logger.Level())
+
+	// Loggers created by WithOptions share the scopes
+	restore := logger.WithOptions().WithLevelScope("debug")
+	asrt.Equal("debug", logger.Level())
+	restore()
+	asrt.Equal("info", logger.Level())
+}
+
+func TestLog_WithLevelScope_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	testDir := "./logs/test_logs_level_scope_concurrent"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().WithDirectory(testDir).WithLevel("warn").
+		WithConsoleOutput(false).WithIsolated(true))
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			restore := logger.WithLevelScope([]string{"debug", "info", "error"}[i%3])
+			logger.Debug("maybe logged")
+			restore()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, "warn", logger.Level())
+}
+
+func TestLog_Infowf(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix("").WithIsolated(true))
+
+	logger.Debugwf([]any{"skipped", true}, "filtered by level %d", 1)
+	logger.Infowf([]any{"user", "u-1"}, "processed %d items", 3)
+	logger.Warnwf([]any{"attempt", 2}, "retrying %s", "upload")
+	logger.Errorwf(nil, "no fields, %d%% done", 50)
+	logger.Infowf([]any{"raw", true}, "100% literal") // No arguments, the template is kept as is
+	asrt.PanicsWithValue("fatal state: corrupt", func() {
+		logger.Panicwf([]any{"state", "corrupt"}, "fatal state: %s", "corrupt")
+	})
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 5)
+
+	asrt.Equal(zapcore.InfoLevel, entries[0].Level)
+	asrt.Equal("processed 3 items", entries[0].Message)
+	asrt.Equal(map[string]any{"user": "u-1"}, entries[0].ContextMap())
+	asrt.Contains(entries[0].Caller.File, "log_test.go")
+
+	asrt.Equal(zapcore.WarnLevel, entries[1].Level)
+	asrt.Equal("retrying upload", entries[1].Message)
+	asrt.Equal(map[string]any{"attempt": int64(2)}, entries[1].ContextMap())
+
+	asrt.Equal("no fields, 50% done", entries[2].Message)
+	asrt.Empty(entries[2].Context)
+	asrt.Equal("100% literal", entries[3].Message)
+
+	asrt.Equal(zapcore.PanicLevel, entries[4].Level)
+	asrt.Equal(map[string]any{"state": "corrupt"}, entries[4].ContextMap())
+}
+
+func TestLog_Config(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_config"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithFilename("app").
+		WithLevel("warn").
+		WithConsoleOutput(false))
+
+	cfg := logger.Config()
+	asrt.Equal(testDir, cfg.Directory)
+	asrt.Equal("app", cfg.Filename)
+	asrt.Equal("warn", cfg.Level)
+
+	// Mutating the copy doesn't affect the logger
+	cfg.Directory = "/elsewhere"
+	cfg.Level = "debug"
+	asrt.Equal(testDir, logger.Config().Directory)
+	asrt.Equal("warn", logger.Level())
+
+	// Runtime level changes are reflected
+	asrt.NoError(logger.SetLevel("error"))
+	asrt.Equal("error", logger.Config().Level)
+}
+
+func TestLog_LogConfig(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix("").WithFilename("app"))
+
+	logger.LogConfig()
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	asrt.Equal(zapcore.InfoLevel, entries[0].Level)
+
+	fields := entries[0].ContextMap()
+	asrt.Equal("app", fields["filename"])
+	asrt.Equal("info", fields["level"])
+	asrt.Equal(int64(DefaultMaxSize), fields["max_size"])
+	asrt.Contains(fields, "console_output")
+}
+
+func TestLog_Rotate(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_rotate"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithFilename("app").
+		WithDisableSplitError(false).
+		WithConsoleOutput(false))
+	defer logger.Close()
+
+	logger.Info("before rotation")
+	logger.Error("error before rotation")
+
+	require.NoError(t, logger.Rotate())
+
+	logger.Info("after rotation")
+
+	// The old content moved to timestamped backups of both files
+	mainBackups, err := filepath.Glob(filepath.Join(testDir, "app-"+logger.currDate+"-*T*.log"))
+	require.NoError(t, err)
+	require.Len(t, mainBackups, 1)
+	content, err := os.ReadFile(mainBackups[0])
+	require.NoError(t, err)
+	asrt.Contains(string(content), "before rotation")
+
+	errBackups, err := filepath.Glob(filepath.Join(testDir, "app-"+logger.currDate+"_error-*T*.log"))
+	require.NoError(t, err)
+	asrt.Len(errBackups, 1)
+
+	// The active file is fresh
+	content, err = os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	asrt.NotContains(string(content), "before rotation")
+	asrt.Contains(string(content), "after rotation")
+
+	// Rotating a logger that never opened its files is a no-op
+	asrt.NoError(NewNop().Rotate())
+}
+
+func TestLog_CachedSugar(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_cached_sugar"
+	defer os.RemoveAll(testDir)
+
+	core, _ := observer.New(zapcore.InfoLevel)
+	for _, logger := range []*Log{
+		NewLog(NewOptions().WithDirectory(testDir).WithConsoleOutput(false)),
+		NewLogWithCore(core, NewOptions()),
+		NewNop(),
+	} {
+		require.NotNil(t, logger.sugar)
+		asrt.Equal(logger.log.Core(), logger.sugar.Desugar().Core())
+	}
+}
+
+func TestPrependPrefix(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	buf := buffer.NewPool().Get()
+	buf.AppendString("encoded entry\n")
+
+	prefixed := prependPrefix(buf, "PRE_")
+	asrt.Same(buf, prefixed, "the entry is moved in place")
+	asrt.Equal("PRE_encoded entry\n", prefixed.String())
+
+	// Prefixes longer than the entry
+	buf.Reset()
+	buf.AppendString("e\n")
+	asrt.Equal("LONG_PREFIX_e\n", prependPrefix(buf, "LONG_PREFIX_").String())
+	buf.Free()
+}
+
+func TestLog_PerInstancePrefix(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	dirA := "./logs/test_logs_prefix_a"
+	dirB := "./logs/test_logs_prefix_b"
+	defer os.RemoveAll(dirA)
+	defer os.RemoveAll(dirB)
+
+	loggerA := NewLog(NewOptions().WithDirectory(dirA).WithPrefix("ALPHA_").WithConsoleOutput(false))
+	loggerB := NewLog(NewOptions().WithDirectory(dirB).WithPrefix("BRAVO_").WithConsoleOutput(false))
+	defer loggerA.Close()
+	defer loggerB.Close()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			loggerA.Infow("from a", "i", i)
+		}()
+		go func() {
+			defer wg.Done()
+			loggerB.Infow("from b", "i", i)
+		}()
+	}
+	wg.Wait()
+
+	for _, tc := range []struct {
+		logger     *Log
+		dir        string
+		own, other string
+		ownMessage string
+	}{
+		{loggerA, dirA, "ALPHA_", "BRAVO_", "from a"},
+		{loggerB, dirB, "BRAVO_", "ALPHA_", "from b"},
+	} {
+		content, err := os.ReadFile(filepath.Join(tc.dir, tc.logger.generateFileName(tc.logger.currDate, false)))
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		entries := 0
+		for _, line := range lines {
+			asrt.True(strings.HasPrefix(line, tc.own), "line %q should start with %s", line, tc.own)
+			asrt.Contains(line, tc.ownMessage)
+			asrt.NotContains(line, tc.other)
+			entries++
+		}
+		asrt.Equal(50, entries)
+	}
+}
@@ -0,0 +1,96 @@
+// Package logaudit records audit events, e.g. for compliance: who (the actor) did what (the
+// action) on which resource, and with which result. Events are structured entries with the
+// same reserved keys in every service, so audit trails can be queried uniformly. When is the
+// time of the entry.
+//
+// Events can go to the application log, or to a dedicated audit file with NewLogger:
+//
+//	audit := logaudit.NewLogger(log.NewOptions().WithDirectory("/var/log/myapp"))
+//	defer audit.Close()
+//
+//	logaudit.Event(audit, "alice", "delete", "invoice/42", logaudit.ResultSuccess, "ip", ip)
+package logaudit
+
+import (
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/kydenul/log"
+)
+
+// Message is the message of the audit entries.
+const Message = "audit"
+
+// Reserved keys of the audit entries.
+const (
+	KeyAudit    = "audit" // always true, to filter audit entries out of an application log
+	KeyActor    = "actor"
+	KeyAction   = "action"
+	KeyResource = "resource"
+	KeyResult   = "result"
+)
+
+// Common results of audited actions.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultDenied  = "denied"
+)
+
+// DefaultFilename is the filename prefix of the audit files written by NewLogger.
+const DefaultFilename = "audit"
+
+// reservedKeys lists the keys set by Event, which can't be overridden by its key-value pairs.
+var reservedKeys = []string{KeyAudit, KeyActor, KeyAction, KeyResource, KeyResult}
+
+// Event logs an audit event at info level: the actor performed the action on the resource,
+// with the given result, e.g. ResultSuccess. The key-value pairs add details, e.g. the IP
+// address of the actor; pairs using a reserved key are dropped, so they can't forge the
+// event.
+func Event(logger log.Logger, actor, action, resource, result string, kv ...any) {
+	if logger == nil {
+		return
+	}
+
+	// The caller of Event is the one recorded in the entry
+	if l, ok := logger.(*log.Log); ok {
+		logger = l.WithOptions(zap.AddCallerSkip(1))
+	}
+
+	fields := make([]any, 0, 2*len(reservedKeys)+len(kv))
+	fields = append(fields,
+		KeyAudit, true,
+		KeyActor, actor,
+		KeyAction, action,
+		KeyResource, resource,
+		KeyResult, result,
+	)
+	for i := 0; i < len(kv); i += 2 {
+		if key, ok := kv[i].(string); ok && slices.Contains(reservedKeys, key) {
+			continue
+		}
+		fields = append(fields, kv[i:min(i+2, len(kv))]...)
+	}
+
+	logger.Infow(Message, fields...)
+}
+
+// NewLogger returns an isolated logger writing audit events to dedicated JSON files, named
+// after DefaultFilename unless opts.Filename is set. Its level is info, so audit events are
+// never filtered. opts is not modified; nil means the default options.
+func NewLogger(opts *log.Options) *log.Log {
+	if opts == nil {
+		opts = log.NewOptions()
+	}
+
+	auditOpts := *opts
+	if auditOpts.Filename == "" {
+		auditOpts.Filename = DefaultFilename
+	}
+	auditOpts.Level = zap.InfoLevel.String()
+	auditOpts.Format = log.FormatJSON
+	auditOpts.Isolated = true
+
+	return log.NewLog(&auditOpts)
+}
@@ -0,0 +1,74 @@
+package logaudit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/kydenul/log"
+	"github.com/kydenul/log/logtest"
+)
+
+func TestEvent(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := log.NewLogWithCore(core, log.NewOptions().WithPrefix(""))
+
+	Event(logger, "alice", "delete", "invoice/42", ResultDenied,
+		"ip", "10.0.0.1", KeyActor, "mallory")
+
+	entries := recorded.TakeAll()
+	require.Len(t, entries, 1)
+	asrt.Equal(Message, entries[0].Message)
+	asrt.Equal(zapcore.InfoLevel, entries[0].Level)
+	asrt.Contains(entries[0].Caller.File, "logaudit_test.go")
+
+	fields := entries[0].ContextMap()
+	asrt.Equal(true, fields["audit"])
+	asrt.Equal("alice", fields["actor"], "reserved keys can't be overridden")
+	asrt.Equal("delete", fields["action"])
+	asrt.Equal("invoice/42", fields["resource"])
+	asrt.Equal("denied", fields["result"])
+	asrt.Equal("10.0.0.1", fields["ip"])
+
+	Event(nil, "alice", "delete", "invoice/42", ResultSuccess)
+}
+
+func TestNewLogger(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := t.TempDir()
+	opts := log.NewOptions().WithDirectory(testDir).WithLevel("error").WithConsoleOutput(false).WithPrefix("")
+	audit := NewLogger(opts)
+
+	Event(audit, "bob", "login", "session", ResultSuccess)
+	require.NoError(t, audit.Sync())
+
+	// opts is left unchanged
+	asrt.Equal("error", opts.Level)
+	asrt.Empty(opts.Filename)
+
+	files := audit.PlannedFiles()
+	require.NotEmpty(t, files)
+	asrt.True(strings.HasPrefix(filepath.Base(files[0]), DefaultFilename+"-"))
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	entry, err := logtest.ParseJSONLog(strings.TrimSpace(string(content)), "")
+	require.NoError(t, err)
+	asrt.Equal("info", entry["level"])
+	asrt.Equal(Message, entry["msg"])
+	asrt.Equal("bob", entry["actor"])
+	asrt.Equal("login", entry["action"])
+	asrt.Equal("session", entry["resource"])
+	asrt.Equal("success", entry["result"])
+}
@@ -0,0 +1,234 @@
+package log
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// logFile is the destination of the main or error log entries. The default implementation
+// is a lumberjack.Logger rotating by size, or an appendFile if DisableRotation is set, but
+// any writer can be used; files that can be rotated implement rotator.
+type logFile interface {
+	io.WriteCloser
+}
+
+// rotator is implemented by the log files supporting Rotate.
+type rotator interface {
+	Rotate() error
+}
+
+// newLogFile returns the log file writing to path, according to the rotation settings.
+func (l *Log) newLogFile(path string) logFile {
+	if l.opts.DisableRotation {
+		return &appendFile{Filename: path, Mode: l.opts.FileMode}
+	}
+
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    l.opts.MaxSize,    // megabytes
+		MaxBackups: l.opts.MaxBackups, // number of backups
+		Compress:   l.gzipBackups(),   // compress rotated files
+	}
+}
+
+// logFiles is the registry of the log files opened by the loggers of the process, keyed by
+// resolved path, so the loggers writing to the same file share a single writer: the file is
+// rotated once for all of them, with a single file descriptor. Since lumberjack.Logger and
+// appendFile lock every write, entries of different loggers never interleave either.
+var logFiles = struct {
+	sync.Mutex
+	byPath map[string]*sharedLogFile
+	paths  map[logFile]string // Registry key of every shared file
+}{
+	byPath: make(map[string]*sharedLogFile),
+	paths:  make(map[logFile]string),
+}
+
+// sharedLogFile is a log file of the registry, with the number of loggers writing to it.
+type sharedLogFile struct {
+	file logFile
+	refs int
+}
+
+// openLogFile returns the log file writing to path, shared with the other loggers writing
+// to the same file. The rotation settings of the first logger opening the file apply. The
+// file must be released with releaseLogFile once the logger no longer writes to it.
+func (l *Log) openLogFile(path string) logFile {
+	key := resolvedPath(path)
+
+	logFiles.Lock()
+	defer logFiles.Unlock()
+
+	if shared, ok := logFiles.byPath[key]; ok {
+		shared.refs++
+		return shared.file
+	}
+
+	file := l.newLogFile(path)
+	logFiles.byPath[key] = &sharedLogFile{file: file, refs: 1}
+	logFiles.paths[file] = key
+	return file
+}
+
+// releaseLogFile releases a log file returned by openLogFile, closing it when the last
+// logger writing to it releases it. Other log files are closed right away.
+func releaseLogFile(file logFile) error {
+	if file == nil {
+		return nil
+	}
+
+	logFiles.Lock()
+	if key, ok := logFiles.paths[file]; ok {
+		shared := logFiles.byPath[key]
+		if shared.refs--; shared.refs > 0 {
+			logFiles.Unlock()
+			return nil
+		}
+		delete(logFiles.byPath, key)
+		delete(logFiles.paths, file)
+	}
+	logFiles.Unlock()
+
+	return file.Close()
+}
+
+// resolvedPath returns the absolute path of the log file, with the symbolic links of its
+// directory resolved, so the different paths of a file share the same registry key.
+func resolvedPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		return filepath.Join(dir, filepath.Base(abs))
+	}
+	return abs
+}
+
+// logFileMode returns the permissions of the file created by the log file: lumberjack
+// creates its files readable by the owner only.
+func logFileMode(file logFile) os.FileMode {
+	if _, ok := file.(*lumberjack.Logger); ok {
+		return 0o600
+	}
+	return 0o644
+}
+
+// fileMode returns the permissions of the log file: Options.FileMode if set, or the
+// default of the log file.
+func (l *Log) fileMode(file logFile) os.FileMode {
+	if l.opts.FileMode != 0 {
+		return l.opts.FileMode
+	}
+	return logFileMode(file)
+}
+
+// dirMode returns the permissions of the directories created for the log files.
+func (l *Log) dirMode() os.FileMode {
+	if l.opts.DirMode == 0 {
+		return DefaultDirMode
+	}
+	return l.opts.DirMode
+}
+
+// syncFile commits the data written to the log file to stable storage, writing its pending
+// gzip member first if CompressActive is set. It is a no-op for writers that aren't files.
+func (l *Log) syncFile(file logFile) error {
+	if err := l.flushActive(file, false); err != nil {
+		return err
+	}
+
+	if f, ok := file.(*appendFile); ok {
+		return f.Sync()
+	}
+
+	path := logFileName(file)
+	if path == "" {
+		return nil
+	}
+
+	// lumberjack doesn't expose its file, but fsync commits the data of the file written
+	// through any descriptor
+	f, err := os.OpenFile(path, os.O_WRONLY, 0) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
+// logFileName returns the path of the log file, or an empty string if it's not a file.
+func logFileName(file logFile) string {
+	switch f := file.(type) {
+	case *lumberjack.Logger:
+		return f.Filename
+	case *appendFile:
+		return f.Filename
+	default:
+		return ""
+	}
+}
+
+// appendFile is a plain log file opened in append mode, never rotated by size. Since
+// every write appends to the end of the file, it works with external rotation that
+// truncates the file in place (logrotate's copytruncate).
+//
+// The file is opened on the first write, and reopened by the next write after Close or
+// Rotate, so a file moved away by an external tool is recreated.
+type appendFile struct {
+	Filename string
+	Mode     os.FileMode // permissions of the file when created; zero means 0o644
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// Write appends p to the file, opening it if needed.
+func (f *appendFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		mode := f.Mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		file, err := os.OpenFile(f.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, mode) //nolint:gosec
+		if err != nil {
+			return 0, err
+		}
+		f.file = file
+	}
+	return f.file.Write(p)
+}
+
+// Close closes the file, if open.
+func (f *appendFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// Sync commits the content of the file to stable storage, if open.
+func (f *appendFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Rotate closes the file, so the next write reopens it at Filename.
+func (f *appendFile) Rotate() error { return f.Close() }
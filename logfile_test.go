@@ -0,0 +1,486 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisableRotation(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_disable_rotation"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Filename("plain").
+		Prefix("").
+		MaxSize(1). // 1MB, exceeded below
+		DisableSplitError(false).
+		DisableRotation(true).
+		ConsoleOutput(false).
+		Build()
+
+	payload := strings.Repeat("x", 1024)
+	for range 1200 { // About 1.2MB
+		logger.Info(payload)
+	}
+	logger.Error("error entry")
+	require.NoError(t, logger.Sync())
+
+	// No size-based rotation: only the main and error files, the main one over MaxSize
+	files, err := filepath.Glob(filepath.Join(testDir, "*"))
+	require.NoError(t, err)
+	asrt.Len(files, 2)
+
+	mainPath := filepath.Join(testDir, logger.generateFileName(logger.currDate, false))
+	info, err := os.Stat(mainPath)
+	require.NoError(t, err)
+	asrt.True(info.Mode().IsRegular())
+	asrt.Greater(info.Size(), int64(1024*1024))
+	asrt.IsType(&appendFile{}, logger.file)
+	asrt.IsType(&appendFile{}, logger.errFile)
+
+	// Truncating the file in place (copytruncate) doesn't leave a gap before new entries
+	require.NoError(t, os.Truncate(mainPath, 0))
+	logger.Info("after truncate")
+	require.NoError(t, logger.Sync())
+
+	content, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	asrt.True(strings.HasPrefix(string(content), "20"), "file must start with the entry")
+	asrt.Contains(string(content), "after truncate")
+	asrt.NotContains(string(content), "\x00")
+}
+
+func TestDisableRotation_Rotate(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_disable_rotation_rotate"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		DisableRotation(true).
+		ConsoleOutput(false).
+		Build()
+	defer logger.Close()
+
+	logger.Info("before move")
+	mainPath := filepath.Join(testDir, logger.generateFileName(logger.currDate, false))
+	movedPath := mainPath + ".1"
+
+	// Like logrotate with create: move the file away, then ask the logger to reopen it
+	require.NoError(t, os.Rename(mainPath, movedPath))
+	require.NoError(t, logger.Rotate())
+	logger.Info("after move")
+
+	content, err := os.ReadFile(movedPath)
+	require.NoError(t, err)
+	asrt.Contains(string(content), "before move")
+	asrt.NotContains(string(content), "after move")
+
+	content, err = os.ReadFile(mainPath)
+	require.NoError(t, err)
+	asrt.Contains(string(content), "after move")
+
+	// No timestamped backup is created
+	backups, err := filepath.Glob(filepath.Join(testDir, "*T*.log"))
+	require.NoError(t, err)
+	asrt.Empty(backups)
+}
+
+// flakyFile is an in-memory log file failing its first writes.
+type flakyFile struct {
+	bytes.Buffer
+	failures int
+	closed   bool
+}
+
+func (f *flakyFile) Write(p []byte) (int, error) {
+	if f.failures > 0 {
+		f.failures--
+		return 0, errors.New("transient failure")
+	}
+	return f.Buffer.Write(p)
+}
+
+func (f *flakyFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestWriteToFile_CustomWriter(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	logger := &Log{opts: NewOptions()}
+
+	// Transient failures are retried
+	file := &flakyFile{failures: MaxRetries - 1}
+	require.NoError(t, logger.writeToFile(file, []byte("entry\n")))
+	asrt.Equal("entry\n", file.String())
+
+	// Persistent failures are reported after MaxRetries attempts
+	file = &flakyFile{failures: MaxRetries}
+	err := logger.writeToFile(file, []byte("entry\n"))
+	asrt.ErrorContains(err, "failed to write after retries: transient failure")
+	asrt.Empty(file.String())
+}
+
+func TestRotate_SkipsNonRotatableFiles(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	file := &flakyFile{}
+	logger := &Log{opts: NewOptions(), file: file}
+
+	asrt.NoError(logger.Rotate())
+	asrt.False(file.closed)
+	asrt.Empty(logFileName(file))
+}
+
+func TestPlannedFiles(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_planned_files"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Filename("app").
+		FilenamePattern("{name}/{date}/{level}.log").
+		ConsoleOutput(false).
+		DisableSplitError(false).
+		SplitFatal(true).
+		Isolated(true).
+		Build()
+	defer logger.Close()
+
+	planned := logger.PlannedFiles()
+	require.Len(t, planned, 3)
+	asrt.Equal(filepath.Join(logger.logDir, "app", time.Now().Format(time.DateOnly), "main.log"), planned[0])
+	asrt.NoDirExists(testDir, "planning doesn't create anything")
+
+	logger.Info("started")
+	require.NoError(t, logger.Sync())
+
+	var created []string
+	require.NoError(t, filepath.WalkDir(logger.logDir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			created = append(created, path)
+		}
+		return err
+	}))
+	asrt.ElementsMatch(planned, created)
+
+	// Derived loggers write to the same files
+	asrt.Equal(planned, logger.WithOptions().PlannedFiles())
+
+	// Without split files only the main log file is written, and none with syslog only
+	plain := NewLog(NewOptions().WithDirectory(testDir).WithConsoleOutput(false).WithIsolated(true))
+	asrt.Equal([]string{filepath.Join(plain.logDir, time.Now().Format(time.DateOnly)+".log")}, plain.PlannedFiles())
+	plain.disableFile = true
+	asrt.Nil(plain.PlannedFiles())
+}
+
+func TestSharedLogFiles(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_shared_files"
+	defer os.RemoveAll(testDir)
+
+	newLogger := func() *Log {
+		return NewBuilder().
+			Directory(testDir).
+			Filename("shared").
+			MaxSize(1).
+			ConsoleOutput(false).
+			DisableSplitError(false).
+			Isolated(true).
+			Build()
+	}
+	first, second := newLogger(), newLogger()
+	defer first.Close()
+	defer second.Close()
+
+	first.Info("opened")
+	second.Info("opened")
+	require.NotNil(t, first.file)
+	asrt.Same(first.file, second.file, "same path, same file handle")
+	asrt.Same(first.errFile, second.errFile)
+
+	// Both loggers count towards the same 1MB, so the file is rotated once
+	payload := strings.Repeat("x", 1000)
+	for i := range 1500 {
+		[]*Log{first, second}[i%2].Info(payload)
+	}
+	require.NoError(t, first.Sync())
+	require.NoError(t, second.Sync())
+
+	backups, err := filepath.Glob(filepath.Join(first.logDir, "shared-*-*T*.log"))
+	require.NoError(t, err)
+	asrt.Len(backups, 1, "a single rotation")
+
+	// The file is released by both loggers when they move to the next day
+	key := resolvedPath(logFileName(first.file))
+	logFiles.Lock()
+	asrt.Equal(2, logFiles.byPath[key].refs)
+	logFiles.Unlock()
+
+	require.NoError(t, first.setupLogFiles("2099-01-01"))
+	logFiles.Lock()
+	asrt.Equal(1, logFiles.byPath[key].refs)
+	logFiles.Unlock()
+
+	require.NoError(t, second.setupLogFiles("2099-01-01"))
+	logFiles.Lock()
+	asrt.NotContains(logFiles.byPath, key, "closed when the last logger releases it")
+	logFiles.Unlock()
+	asrt.Same(first.file, second.file)
+}
+
+func TestSharedLogFiles_Close(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_shared_files_close"
+	defer os.RemoveAll(testDir)
+
+	newLogger := func(maxSize int) *Log {
+		return NewBuilder().
+			Directory(testDir).
+			Filename("swapped").
+			MaxSize(maxSize).
+			ConsoleOutput(false).
+			DisableSplitError(false).
+			SplitFatal(true).
+			Isolated(true).
+			Build()
+	}
+
+	// Closing the logger releases its files from the registry
+	old := newLogger(100)
+	old.Error("opened")
+	keys := []string{
+		resolvedPath(logFileName(old.file)),
+		resolvedPath(logFileName(old.errFile)),
+		resolvedPath(logFileName(old.fatalFile)),
+	}
+	require.NoError(t, old.Close())
+	logFiles.Lock()
+	for _, key := range keys {
+		asrt.NotContains(logFiles.byPath, key, "closed when the last logger releases it")
+	}
+	logFiles.Unlock()
+
+	// A new logger for the same files uses its own rotation settings
+	current := newLogger(1)
+	defer current.Close()
+	payload := strings.Repeat("x", 1000)
+	for range 1500 {
+		current.Info(payload)
+	}
+	require.NoError(t, current.Sync())
+
+	backups, err := filepath.Glob(filepath.Join(current.logDir, "swapped-*-*T*.log"))
+	require.NoError(t, err)
+	asrt.Len(backups, 1, "rotated at 1MB, not 100MB")
+}
+
+func TestFileModes(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permissions")
+	}
+
+	for _, disableRotation := range []bool{false, true} {
+		t.Run(fmt.Sprintf("disable rotation %t", disableRotation), func(t *testing.T) {
+			t.Parallel()
+			asrt := assert.New(t)
+
+			testDir := fmt.Sprintf("./logs/test_logs_file_modes_%t", disableRotation)
+			defer os.RemoveAll(testDir)
+
+			logger := NewBuilder().
+				Directory(filepath.Join(testDir, "private")).
+				FilenamePattern("{date}/{level}.log").
+				ConsoleOutput(false).
+				DisableSplitError(false).
+				DisableRotation(disableRotation).
+				DirMode(0o700).
+				FileMode(0o640).
+				Isolated(true).
+				Build()
+			defer logger.Close()
+
+			logger.Error("written")
+			require.NoError(t, logger.Sync())
+
+			planned := logger.PlannedFiles()
+			require.Len(t, planned, 2)
+			for _, path := range planned {
+				info, err := os.Stat(path)
+				require.NoError(t, err)
+				asrt.Equal(os.FileMode(0o640), info.Mode().Perm(), path)
+			}
+
+			for _, dir := range []string{logger.logDir, filepath.Dir(planned[0])} {
+				info, err := os.Stat(dir)
+				require.NoError(t, err)
+				asrt.Equal(os.FileMode(0o700), info.Mode().Perm(), dir)
+			}
+		})
+	}
+}
+
+func TestFileModes_Validation(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	opts := NewOptions()
+	asrt.Equal(os.FileMode(DefaultDirMode), opts.DirMode)
+	asrt.Zero(opts.FileMode)
+
+	err := NewOptions().WithDirMode(os.ModeDir | 0o700).WithFileMode(os.ModeSetuid | 0o600).Validate()
+	require.Error(t, err)
+	asrt.Contains(err.Error(), "invalid dir mode")
+	asrt.Contains(err.Error(), "invalid file mode")
+}
+
+func TestRelativeTo(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	exe, err = filepath.EvalSymlinks(exe)
+	require.NoError(t, err)
+
+	testDir := "logs/test_logs_relative_to_exe"
+	defer os.RemoveAll(filepath.Join(filepath.Dir(exe), testDir))
+
+	logger := NewBuilder().
+		Directory(testDir).
+		RelativeTo(RelativeToExe).
+		ConsoleOutput(false).
+		Isolated(true).
+		Build()
+	defer logger.Close()
+
+	asrt.Equal(filepath.Join(filepath.Dir(exe), testDir), logger.logDir)
+	logger.Info("next to the binary")
+	require.NoError(t, logger.Sync())
+	planned := logger.PlannedFiles()
+	require.Len(t, planned, 1)
+	asrt.FileExists(planned[0])
+
+	// The working directory is resolved once, when the logger is created
+	cwdDir := "./logs/test_logs_relative_to_cwd"
+	cwd := NewLog(NewOptions().WithDirectory(cwdDir).WithRelativeTo(RelativeToCWD).WithIsolated(true))
+	defer cwd.Close()
+	abs, err := filepath.Abs(cwdDir)
+	require.NoError(t, err)
+	asrt.Equal(abs, cwd.logDir)
+
+	// Absolute directories are never anchored
+	absolute := NewLog(NewOptions().WithDirectory(abs).WithRelativeTo(RelativeToExe).WithIsolated(true))
+	defer absolute.Close()
+	asrt.Equal(abs, absolute.logDir)
+
+	// Without an anchor the directory is kept as is
+	plain := NewLog(NewOptions().WithDirectory(cwdDir).WithIsolated(true))
+	defer plain.Close()
+	asrt.Equal(cwdDir, plain.logDir)
+}
+
+func TestRelativeTo_Config(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, "log.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("directory: logs\nrelative_to: config\n"), 0o600))
+
+	opts, err := LoadFromFile(configPath)
+	require.NoError(t, err)
+	asrt.Equal(filepath.Join(configDir, "logs"), opts.Directory)
+
+	err = NewOptions().WithRelativeTo("home").Validate()
+	require.Error(t, err)
+	asrt.Contains(err.Error(), "invalid relative to")
+
+	// NewLog falls back to the directory as is
+	logger := NewLog(NewOptions().WithDirectory("./logs/test_logs_relative_to_invalid").
+		WithRelativeTo("home").WithConsoleOutput(false).WithIsolated(true))
+	defer logger.Close()
+	asrt.Equal("./logs/test_logs_relative_to_invalid", logger.logDir)
+}
+
+func TestSyncOnError(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_sync_on_error"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		ConsoleOutput(false).
+		DisableSplitError(false).
+		SyncOnError(true).
+		Isolated(true).
+		Build()
+	defer logger.Close()
+
+	logger.Errorw("payment failed", "order_id", 42)
+
+	// The entry is on disk without Sync
+	planned := logger.PlannedFiles()
+	require.Len(t, planned, 2)
+	for _, path := range planned {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		asrt.Contains(string(content), "payment failed", path)
+	}
+}
+
+func TestSyncOnError_CompressActive(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_sync_on_error_gzip"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		ConsoleOutput(false).
+		CompressActive(true).
+		SyncOnError(true).
+		Isolated(true).
+		Build()
+	defer logger.Close()
+
+	logger.Info("buffered")
+	logger.Error("payment failed")
+
+	// The gzip member holding the buffered entries is written without Sync
+	content := readGzipFile(t, logger.PlannedFiles()[0])
+	asrt.Contains(content, "buffered")
+	asrt.Contains(content, "payment failed")
+}
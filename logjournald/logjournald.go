@@ -0,0 +1,208 @@
+// Package logjournald sends log entries to the systemd journal with its native protocol,
+// keeping the fields of the entries as journal fields and their level as the journal
+// priority, which is lost when systemd captures the console output.
+//
+// Use it with log.NewLogWithCore, or combine it with a file logger with log.Tee:
+//
+//	core, err := logjournald.JournaldCore(opts)
+//	if err != nil {
+//	    return err
+//	}
+//	logger := log.NewLogWithCore(core, opts)
+package logjournald
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kydenul/log"
+)
+
+// SocketPath is the path of the socket of the journal daemon.
+const SocketPath = "/run/systemd/journal/socket"
+
+// maxFieldNameLength is the maximum length of the journal field names.
+const maxFieldNameLength = 64
+
+// JournaldCore returns a core sending the entries enabled by opts.Level to the systemd
+// journal. Entries have the MESSAGE, PRIORITY and SYSLOG_IDENTIFIER fields, the caller as
+// CODE_FILE, CODE_LINE and CODE_FUNC, and their fields with names converted to journal
+// field names, e.g. "user_id" as USER_ID. The identifier is opts.Syslog.Tag, or the
+// program name if empty.
+//
+// It returns an error if the journal is not available, e.g. on other platforms than Linux
+// or when the service is not managed by systemd.
+func JournaldCore(opts *log.Options) (zapcore.Core, error) {
+	if opts == nil {
+		opts = log.NewOptions()
+	}
+
+	level, err := zap.ParseAtomicLevel(opts.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid level: %w", err)
+	}
+
+	identifier := opts.Syslog.Tag
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+
+	return newCore(SocketPath, level, identifier)
+}
+
+// journalCore is the zapcore.Core sending entries to the journal through its sender,
+// implemented per platform.
+type journalCore struct {
+	zapcore.LevelEnabler
+
+	identifier string
+	context    []zapcore.Field
+	sender     *sender
+}
+
+func (c *journalCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.context = append(slices.Clip(c.context), fields...)
+	return &clone
+}
+
+func (c *journalCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *journalCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.sender.send(encodeEntry(c.identifier, entry, append(slices.Clip(c.context), fields...)))
+}
+
+func (c *journalCore) Sync() error { return nil }
+
+// encodeEntry encodes the entry and its fields as a journal message of the native protocol.
+func encodeEntry(identifier string, entry zapcore.Entry, fields []zapcore.Field) []byte {
+	var data []byte
+	data = appendField(data, "MESSAGE", entry.Message)
+	data = appendField(data, "PRIORITY", strconv.Itoa(priority(entry.Level)))
+	data = appendField(data, "SYSLOG_IDENTIFIER", identifier)
+	if entry.LoggerName != "" {
+		data = appendField(data, "LOGGER", entry.LoggerName)
+	}
+	if entry.Caller.Defined {
+		data = appendField(data, "CODE_FILE", entry.Caller.File)
+		data = appendField(data, "CODE_LINE", strconv.Itoa(entry.Caller.Line))
+		if entry.Caller.Function != "" {
+			data = appendField(data, "CODE_FUNC", entry.Caller.Function)
+		}
+	}
+	if entry.Stack != "" {
+		data = appendField(data, "STACKTRACE", entry.Stack)
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	keys := make([]string, 0, len(enc.Fields))
+	for key := range enc.Fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if name := fieldName(key); name != "" {
+			data = appendField(data, name, fieldValue(enc.Fields[key]))
+		}
+	}
+
+	return data
+}
+
+// appendField appends a field of the native protocol to data: "NAME=value\n", or the name,
+// the length as a 64-bit little endian integer and the value for values with newlines.
+func appendField(data []byte, name, value string) []byte {
+	if !strings.Contains(value, "\n") {
+		data = append(data, name...)
+		data = append(data, '=')
+		data = append(data, value...)
+		return append(data, '\n')
+	}
+
+	data = append(data, name...)
+	data = append(data, '\n')
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(value)))
+	data = append(data, value...)
+	return append(data, '\n')
+}
+
+// fieldName converts a field key to a journal field name, made of uppercase letters,
+// digits and underscores, not starting with an underscore or a digit. It returns an empty
+// string for keys without any valid character.
+func fieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+
+	// Names starting with an underscore are reserved to the journal
+	trimmed := strings.TrimLeft(string(name), "_0123456789")
+	if len(trimmed) > maxFieldNameLength {
+		trimmed = trimmed[:maxFieldNameLength]
+	}
+	return trimmed
+}
+
+// fieldValue formats a value of a zapcore.MapObjectEncoder.
+func fieldValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// priority maps a level to a journal priority, like the syslog severities of the log package.
+func priority(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7 // debug
+	case zapcore.InfoLevel:
+		return 6 // info
+	case zapcore.WarnLevel:
+		return 4 // warning
+	case zapcore.ErrorLevel:
+		return 3 // err
+	case zapcore.DPanicLevel:
+		return 2 // crit
+	case zapcore.PanicLevel:
+		return 1 // alert
+	case zapcore.FatalLevel:
+		return 0 // emerg
+	default:
+		return 6
+	}
+}
@@ -0,0 +1,100 @@
+package logjournald
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func Test_encodeEntry(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	entry := zapcore.Entry{
+		Level:   zapcore.WarnLevel,
+		Message: "disk almost full",
+		Caller:  zapcore.EntryCaller{Defined: true, File: "/src/app/main.go", Line: 42, Function: "main.run"},
+	}
+	fields := []zapcore.Field{
+		zap.String("user-id", "u1"),
+		zap.Int("usage", 93),
+		zap.Duration("elapsed", 1500*time.Millisecond),
+		zap.Error(errors.New("no space")),
+		zap.Any("tags", map[string]any{"env": "prod"}),
+	}
+
+	asrt.Equal("MESSAGE=disk almost full\n"+
+		"PRIORITY=4\n"+
+		"SYSLOG_IDENTIFIER=app\n"+
+		"CODE_FILE=/src/app/main.go\n"+
+		"CODE_LINE=42\n"+
+		"CODE_FUNC=main.run\n"+
+		"ELAPSED=1.5s\n"+
+		"ERROR=no space\n"+
+		"TAGS={\"env\":\"prod\"}\n"+
+		"USAGE=93\n"+
+		"USER_ID=u1\n",
+		string(encodeEntry("app", entry, fields)))
+}
+
+func Test_appendField(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	asrt.Equal("KEY=value\n", string(appendField(nil, "KEY", "value")))
+	asrt.Equal("KEY=\n", string(appendField(nil, "KEY", "")))
+
+	// Values with newlines are prefixed with their length
+	data := appendField(nil, "STACKTRACE", "line 1\nline 2")
+	asrt.Equal("STACKTRACE\n", string(data[:11]))
+	asrt.Equal(uint64(13), binary.LittleEndian.Uint64(data[11:19]))
+	asrt.Equal("line 1\nline 2\n", string(data[19:]))
+}
+
+func Test_fieldName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"user_id", "USER_ID"},
+		{"request.id", "REQUEST_ID"},
+		{"_internal", "INTERNAL"},
+		{"2fa", "FA"},
+		{"ünicode", "NICODE"},
+		{"---", ""},
+		{strings.Repeat("a", 100), strings.Repeat("A", maxFieldNameLength)},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fieldName(tt.key), "key %q", tt.key)
+	}
+}
+
+func Test_priority(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		level zapcore.Level
+		want  int
+	}{
+		{zapcore.DebugLevel, 7},
+		{zapcore.InfoLevel, 6},
+		{zapcore.WarnLevel, 4},
+		{zapcore.ErrorLevel, 3},
+		{zapcore.DPanicLevel, 2},
+		{zapcore.PanicLevel, 1},
+		{zapcore.FatalLevel, 0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, priority(tt.level), "level %s", tt.level)
+	}
+}
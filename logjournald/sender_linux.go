@@ -0,0 +1,68 @@
+//go:build linux
+
+package logjournald
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// sender sends journal messages to the socket of the journal daemon.
+type sender struct {
+	conn *net.UnixConn
+	addr *net.UnixAddr
+}
+
+// newCore returns a core sending entries to the journal socket at path.
+func newCore(path string, enab zapcore.LevelEnabler, identifier string) (zapcore.Core, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("journald is not available: %w", err)
+	}
+
+	// Unconnected, so messages still reach the journal after it restarts
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journald socket: %w", err)
+	}
+
+	return &journalCore{
+		LevelEnabler: enab,
+		identifier:   identifier,
+		sender:       &sender{conn: conn, addr: &net.UnixAddr{Name: path, Net: "unixgram"}},
+	}, nil
+}
+
+// send sends the message in a datagram, or through a temporary file passed to the journal
+// if it's too large for a datagram.
+func (s *sender) send(data []byte) error {
+	_, _, err := s.conn.WriteMsgUnix(data, nil, s.addr)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return fmt.Errorf("failed to send to journald: %w", err)
+	}
+
+	file, err := os.CreateTemp("/dev/shm", "journal.*")
+	if err != nil {
+		file, err = os.CreateTemp("", "journal.*")
+		if err != nil {
+			return fmt.Errorf("failed to send to journald: %w", err)
+		}
+	}
+	defer file.Close()
+	_ = os.Remove(file.Name()) // The journal reads the file through the descriptor
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to send to journald: %w", err)
+	}
+	if _, _, err := s.conn.WriteMsgUnix(nil, syscall.UnixRights(int(file.Fd())), s.addr); err != nil {
+		return fmt.Errorf("failed to send to journald: %w", err)
+	}
+	return nil
+}
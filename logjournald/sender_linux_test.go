@@ -0,0 +1,127 @@
+//go:build linux
+
+package logjournald
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/kydenul/log"
+)
+
+// listenJournal listens on a fake journal socket, returning its path and the connection.
+func listenJournal(t *testing.T) (string, *net.UnixConn) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return path, conn
+}
+
+// readMessage reads the next message sent to the fake journal, in a datagram or through a
+// file descriptor.
+func readMessage(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+
+	buf := make([]byte, 1<<20)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	require.NoError(t, err)
+	if oobn == 0 {
+		return string(buf[:n])
+	}
+
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	require.NoError(t, err)
+	require.Len(t, fds, 1)
+
+	file := os.NewFile(uintptr(fds[0]), "journal")
+	defer file.Close()
+	_, err = file.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestJournalCore(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	path, conn := listenJournal(t)
+	core, err := newCore(path, zapcore.InfoLevel, "billing")
+	require.NoError(t, err)
+
+	opts := log.NewOptions().WithPrefix("").WithIsolated(true)
+	logger := log.NewLogWithCore(core, opts)
+
+	logger.Debug("filtered by level")
+	logger.Errorw("payment failed", "order_id", 42, "reason", "card declined")
+
+	msg := readMessage(t, conn)
+	asrt.Contains(msg, "MESSAGE=payment failed\n")
+	asrt.Contains(msg, "PRIORITY=3\n")
+	asrt.Contains(msg, "SYSLOG_IDENTIFIER=billing\n")
+	asrt.Contains(msg, "CODE_FILE=")
+	asrt.Contains(msg, "ORDER_ID=42\n")
+	asrt.Contains(msg, "REASON=card declined\n")
+
+	// Context fields are kept
+	core.With([]zapcore.Field{zap.String("tenant", "acme")}).
+		Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "with context"}, nil)
+	msg = readMessage(t, conn)
+	asrt.Contains(msg, "MESSAGE=with context\n")
+	asrt.Contains(msg, "TENANT=acme\n")
+}
+
+func TestJournalCore_LargeMessage(t *testing.T) {
+	t.Parallel()
+
+	path, conn := listenJournal(t)
+	core, err := newCore(path, zapcore.InfoLevel, "app")
+	require.NoError(t, err)
+
+	// Larger than the maximum datagram size, sent through a file descriptor
+	payload := strings.Repeat("x", 4<<20)
+	require.NoError(t, core.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "large"},
+		[]zapcore.Field{zap.String("payload", payload)}))
+
+	msg := readMessage(t, conn)
+	assert.Contains(t, msg, "MESSAGE=large\n")
+	assert.Contains(t, msg, "PAYLOAD="+payload+"\n")
+}
+
+func TestJournaldCore(t *testing.T) {
+	t.Parallel()
+
+	if _, err := os.Stat(SocketPath); err != nil {
+		t.Skip("journald is not available:", err)
+	}
+
+	core, err := JournaldCore(log.NewOptions().WithLevel("debug"))
+	require.NoError(t, err)
+	assert.True(t, core.Enabled(zapcore.DebugLevel))
+	assert.NoError(t, core.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "logjournald test"}, nil))
+}
+
+func TestJournaldCore_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	_, err := newCore(filepath.Join(t.TempDir(), "missing.sock"), zapcore.InfoLevel, "app")
+	assert.ErrorContains(t, err, "journald is not available")
+}
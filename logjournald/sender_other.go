@@ -0,0 +1,21 @@
+//go:build !linux
+
+package logjournald
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// sender is never created on this platform.
+type sender struct{}
+
+// newCore always fails, journald is only available on Linux.
+func newCore(string, zapcore.LevelEnabler, string) (zapcore.Core, error) {
+	return nil, errors.New("journald is only supported on Linux")
+}
+
+func (*sender) send([]byte) error {
+	return errors.New("journald is only supported on Linux")
+}
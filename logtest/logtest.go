@@ -0,0 +1,39 @@
+// Package logtest provides helpers for asserting on the output of log.Log in tests.
+package logtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseJSONLog parses a line written by a logger using the JSON format, stripping the
+// logger prefix first (log.Options.Prefix, e.g. "ZIWI_"). An empty prefix means the line
+// is plain JSON. It returns an error if the line doesn't start with the prefix or isn't a
+// JSON object, so malformed lines fail assertions clearly.
+//
+// Example Usage:
+//
+//	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+//		entry, err := logtest.ParseJSONLog(line, "ZIWI_")
+//		require.NoError(t, err)
+//		assert.Equal(t, "info", entry["level"])
+//	}
+func ParseJSONLog(line string, prefix string) (map[string]any, error) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty log line")
+	}
+
+	body, ok := strings.CutPrefix(line, prefix)
+	if !ok {
+		return nil, fmt.Errorf("log line doesn't start with the prefix %q: %s", prefix, line)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(body), &entry); err != nil {
+		return nil, fmt.Errorf("malformed JSON log line %q: %w", line, err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,57 @@
+package logtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONLog(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	// Prefixed line, with the trailing newline of the file
+	entry, err := ParseJSONLog(`ZIWI_{"level":"info","msg":"hello","user_id":42}`+"\n", "ZIWI_")
+	require.NoError(t, err)
+	asrt.Equal(map[string]any{"level": "info", "msg": "hello", "user_id": float64(42)}, entry)
+
+	// Without prefix
+	entry, err = ParseJSONLog(`{"level":"warn","msg":"plain"}`, "")
+	require.NoError(t, err)
+	asrt.Equal("warn", entry["level"])
+	asrt.Equal("plain", entry["msg"])
+
+	// A prefix containing braces is stripped as is
+	entry, err = ParseJSONLog(`{APP}{"msg":"braces"}`, "{APP}")
+	require.NoError(t, err)
+	asrt.Equal("braces", entry["msg"])
+}
+
+func TestParseJSONLog_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		line   string
+		prefix string
+		errMsg string
+	}{
+		{"empty line", "\n", "", "empty log line"},
+		{"missing prefix", `{"msg":"hello"}`, "ZIWI_", `doesn't start with the prefix "ZIWI_"`},
+		{"prefix not stripped", `ZIWI_{"msg":"hello"}`, "", "malformed JSON log line"},
+		{"console format", "2025-07-20 00:00:00.000\tinfo\thello", "", "malformed JSON log line"},
+		{"truncated JSON", `ZIWI_{"msg":"hel`, "ZIWI_", "malformed JSON log line"},
+		{"not an object", `ZIWI_["msg"]`, "ZIWI_", "malformed JSON log line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			entry, err := ParseJSONLog(tt.line, tt.prefix)
+			assert.ErrorContains(t, err, tt.errMsg)
+			assert.Nil(t, entry)
+		})
+	}
+}
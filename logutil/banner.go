@@ -0,0 +1,111 @@
+package logutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kydenul/log"
+)
+
+// bannerOutput receives the ASCII banner, next to the console output of the logger.
+var bannerOutput io.Writer = os.Stdout
+
+// BannerInfo describes the application logged by LogBanner.
+type BannerInfo struct {
+	AppName   string
+	Version   string
+	Commit    string // VCS revision the binary was built from
+	BuildTime string // e.g. injected with -ldflags "-X main.buildTime=..."
+
+	// Go version the binary was built with, runtime.Version() if empty
+	GoVersion string
+
+	// Process ID, os.Getpid() if zero
+	PID int
+
+	// Summary of the configuration worth having in support tickets, e.g. ports, feature
+	// flags or the environment. Avoid secrets, it is logged as is.
+	Config map[string]any
+
+	// Print a framed ASCII banner with the same information before the entry, when the
+	// logger writes to the console in console format. Log aggregators only see the entry.
+	ASCII bool
+}
+
+// LogBanner logs a structured "app started" entry at info level, so the startup line is the
+// same across services. It records app_name, version, commit, build_time, go_version, pid,
+// hostname and config, even when empty, so they can always be queried.
+func LogBanner(logger log.Logger, info BannerInfo) {
+	if logger == nil {
+		return
+	}
+
+	if info.GoVersion == "" {
+		info.GoVersion = runtime.Version()
+	}
+	if info.PID == 0 {
+		info.PID = os.Getpid()
+	}
+	hostname, _ := os.Hostname()
+
+	if info.ASCII && consoleFormat(logger) {
+		fmt.Fprint(bannerOutput, formatBanner(info, hostname))
+	}
+
+	logger.Infow("应用启动",
+		"app_name", info.AppName,
+		"version", info.Version,
+		"commit", info.Commit,
+		"build_time", info.BuildTime,
+		"go_version", info.GoVersion,
+		"pid", info.PID,
+		"hostname", hostname,
+		"config", info.Config,
+	)
+}
+
+// consoleFormat reports whether logger writes human-readable entries to the console.
+func consoleFormat(logger log.Logger) bool {
+	l, ok := logger.(*log.Log)
+	if !ok {
+		return false
+	}
+
+	opts := l.Config()
+	return opts.ConsoleOutput && opts.Format == log.FormatConsole
+}
+
+// formatBanner renders info as a framed block of "key: value" lines.
+func formatBanner(info BannerInfo, hostname string) string {
+	lines := []string{
+		info.AppName,
+		"",
+		"version:    " + info.Version,
+		"commit:     " + info.Commit,
+		"build time: " + info.BuildTime,
+		"go version: " + info.GoVersion,
+		fmt.Sprintf("pid:        %d", info.PID),
+		"hostname:   " + hostname,
+	}
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, utf8.RuneCountInString(line))
+	}
+
+	var sb strings.Builder
+	border := "+" + strings.Repeat("-", width+2) + "+\n"
+	sb.WriteString(border)
+	for _, line := range lines {
+		sb.WriteString("| ")
+		sb.WriteString(line)
+		sb.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(line)))
+		sb.WriteString(" |\n")
+	}
+	sb.WriteString(border)
+	return sb.String()
+}
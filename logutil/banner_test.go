@@ -0,0 +1,119 @@
+package logutil
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/kydenul/log"
+)
+
+func TestLogBanner(t *testing.T) {
+	mock := newMockLogger()
+	config := map[string]any{"port": 8080, "env": "staging"}
+
+	LogBanner(mock, BannerInfo{
+		AppName:   "billing",
+		Version:   "v1.4.2",
+		Commit:    "3f2c9e1",
+		BuildTime: "2024-01-15T10:00:00Z",
+		GoVersion: "go1.23.4",
+		PID:       4242,
+		Config:    config,
+	})
+
+	if len(mock.logs) != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", len(mock.logs))
+	}
+	entry := mock.getLastLog()
+	if entry.level != "infow" || entry.message != "应用启动" {
+		t.Errorf("Expected infow '应用启动', got %s '%s'", entry.level, entry.message)
+	}
+
+	hostname, _ := os.Hostname()
+	for key, value := range map[string]any{
+		"app_name":   "billing",
+		"version":    "v1.4.2",
+		"commit":     "3f2c9e1",
+		"build_time": "2024-01-15T10:00:00Z",
+		"go_version": "go1.23.4",
+		"pid":        4242,
+		"hostname":   hostname,
+	} {
+		if !mock.hasField(key, value) {
+			t.Errorf("Expected %s field with value %v", key, value)
+		}
+	}
+
+	// Maps aren't comparable, look the config up by key
+	var logged map[string]any
+	for i := 0; i < len(entry.fields)-1; i += 2 {
+		if entry.fields[i] == "config" {
+			logged, _ = entry.fields[i+1].(map[string]any)
+		}
+	}
+	if logged["port"] != 8080 || logged["env"] != "staging" {
+		t.Errorf("Expected config field %v, got %v", config, logged)
+	}
+}
+
+func TestLogBanner_Defaults(t *testing.T) {
+	mock := newMockLogger()
+
+	LogBanner(mock, BannerInfo{AppName: "billing"})
+
+	if !mock.hasField("go_version", runtime.Version()) {
+		t.Error("Expected go_version to default to runtime.Version()")
+	}
+	if !mock.hasField("pid", os.Getpid()) {
+		t.Error("Expected pid to default to os.Getpid()")
+	}
+
+	// Empty fields are still present
+	if !mock.hasField("commit", "") || !mock.hasField("build_time", "") {
+		t.Error("Expected empty commit and build_time fields")
+	}
+
+	LogBanner(nil, BannerInfo{AppName: "billing"})
+}
+
+func TestLogBanner_ASCII(t *testing.T) {
+	var out bytes.Buffer
+	bannerOutput = &out
+	defer func() { bannerOutput = os.Stdout }()
+
+	info := BannerInfo{AppName: "billing", Version: "v1.4.2", PID: 4242, ASCII: true}
+
+	// Only for a console logger in console format
+	LogBanner(newMockLogger(), info)
+	jsonLogger := log.NewLog(log.NewOptions().WithDirectory(t.TempDir()).
+		WithFormat(log.FormatJSON).WithIsolated(true))
+	defer jsonLogger.Close()
+	LogBanner(jsonLogger, info)
+	if out.Len() != 0 {
+		t.Fatalf("Expected no banner, got %q", out.String())
+	}
+
+	logger := log.NewLog(log.NewOptions().WithDirectory(t.TempDir()).WithIsolated(true))
+	defer logger.Close()
+	LogBanner(logger, info)
+
+	banner := out.String()
+	lines := strings.Split(strings.TrimSuffix(banner, "\n"), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("Expected 10 banner lines, got %d:\n%s", len(lines), banner)
+	}
+	for _, line := range lines {
+		if len(line) != len(lines[0]) {
+			t.Errorf("Expected aligned banner lines, got:\n%s", banner)
+			break
+		}
+	}
+	for _, want := range []string{"| billing ", "version:    v1.4.2", "pid:        4242"} {
+		if !strings.Contains(banner, want) {
+			t.Errorf("Expected banner to contain %q, got:\n%s", want, banner)
+		}
+	}
+}
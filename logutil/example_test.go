@@ -128,6 +128,19 @@ func ExampleLogStartup() {
 	// Logs application startup information including name, version, and port
 }
 
+func ExampleLogBanner() {
+	logger := log.NewLog(nil)
+
+	logutil.LogBanner(logger, logutil.BannerInfo{
+		AppName:   "my-web-service",
+		Version:   "v1.2.3",
+		Commit:    "3f2c9e1",
+		BuildTime: "2024-01-15T10:00:00Z",
+		Config:    map[string]any{"port": 8080, "env": "production"},
+	})
+	// Logs app_name, version, commit, build_time, go_version, pid, hostname and config
+}
+
 func ExampleLogShutdown() {
 	logger := log.NewLog(nil)
 	startTime := time.Now()
@@ -6,29 +6,26 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"runtime/debug"
 	"time"
 
 	"github.com/kydenul/log"
 )
 
 // LogHTTPRequest logs details about an HTTP request.
-// It records the HTTP method, URL, remote address, and user agent.
+// It records the same fields as log.HTTPMiddleware: method, URL, remote address,
+// user agent, host and content length.
 func LogHTTPRequest(logger log.Logger, req *http.Request) {
 	if logger == nil || req == nil {
 		return
 	}
 
-	logger.Infow("HTTP请求",
-		"method", req.Method,
-		"url", req.URL.String(),
-		"remote_addr", req.RemoteAddr,
-		"user_agent", req.UserAgent(),
-		"content_length", req.ContentLength,
-	)
+	logger.Infow("HTTP请求", log.HTTPRequestFields(req)...)
 }
 
 // LogHTTPResponse logs details about an HTTP response.
-// It records the HTTP method, URL, status code, and response duration.
+// It records the same fields as log.HTTPMiddleware: method, URL, status code,
+// response duration and remote address.
 func LogHTTPResponse(logger log.Logger, req *http.Request, statusCode int, duration time.Duration) {
 	if logger == nil || req == nil {
 		return
@@ -41,12 +38,7 @@ func LogHTTPResponse(logger log.Logger, req *http.Request, statusCode int, durat
 		level = "warn"
 	}
 
-	fields := []any{
-		"method", req.Method,
-		"url", req.URL.String(),
-		"status_code", statusCode,
-		"duration_ms", duration.Milliseconds(),
-	}
+	fields := log.HTTPResponseFields(req, statusCode, duration)
 
 	switch level {
 	case "error":
@@ -105,6 +97,61 @@ func Timer(logger log.Logger, name string) func() {
 	}
 }
 
+// SlowThreshold marks a duration above which TimerWithFields logs at warn level.
+// It can be passed anywhere among the key-value pairs of TimerWithFields.
+type SlowThreshold time.Duration
+
+// TimerWithFields returns a function that, when called, logs the elapsed time since
+// TimerWithFields was called, together with the given key-value pairs.
+// If a SlowThreshold is passed among the key-value pairs and the duration exceeds it,
+// the log is emitted at warn level with an additional "slow_threshold" field.
+//
+// Example usage:
+//
+//	defer TimerWithFields(logger, "database_query", "table", "users", SlowThreshold(100*time.Millisecond))()
+func TimerWithFields(logger log.Logger, name string, kv ...any) func() {
+	if logger == nil {
+		return func() {} // No-op if logger is nil
+	}
+
+	var threshold time.Duration
+	fields := make([]any, 0, len(kv)+6)
+	for _, v := range kv {
+		if t, ok := v.(SlowThreshold); ok {
+			threshold = time.Duration(t)
+			continue
+		}
+		fields = append(fields, v)
+	}
+
+	start := time.Now()
+	return func() {
+		duration := time.Since(start)
+		fields := append([]any{
+			"operation", name,
+			"duration_ms", duration.Milliseconds(),
+			"duration", duration.String(),
+		}, fields...)
+
+		if threshold > 0 && duration > threshold {
+			logger.Warnw("操作耗时", append(fields, "slow_threshold", threshold.String())...)
+			return
+		}
+		logger.Infow("操作耗时", fields...)
+	}
+}
+
+// SlowTimer returns a function that, when called, logs the elapsed time since SlowTimer was
+// called at info level, or at warn level with an additional "slow_threshold" field when the
+// duration exceeds threshold. It is a shorthand for TimerWithFields with a SlowThreshold.
+//
+// Example usage:
+//
+//	defer SlowTimer(logger, "database_query", 100*time.Millisecond)()
+func SlowTimer(logger log.Logger, name string, threshold time.Duration) func() {
+	return TimerWithFields(logger, name, SlowThreshold(threshold))
+}
+
 // TimeFunction executes a function and logs its execution time.
 // This is a convenience wrapper around Timer for simple function timing.
 func TimeFunction(logger log.Logger, name string, fn func()) {
@@ -150,7 +197,9 @@ func ErrorIf(logger log.Logger, condition bool, msg string, args ...any) {
 }
 
 // DebugIf logs a debug message only if the condition is true.
-// This helps reduce conditional logging boilerplate in application code.
+// This helps reduce conditional logging boilerplate in application code,
+// e.g. gating expensive debug logging behind a flag.
+// When the condition is false it returns immediately without touching the logger.
 func DebugIf(logger log.Logger, condition bool, msg string, args ...any) {
 	if logger == nil || !condition {
 		return
@@ -185,6 +234,14 @@ func WithRequestID(ctx context.Context, logger log.Logger) log.Logger {
 		return logger
 	}
 
+	// Request ID stored by log.HTTPMiddleware
+	if requestID := log.RequestIDFromContext(ctx); requestID != "" {
+		return &requestIDLogger{
+			logger:    logger,
+			requestID: requestID,
+		}
+	}
+
 	// Try common request ID keys
 	requestIDKeys := []string{"request_id", "requestId", "req_id", "trace_id", "traceId"}
 
@@ -207,8 +264,8 @@ type requestIDLogger struct {
 	requestID any
 }
 
-func (r *requestIDLogger) Sync() {
-	r.logger.Sync()
+func (r *requestIDLogger) Sync() error {
+	return r.logger.Sync()
 }
 
 func (r *requestIDLogger) Debug(args ...any) {
@@ -344,6 +401,52 @@ func LogPanicAsError(logger log.Logger, operation string) {
 	}
 }
 
+// Recover recovers from a panic and logs the recovered value together with the
+// goroutine stack trace (under the "stack" field) at error level, without re-panicking.
+// It must be called directly by defer.
+//
+// Example usage:
+//
+//	defer logutil.Recover(logger, "handler")
+func Recover(logger log.Logger, operation string) {
+	if r := recover(); r != nil {
+		logRecovered(logger, operation, r, debug.Stack(), false)
+	}
+}
+
+// RecoverAndExit recovers from a panic, logs the recovered value together with the
+// goroutine stack trace at fatal level and exits the program.
+// It must be called directly by defer.
+//
+// Example usage:
+//
+//	defer logutil.RecoverAndExit(logger, "main")
+func RecoverAndExit(logger log.Logger, operation string) {
+	if r := recover(); r != nil {
+		logRecovered(logger, operation, r, debug.Stack(), true)
+		os.Exit(1) // Ensure exit even if the logger doesn't
+	}
+}
+
+// logRecovered logs a recovered panic value with its stack trace.
+func logRecovered(logger log.Logger, operation string, r any, stack []byte, fatal bool) {
+	if logger == nil {
+		return
+	}
+
+	fields := []any{
+		"operation", operation,
+		"panic", r,
+		"stack", string(stack),
+	}
+
+	if fatal {
+		logger.Fatalw("Panic recovered, exiting", fields...)
+		return
+	}
+	logger.Errorw("Panic recovered", fields...)
+}
+
 // Must logs a fatal error and exits if err is not nil.
 // This is similar to FatalOnError but with a shorter name for convenience.
 func Must(logger log.Logger, err error, msg string) {
@@ -392,3 +495,40 @@ func LogShutdown(logger log.Logger, appName string, uptime time.Duration) {
 		"uptime_seconds", uptime.Seconds(),
 	)
 }
+
+// ShutdownStats describes the work done by a graceful shutdown, for LogShutdownWithStats.
+type ShutdownStats struct {
+	ConnectionsClosed int           // Connections closed, e.g. idle HTTP or database connections
+	RequestsDrained   int           // In-flight requests that completed before shutting down
+	RequestsAborted   int           // In-flight requests cut off, e.g. when the drain timed out
+	DrainDuration     time.Duration // Time spent waiting for in-flight requests
+	FlushDuration     time.Duration // Time spent flushing buffers, queues and logs
+}
+
+// LogShutdownWithStats logs application shutdown information like LogShutdown, along with
+// the resources released by the graceful shutdown. It logs at warn level when requests were
+// aborted, and at info level otherwise.
+func LogShutdownWithStats(logger log.Logger, appName string, uptime time.Duration, stats ShutdownStats) {
+	if logger == nil {
+		return
+	}
+
+	fields := []any{
+		"app_name", appName,
+		"uptime", uptime.String(),
+		"uptime_seconds", uptime.Seconds(),
+		"connections_closed", stats.ConnectionsClosed,
+		"requests_drained", stats.RequestsDrained,
+		"requests_aborted", stats.RequestsAborted,
+		"drain_duration", stats.DrainDuration.String(),
+		"drain_duration_ms", stats.DrainDuration.Milliseconds(),
+		"flush_duration", stats.FlushDuration.String(),
+		"flush_duration_ms", stats.FlushDuration.Milliseconds(),
+	}
+
+	if stats.RequestsAborted > 0 {
+		logger.Warnw("应用关闭", fields...)
+		return
+	}
+	logger.Infow("应用关闭", fields...)
+}
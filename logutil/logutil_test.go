@@ -2,10 +2,12 @@ package logutil
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,7 +29,7 @@ func newMockLogger() *mockLogger {
 	return &mockLogger{logs: make([]logEntry, 0)}
 }
 
-func (m *mockLogger) Sync() {}
+func (m *mockLogger) Sync() error { return nil }
 
 func (m *mockLogger) Debug(args ...any) {
 	m.logs = append(m.logs, logEntry{level: "debug", message: "", fields: args})
@@ -205,6 +207,15 @@ func TestLogHTTPRequest(t *testing.T) {
 				if !mock.hasField("method", "GET") {
 					t.Error("Expected method field with value 'GET'")
 				}
+
+				// Fields are consistent with log.HTTPMiddleware
+				if !mock.hasField("host", "example.com") {
+					t.Error("Expected host field with value 'example.com'")
+				}
+
+				if !mock.hasField("url", "http://example.com/test") {
+					t.Error("Expected url field with value 'http://example.com/test'")
+				}
 			}
 		})
 	}
@@ -246,6 +257,10 @@ func TestLogHTTPResponse(t *testing.T) {
 			if !mock.hasField("duration_ms", int64(100)) {
 				t.Error("Expected duration_ms field with value 100")
 			}
+
+			if !mock.hasField("method", "GET") || !mock.hasField("url", "http://example.com/test") {
+				t.Error("Expected method and url fields")
+			}
 		})
 	}
 }
@@ -360,6 +375,129 @@ func TestTimer(t *testing.T) {
 	}
 }
 
+func TestTimerWithFields(t *testing.T) {
+	t.Run("with fields", func(t *testing.T) {
+		mock := newMockLogger()
+
+		TimerWithFields(mock, "db_query", "table", "users", "user_id", 42)()
+
+		lastLog := mock.getLastLog()
+		if lastLog == nil {
+			t.Fatal("Expected log entry, got none")
+		}
+
+		if lastLog.level != "infow" {
+			t.Errorf("Expected level 'infow', got '%s'", lastLog.level)
+		}
+
+		if lastLog.message != "操作耗时" {
+			t.Errorf("Expected message '操作耗时', got '%s'", lastLog.message)
+		}
+
+		if !mock.hasField("operation", "db_query") {
+			t.Error("Expected operation field with value 'db_query'")
+		}
+
+		if !mock.hasField("table", "users") || !mock.hasField("user_id", 42) {
+			t.Error("Expected custom fields table and user_id")
+		}
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		mock := newMockLogger()
+
+		TimerWithFields(mock, "fast_op", "table", "users", SlowThreshold(time.Hour))()
+
+		lastLog := mock.getLastLog()
+		if lastLog.level != "infow" {
+			t.Errorf("Expected level 'infow', got '%s'", lastLog.level)
+		}
+
+		for _, field := range lastLog.fields {
+			if _, ok := field.(SlowThreshold); ok {
+				t.Error("SlowThreshold should not be logged as a field")
+			}
+		}
+	})
+
+	t.Run("above threshold escalates to warn", func(t *testing.T) {
+		mock := newMockLogger()
+
+		timer := TimerWithFields(mock, "slow_op", SlowThreshold(time.Millisecond), "table", "orders")
+		time.Sleep(5 * time.Millisecond)
+		timer()
+
+		lastLog := mock.getLastLog()
+		if lastLog.level != "warnw" {
+			t.Errorf("Expected level 'warnw', got '%s'", lastLog.level)
+		}
+
+		if !mock.hasField("table", "orders") {
+			t.Error("Expected table field with value 'orders'")
+		}
+
+		if !mock.hasField("slow_threshold", "1ms") {
+			t.Error("Expected slow_threshold field with value '1ms'")
+		}
+	})
+
+	t.Run("nil logger", func(_ *testing.T) {
+		TimerWithFields(nil, "op", "k", "v")()
+	})
+}
+
+func TestSlowTimer(t *testing.T) {
+	t.Run("below threshold", func(t *testing.T) {
+		mock := newMockLogger()
+
+		SlowTimer(mock, "fast_op", time.Hour)()
+
+		lastLog := mock.getLastLog()
+		if lastLog == nil {
+			t.Fatal("Expected log entry, got none")
+		}
+
+		if lastLog.level != "infow" {
+			t.Errorf("Expected level 'infow', got '%s'", lastLog.level)
+		}
+
+		if !mock.hasField("operation", "fast_op") {
+			t.Error("Expected operation field with value 'fast_op'")
+		}
+
+		for i := 0; i < len(lastLog.fields)-1; i += 2 {
+			if lastLog.fields[i] == "slow_threshold" {
+				t.Error("Expected no slow_threshold field below the threshold")
+			}
+		}
+	})
+
+	t.Run("above threshold", func(t *testing.T) {
+		mock := newMockLogger()
+
+		timer := SlowTimer(mock, "slow_op", time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+		timer()
+
+		lastLog := mock.getLastLog()
+		if lastLog.level != "warnw" {
+			t.Errorf("Expected level 'warnw', got '%s'", lastLog.level)
+		}
+
+		if !mock.hasField("operation", "slow_op") {
+			t.Error("Expected operation field with value 'slow_op'")
+		}
+
+		if !mock.hasField("slow_threshold", "1ms") {
+			t.Error("Expected slow_threshold field with value '1ms'")
+		}
+	})
+
+	t.Run("nil logger", func(_ *testing.T) {
+		SlowTimer(nil, "op", time.Second)()
+	})
+}
+
 func TestTimeFunction(t *testing.T) {
 	mock := newMockLogger()
 	executed := false
@@ -453,6 +591,82 @@ func TestErrorIf(t *testing.T) {
 	}
 }
 
+func TestDebugIf(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition bool
+		args      []any
+		wantLevel string // empty means no log entry expected
+	}{
+		{"true condition with fields", true, []any{"key", "value"}, "debugw"},
+		{"true condition without fields", true, nil, "debug"},
+		{"false condition", false, []any{"key", "value"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockLogger()
+
+			DebugIf(mock, tt.condition, "test message", tt.args...)
+
+			if tt.wantLevel == "" {
+				if len(mock.logs) > 0 {
+					t.Error("Expected no log entry, got one")
+				}
+				return
+			}
+
+			if len(mock.logs) == 0 {
+				t.Error("Expected log entry, got none")
+				return
+			}
+
+			lastLog := mock.getLastLog()
+			if lastLog.level != tt.wantLevel {
+				t.Errorf("Expected level '%s', got '%s'", tt.wantLevel, lastLog.level)
+			}
+		})
+	}
+}
+
+func TestWarnIf(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition bool
+		args      []any
+		wantLevel string // empty means no log entry expected
+	}{
+		{"true condition with fields", true, []any{"key", "value"}, "warnw"},
+		{"true condition without fields", true, nil, "warn"},
+		{"false condition", false, []any{"key", "value"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockLogger()
+
+			WarnIf(mock, tt.condition, "test message", tt.args...)
+
+			if tt.wantLevel == "" {
+				if len(mock.logs) > 0 {
+					t.Error("Expected no log entry, got one")
+				}
+				return
+			}
+
+			if len(mock.logs) == 0 {
+				t.Error("Expected log entry, got none")
+				return
+			}
+
+			lastLog := mock.getLastLog()
+			if lastLog.level != tt.wantLevel {
+				t.Errorf("Expected level '%s', got '%s'", tt.wantLevel, lastLog.level)
+			}
+		})
+	}
+}
+
 func TestWithRequestID(t *testing.T) {
 	mock := newMockLogger()
 
@@ -480,6 +694,15 @@ func TestWithRequestID(t *testing.T) {
 	if wrappedLogger2 != mock2 {
 		t.Error("Expected original logger when no request ID in context")
 	}
+
+	// Test with request ID stored by log.HTTPMiddleware
+	mock3 := newMockLogger()
+	ctx3 := context.WithValue(context.Background(), log.RequestIDContextKey, "mw-456")
+	WithRequestID(ctx3, mock3).Infow("test message")
+
+	if !mock3.hasField("request_id", "mw-456") {
+		t.Error("Expected request_id field with value 'mw-456'")
+	}
 }
 
 func TestLogPanicAsError(t *testing.T) {
@@ -510,6 +733,64 @@ func TestLogPanicAsError(t *testing.T) {
 	}
 }
 
+func TestRecover(t *testing.T) {
+	t.Run("recovered", func(t *testing.T) {
+		mock := newMockLogger()
+
+		func() {
+			defer Recover(mock, "handler")
+			panic("boom")
+		}()
+
+		if len(mock.logs) != 1 {
+			t.Fatalf("Expected 1 log entry, got %d", len(mock.logs))
+		}
+
+		lastLog := mock.getLastLog()
+		if lastLog.level != "errorw" {
+			t.Errorf("Expected level 'errorw', got '%s'", lastLog.level)
+		}
+
+		if !mock.hasField("operation", "handler") {
+			t.Error("Expected operation field with value 'handler'")
+		}
+
+		if !mock.hasField("panic", "boom") {
+			t.Error("Expected panic field with value 'boom'")
+		}
+
+		found := false
+		for i := 0; i < len(lastLog.fields)-1; i += 2 {
+			if lastLog.fields[i] == "stack" {
+				stack, ok := lastLog.fields[i+1].(string)
+				found = ok && strings.Contains(stack, "TestRecover")
+			}
+		}
+		if !found {
+			t.Error("Expected stack field containing the panicking function")
+		}
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		mock := newMockLogger()
+
+		func() {
+			defer Recover(mock, "handler")
+		}()
+
+		if len(mock.logs) != 0 {
+			t.Errorf("Expected no log entries, got %d", len(mock.logs))
+		}
+	})
+
+	t.Run("nil logger", func(_ *testing.T) {
+		func() {
+			defer Recover(nil, "handler")
+			panic("boom")
+		}()
+	})
+}
+
 func TestCheckError(t *testing.T) {
 	tests := []struct {
 		name string
@@ -604,6 +885,70 @@ func TestLogShutdown(t *testing.T) {
 	}
 }
 
+func TestLogShutdownWithStats(t *testing.T) {
+	mock := newMockLogger()
+	stats := ShutdownStats{
+		ConnectionsClosed: 12,
+		RequestsDrained:   3,
+		DrainDuration:     1500 * time.Millisecond,
+		FlushDuration:     20 * time.Millisecond,
+	}
+
+	LogShutdownWithStats(mock, "test-app", 5*time.Minute, stats)
+
+	lastLog := mock.getLastLog()
+	if lastLog.level != "infow" || lastLog.message != "应用关闭" {
+		t.Errorf("Expected infow '应用关闭', got %s '%s'", lastLog.level, lastLog.message)
+	}
+	if !mock.hasField("uptime", (5*time.Minute).String()) || !mock.hasField("connections_closed", 12) {
+		t.Error("Expected uptime and connections_closed fields")
+	}
+
+	// Aborted requests are worth a warning
+	stats.RequestsAborted = 1
+	LogShutdownWithStats(mock, "test-app", 5*time.Minute, stats)
+	if lastLog = mock.getLastLog(); lastLog.level != "warnw" {
+		t.Errorf("Expected level 'warnw', got '%s'", lastLog.level)
+	}
+
+	// The fields serialize to JSON with their expected types
+	logger := log.NewLog(log.NewOptions().WithDirectory(t.TempDir()).WithFormat(log.FormatJSON).
+		WithConsoleOutput(false).WithIsolated(true))
+	LogShutdownWithStats(logger, "test-app", 5*time.Minute, stats)
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(logger.PlannedFiles()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := string(content)
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(line[strings.Index(line, "{"):]), &entry); err != nil {
+		t.Fatalf("Expected a JSON entry, got %q: %v", line, err)
+	}
+
+	for key, want := range map[string]any{
+		"app_name":           "test-app",
+		"uptime":             "5m0s",
+		"uptime_seconds":     300.0,
+		"connections_closed": 12.0,
+		"requests_drained":   3.0,
+		"requests_aborted":   1.0,
+		"drain_duration":     "1.5s",
+		"drain_duration_ms":  1500.0,
+		"flush_duration":     "20ms",
+		"flush_duration_ms":  20.0,
+	} {
+		if entry[key] != want {
+			t.Errorf("Expected %s %v, got %v", key, want, entry[key])
+		}
+	}
+
+	LogShutdownWithStats(nil, "test", time.Minute, stats)
+}
+
 // Test nil logger handling for all functions
 func TestNilLoggerHandling(t *testing.T) {
 	req := httptest.NewRequest("GET", "http://example.com", nil)
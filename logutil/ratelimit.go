@@ -0,0 +1,291 @@
+package logutil
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kydenul/log"
+)
+
+// rateLimiters holds the sliding-window limiter state for every key passed to RateLimit.
+// The budget is shared by all loggers using the same key. Idle windows are evicted, see
+// sweepRateLimiters, so keys like user IDs don't grow it without bound.
+var rateLimiters sync.Map // map[string]*slidingWindow
+
+// rateLimitSweepInterval is how often the idle windows are evicted from rateLimiters.
+var rateLimitSweepInterval = time.Minute
+
+// lastRateLimitSweep is the time of the last sweep of rateLimiters, in Unix nanoseconds.
+var lastRateLimitSweep atomic.Int64
+
+// slidingWindow tracks the emit timestamps of the last n allowed logs for a key.
+type slidingWindow struct {
+	mu       sync.Mutex
+	times    []time.Time   // ring buffer of allowed timestamps
+	next     int           // next slot to overwrite in times
+	interval time.Duration // window of the last call, after which the key is idle
+	evicted  bool          // whether the window was removed from rateLimiters
+}
+
+// allow reports whether another log may be emitted at now, given a budget of n logs per
+// interval. It reports false for ok if the window was evicted, to be looked up again.
+func (w *slidingWindow) allow(now time.Time, n int, interval time.Duration) (allowed, ok bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.evicted {
+		return false, false
+	}
+	w.interval = interval
+
+	// Resize the ring buffer when the budget for this key changes, keeping the most recent
+	// timestamps, so the window isn't reset
+	if cap(w.times) != n {
+		recent := append(w.times[w.next:len(w.times):len(w.times)], w.times[:w.next]...)
+		recent = recent[max(len(recent)-n, 0):]
+		w.times = append(make([]time.Time, 0, n), recent...)
+		w.next = 0
+	}
+
+	// Budget not exhausted yet
+	if len(w.times) < n {
+		w.times = append(w.times, now)
+		return true, true
+	}
+
+	// The oldest allowed log is still within the window, drop this one
+	if now.Sub(w.times[w.next]) < interval {
+		return false, true
+	}
+
+	w.times[w.next] = now
+	w.next = (w.next + 1) % n
+	return true, true
+}
+
+// idle reports whether no log was allowed within the last interval at now, in which case
+// the window is the same as a new one.
+func (w *slidingWindow) idle(now time.Time) bool {
+	if len(w.times) == 0 {
+		return true
+	}
+	newest := w.times[(w.next+len(w.times)-1)%len(w.times)]
+	return now.Sub(newest) >= w.interval
+}
+
+// allowRateLimit reports whether another log may be emitted for key at now.
+func allowRateLimit(key string, now time.Time, n int, interval time.Duration) bool {
+	sweepRateLimiters(now)
+
+	for {
+		window, _ := rateLimiters.LoadOrStore(key, &slidingWindow{})
+		w, _ := window.(*slidingWindow)
+		if allowed, ok := w.allow(now, n, interval); ok {
+			return allowed
+		}
+	}
+}
+
+// sweepRateLimiters evicts the idle windows from rateLimiters, at most once every
+// rateLimitSweepInterval.
+func sweepRateLimiters(now time.Time) {
+	last := lastRateLimitSweep.Load()
+	if now.UnixNano()-last < int64(rateLimitSweepInterval) ||
+		!lastRateLimitSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	rateLimiters.Range(func(key, window any) bool {
+		w, _ := window.(*slidingWindow)
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if w.idle(now) {
+			w.evicted = true
+			rateLimiters.CompareAndDelete(key, w)
+		}
+		return true
+	})
+}
+
+// RateLimit returns a logger that emits at most n logs for the given key within any
+// sliding window of the given interval, dropping the rest. This prevents a single
+// tenant or error type from flooding the logs with millions of identical entries.
+//
+// The budget is tracked per key and shared across all calls using the same key,
+// so it is safe to call RateLimit at every call site:
+//
+//	logutil.RateLimit(logger, "tenant:"+tenantID, 10, time.Minute).Warnw("quota exceeded")
+//
+// The state of a key is dropped once no log was emitted for it within the interval, so
+// keys can be unbounded, e.g. user IDs. Calls with a different n for the same key resize
+// its budget, keeping the logs already emitted within the window.
+//
+// Sync is always forwarded. If n <= 0 or interval <= 0, the original logger is returned.
+func RateLimit(logger log.Logger, key string, n int, interval time.Duration) log.Logger {
+	if logger == nil || n <= 0 || interval <= 0 {
+		return logger
+	}
+
+	return &rateLimitedLogger{
+		logger:   logger,
+		key:      key,
+		n:        n,
+		interval: interval,
+	}
+}
+
+// ResetRateLimit clears the rate limit state for the given key.
+func ResetRateLimit(key string) {
+	if window, ok := rateLimiters.LoadAndDelete(key); ok {
+		w, _ := window.(*slidingWindow)
+		w.mu.Lock()
+		w.evicted = true
+		w.mu.Unlock()
+	}
+}
+
+// rateLimitedLogger is a wrapper that drops log calls exceeding the key's budget
+type rateLimitedLogger struct {
+	logger   log.Logger
+	key      string
+	n        int
+	interval time.Duration
+}
+
+func (r *rateLimitedLogger) allow() bool { return allowRateLimit(r.key, time.Now(), r.n, r.interval) }
+
+func (r *rateLimitedLogger) Sync() error {
+	return r.logger.Sync()
+}
+
+func (r *rateLimitedLogger) Debug(args ...any) {
+	if r.allow() {
+		r.logger.Debug(args...)
+	}
+}
+
+func (r *rateLimitedLogger) Debugf(template string, args ...any) {
+	if r.allow() {
+		r.logger.Debugf(template, args...)
+	}
+}
+
+func (r *rateLimitedLogger) Debugw(msg string, keysAndValues ...any) {
+	if r.allow() {
+		r.logger.Debugw(msg, keysAndValues...)
+	}
+}
+
+func (r *rateLimitedLogger) Debugln(args ...any) {
+	if r.allow() {
+		r.logger.Debugln(args...)
+	}
+}
+
+func (r *rateLimitedLogger) Info(args ...any) {
+	if r.allow() {
+		r.logger.Info(args...)
+	}
+}
+
+func (r *rateLimitedLogger) Infof(template string, args ...any) {
+	if r.allow() {
+		r.logger.Infof(template, args...)
+	}
+}
+
+func (r *rateLimitedLogger) Infow(msg string, keysAndValues ...any) {
+	if r.allow() {
+		r.logger.Infow(msg, keysAndValues...)
+	}
+}
+
+func (r *rateLimitedLogger) Infoln(args ...any) {
+	if r.allow() {
+		r.logger.Infoln(args...)
+	}
+}
+
+func (r *rateLimitedLogger) Warn(args ...any) {
+	if r.allow() {
+		r.logger.Warn(args...)
+	}
+}
+
+func (r *rateLimitedLogger) Warnf(template string, args ...any) {
+	if r.allow() {
+		r.logger.Warnf(template, args...)
+	}
+}
+
+func (r *rateLimitedLogger) Warnw(msg string, keysAndValues ...any) {
+	if r.allow() {
+		r.logger.Warnw(msg, keysAndValues...)
+	}
+}
+
+func (r *rateLimitedLogger) Warnln(args ...any) {
+	if r.allow() {
+		r.logger.Warnln(args...)
+	}
+}
+
+func (r *rateLimitedLogger) Error(args ...any) {
+	if r.allow() {
+		r.logger.Error(args...)
+	}
+}
+
+func (r *rateLimitedLogger) Errorf(template string, args ...any) {
+	if r.allow() {
+		r.logger.Errorf(template, args...)
+	}
+}
+
+func (r *rateLimitedLogger) Errorw(msg string, keysAndValues ...any) {
+	if r.allow() {
+		r.logger.Errorw(msg, keysAndValues...)
+	}
+}
+
+func (r *rateLimitedLogger) Errorln(args ...any) {
+	if r.allow() {
+		r.logger.Errorln(args...)
+	}
+}
+
+// Panic-level calls are never dropped, so control flow is preserved.
+func (r *rateLimitedLogger) Panic(args ...any) {
+	r.logger.Panic(args...)
+}
+
+func (r *rateLimitedLogger) Panicf(template string, args ...any) {
+	r.logger.Panicf(template, args...)
+}
+
+func (r *rateLimitedLogger) Panicw(msg string, keysAndValues ...any) {
+	r.logger.Panicw(msg, keysAndValues...)
+}
+
+func (r *rateLimitedLogger) Panicln(args ...any) {
+	r.logger.Panicln(args...)
+}
+
+// Fatal-level calls are never dropped, so control flow is preserved.
+func (r *rateLimitedLogger) Fatal(args ...any) {
+	r.logger.Fatal(args...)
+}
+
+func (r *rateLimitedLogger) Fatalf(template string, args ...any) {
+	r.logger.Fatalf(template, args...)
+}
+
+func (r *rateLimitedLogger) Fatalw(msg string, keysAndValues ...any) {
+	r.logger.Fatalw(msg, keysAndValues...)
+}
+
+func (r *rateLimitedLogger) Fatalln(args ...any) {
+	r.logger.Fatalln(args...)
+}
@@ -0,0 +1,171 @@
+package logutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	mock := newMockLogger()
+	key := "test_rate_limit_same_key"
+	defer ResetRateLimit(key)
+
+	for i := range 1000 {
+		RateLimit(mock, key, 10, time.Minute).Warnw("tenant flood", "i", i)
+	}
+
+	if len(mock.logs) != 10 {
+		t.Fatalf("Expected 10 log entries, got %d", len(mock.logs))
+	}
+
+	// The first N calls are the ones that are emitted
+	if !mock.hasField("i", 0) || !mock.hasField("i", 9) || mock.hasField("i", 10) {
+		t.Error("Expected only the first 10 calls to be emitted")
+	}
+}
+
+func TestRateLimit_IndependentKeys(t *testing.T) {
+	mock := newMockLogger()
+	defer ResetRateLimit("tenant_a")
+	defer ResetRateLimit("tenant_b")
+
+	for range 100 {
+		RateLimit(mock, "tenant_a", 3, time.Minute).Info("a")
+		RateLimit(mock, "tenant_b", 5, time.Minute).Info("b")
+	}
+
+	if len(mock.logs) != 8 {
+		t.Errorf("Expected 8 log entries (3 + 5), got %d", len(mock.logs))
+	}
+}
+
+func TestRateLimit_WindowSlides(t *testing.T) {
+	mock := newMockLogger()
+	key := "test_rate_limit_window"
+	defer ResetRateLimit(key)
+
+	limited := RateLimit(mock, key, 2, 50*time.Millisecond)
+	for range 10 {
+		limited.Info("burst")
+	}
+	if len(mock.logs) != 2 {
+		t.Fatalf("Expected 2 log entries in first window, got %d", len(mock.logs))
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	for range 10 {
+		limited.Info("burst")
+	}
+	if len(mock.logs) != 4 {
+		t.Errorf("Expected 4 log entries after window elapsed, got %d", len(mock.logs))
+	}
+}
+
+func TestRateLimit_InvalidArgs(t *testing.T) {
+	mock := newMockLogger()
+
+	if RateLimit(mock, "k", 0, time.Second) != mock {
+		t.Error("Expected original logger when n <= 0")
+	}
+
+	if RateLimit(mock, "k", 1, 0) != mock {
+		t.Error("Expected original logger when interval <= 0")
+	}
+
+	if RateLimit(nil, "k", 1, time.Second) != nil {
+		t.Error("Expected nil logger to be returned as is")
+	}
+}
+
+func TestRateLimit_EvictsIdleKeys(t *testing.T) {
+	interval := rateLimitSweepInterval
+	rateLimitSweepInterval = 0
+	defer func() { rateLimitSweepInterval = interval }()
+
+	now := time.Now()
+	for i := range 100 {
+		allowRateLimit(fmt.Sprintf("test_rate_limit_user:%d", i), now, 1, time.Minute)
+	}
+
+	// Keys with a log within the window are kept, the budget still applies
+	if allowRateLimit("test_rate_limit_user:0", now.Add(30*time.Second), 1, time.Minute) {
+		t.Error("Expected the budget of an active key to be kept")
+	}
+	if countRateLimiters("test_rate_limit_user:") != 100 {
+		t.Errorf("Expected 100 active keys, got %d", countRateLimiters("test_rate_limit_user:"))
+	}
+
+	// Once idle for the interval, they are evicted by the next sweep
+	if !allowRateLimit("test_rate_limit_user:0", now.Add(time.Minute), 1, time.Minute) {
+		t.Error("Expected a new log to be allowed once the window elapsed")
+	}
+	if n := countRateLimiters("test_rate_limit_user:"); n != 1 {
+		t.Errorf("Expected idle keys to be evicted, %d left", n)
+	}
+	ResetRateLimit("test_rate_limit_user:0")
+}
+
+func TestRateLimit_EvictedWhileHeld(t *testing.T) {
+	interval := rateLimitSweepInterval
+	rateLimitSweepInterval = 0
+	defer func() { rateLimitSweepInterval = interval }()
+
+	mock := newMockLogger()
+	key := "test_rate_limit_held"
+	defer ResetRateLimit(key)
+
+	// A limiter kept across an eviction shares the budget of the new calls
+	limited := RateLimit(mock, key, 1, 20*time.Millisecond)
+	limited.Info("first")
+	time.Sleep(30 * time.Millisecond)
+	limited.Info("after eviction")
+	RateLimit(mock, key, 1, 20*time.Millisecond).Info("dropped")
+
+	if len(mock.logs) != 2 {
+		t.Errorf("Expected 2 log entries, got %d", len(mock.logs))
+	}
+}
+
+func TestRateLimit_BudgetChange(t *testing.T) {
+	key := "test_rate_limit_budget_change"
+	defer ResetRateLimit(key)
+
+	now := time.Now()
+	for range 3 {
+		allowRateLimit(key, now, 3, time.Minute)
+	}
+
+	// A larger budget keeps the logs already emitted within the window
+	allowed := 0
+	for range 10 {
+		if allowRateLimit(key, now, 5, time.Minute) {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("Expected 2 more logs with a budget of 5, got %d", allowed)
+	}
+
+	// So does a smaller one
+	if allowRateLimit(key, now, 2, time.Minute) {
+		t.Error("Expected the window to be kept when the budget shrinks")
+	}
+	if !allowRateLimit(key, now.Add(time.Minute), 2, time.Minute) {
+		t.Error("Expected a new log to be allowed once the window elapsed")
+	}
+}
+
+// countRateLimiters returns the number of keys with the given prefix in rateLimiters.
+func countRateLimiters(prefix string) int {
+	n := 0
+	rateLimiters.Range(func(key, _ any) bool {
+		if strings.HasPrefix(key.(string), prefix) {
+			n++
+		}
+		return true
+	})
+	return n
+}
@@ -1,10 +1,135 @@
 package log
 
 import (
+	"context"
+	"crypto/rand"
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 )
 
+// ContextKey is the type of context keys defined by this package.
+type ContextKey string
+
+const (
+	// DefaultRequestIDHeader is the header used to read and propagate the request ID.
+	DefaultRequestIDHeader = "X-Request-ID"
+
+	// RequestIDContextKey is the default context key under which HTTPMiddleware stores
+	// the request ID. Use RequestIDFromContext to retrieve it.
+	RequestIDContextKey ContextKey = "request_id"
+)
+
+// RequestIDFromContext returns the request ID stored by HTTPMiddleware under
+// RequestIDContextKey, or an empty string if there is none. Use MiddlewareConfig.RequestID
+// when the middleware is configured with a custom RequestIDContextKey.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(RequestIDContextKey).(string)
+	return id
+}
+
+// newRequestID generates a random RFC 4122 version 4 UUID.
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // Variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// MiddlewareConfig configures the behavior of HTTPMiddlewareWithConfig.
+type MiddlewareConfig struct {
+	// SkipPaths lists request paths (exact match on r.URL.Path) that are not logged,
+	// e.g. "/health" or "/metrics" hit constantly by liveness probes.
+	SkipPaths []string
+
+	// SkipFunc, if set, is called for every request; returning true skips logging.
+	SkipFunc func(*http.Request) bool
+
+	// RequestIDHeader is the header from which the incoming request ID is read and
+	// to which it is written on the response. Defaults to DefaultRequestIDHeader.
+	RequestIDHeader string
+
+	// RequestIDContextKey is the context key under which the request ID is stored.
+	// Defaults to RequestIDContextKey. Use RequestID to retrieve it.
+	RequestIDContextKey any
+
+	// LogRequestHeaders lists the request headers logged as "request_headers" in the start
+	// log line, e.g. "User-Agent" or "X-Forwarded-For". Names are matched case-insensitively,
+	// and the values of multi-valued headers are joined with ", ". Other headers, such as
+	// Authorization, are never logged.
+	LogRequestHeaders []string
+
+	// LogResponseHeaders lists the response headers logged as "response_headers" in the
+	// completion log line, matched like LogRequestHeaders.
+	LogResponseHeaders []string
+}
+
+// contextKey returns the context key under which the request ID is stored.
+func (c *MiddlewareConfig) contextKey() any {
+	if c.RequestIDContextKey != nil {
+		return c.RequestIDContextKey
+	}
+	return RequestIDContextKey
+}
+
+// RequestID returns the request ID stored by a middleware created with this configuration,
+// honoring RequestIDContextKey, or an empty string if there is none.
+func (c MiddlewareConfig) RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(c.contextKey()).(string)
+	return id
+}
+
+// requestID returns the request ID from the incoming header, generating one if absent.
+// It sets the ID on the response header and stores it in the request context.
+func (c *MiddlewareConfig) requestID(w http.ResponseWriter, r *http.Request) (string, *http.Request) {
+	header := c.RequestIDHeader
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	id := r.Header.Get(header)
+	if id == "" {
+		id = newRequestID()
+	}
+
+	w.Header().Set(header, id)
+	return id, r.WithContext(context.WithValue(r.Context(), c.contextKey(), id))
+}
+
+// headerFields appends to fields the headers of h listed in names as an object under key,
+// omitted if none of them is set.
+func headerFields(fields []any, key string, h http.Header, names []string) []any {
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		if v := h.Values(name); len(v) > 0 {
+			values[http.CanonicalHeaderKey(name)] = strings.Join(v, ", ")
+		}
+	}
+
+	if len(values) == 0 {
+		return fields
+	}
+	return append(fields, key, values)
+}
+
+// shouldSkip reports whether logging should be skipped for the request.
+func (c *MiddlewareConfig) shouldSkip(r *http.Request) bool {
+	if slices.Contains(c.SkipPaths, r.URL.Path) {
+		return true
+	}
+	return c.SkipFunc != nil && c.SkipFunc(r)
+}
+
 // HTTPMiddleware creates an HTTP middleware that logs request and response information.
 // It logs the start of each request and completion with timing information.
 //
@@ -20,18 +145,41 @@ import (
 //	middleware := log.HTTPMiddleware(logger)
 //	http.Handle("/", middleware(yourHandler))
 func HTTPMiddleware(logger Logger) func(http.Handler) http.Handler {
+	return HTTPMiddlewareWithConfig(logger, MiddlewareConfig{})
+}
+
+// HTTPMiddlewareWithConfig creates an HTTP middleware like HTTPMiddleware, customized by cfg.
+// Skipped requests produce no log lines but are still passed through to the handler.
+//
+// Every request carries a correlation ID: it is read from the request ID header
+// or generated as a UUID, set on the response header and stored in the request context,
+// for skipped requests too. Logged requests include it as "request_id" in both the start
+// and completion log lines.
+//
+// Usage:
+//
+//	middleware := log.HTTPMiddlewareWithConfig(logger, log.MiddlewareConfig{
+//	    SkipPaths:         []string{"/health", "/metrics"},
+//	    LogRequestHeaders: []string{"User-Agent", "X-Forwarded-For"},
+//	})
+//	http.Handle("/", middleware(yourHandler))
+func HTTPMiddlewareWithConfig(logger Logger, cfg MiddlewareConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Propagate the request ID to the response and downstream handlers, even if not logged
+			requestID, r := cfg.requestID(w, r)
+
+			if cfg.shouldSkip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			start := time.Now()
 
 			// Log request start
-			logger.Infow("HTTP请求开始",
-				"method", r.Method,
-				"url", r.URL.String(),
-				"remote_addr", r.RemoteAddr,
-				"user_agent", r.UserAgent(),
-				"host", r.Host,
-			)
+			fields := append(HTTPRequestFields(r), "request_id", requestID)
+			fields = headerFields(fields, "request_headers", r.Header, cfg.LogRequestHeaders)
+			logger.Infow("HTTP请求开始", fields...)
 
 			// Wrap the ResponseWriter to capture status code
 			wrapped := &responseWriter{
@@ -46,24 +194,54 @@ func HTTPMiddleware(logger Logger) func(http.Handler) http.Handler {
 			duration := time.Since(start)
 
 			// Log request completion
-			logger.Infow("HTTP请求完成",
-				"method", r.Method,
-				"url", r.URL.String(),
-				"status_code", wrapped.statusCode,
-				"duration_ms", duration.Milliseconds(),
-				"duration_ns", duration.Nanoseconds(),
-				"remote_addr", r.RemoteAddr,
+			fields = append(HTTPResponseFields(r, wrapped.statusCode, duration),
+				"request_bytes", r.ContentLength,
+				"response_bytes", wrapped.bytesWritten,
+				"request_id", requestID,
 			)
+			fields = headerFields(fields, "response_headers", wrapped.Header(), cfg.LogResponseHeaders)
+			logger.Infow("HTTP请求完成", fields...)
 		})
 	}
 }
 
+// HTTPRequestFields returns the structured key-value pairs describing an HTTP request.
+// It is shared by HTTPMiddleware and logutil.LogHTTPRequest so that custom middleware
+// can produce consistent field names: method, url, remote_addr, user_agent, host
+// and content_length.
+func HTTPRequestFields(r *http.Request) []any {
+	return []any{
+		"method", r.Method,
+		"url", r.URL.String(),
+		"remote_addr", r.RemoteAddr,
+		"user_agent", r.UserAgent(),
+		"host", r.Host,
+		"content_length", r.ContentLength,
+	}
+}
+
+// HTTPResponseFields returns the structured key-value pairs describing an HTTP response.
+// It is shared by HTTPMiddleware and logutil.LogHTTPResponse so that custom middleware
+// can produce consistent field names: method, url, status_code, duration_ms,
+// duration_ns and remote_addr.
+func HTTPResponseFields(r *http.Request, statusCode int, duration time.Duration) []any {
+	return []any{
+		"method", r.Method,
+		"url", r.URL.String(),
+		"status_code", statusCode,
+		"duration_ms", duration.Milliseconds(),
+		"duration_ns", duration.Nanoseconds(),
+		"remote_addr", r.RemoteAddr,
+	}
+}
+
 // responseWriter is a wrapper around http.ResponseWriter that captures the status code.
 // It implements the http.ResponseWriter interface and additionally tracks the HTTP status code
-// that was written to the response.
+// and the number of body bytes that were written to the response.
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
+	statusCode   int
+	bytesWritten int64
 }
 
 // WriteHeader captures the status code and calls the underlying ResponseWriter's WriteHeader.
@@ -73,10 +251,25 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// Write calls the underlying ResponseWriter's Write method.
+// Write calls the underlying ResponseWriter's Write method and counts the bytes written.
 // If WriteHeader hasn't been called yet, this will trigger an implicit WriteHeader(200).
 func (rw *responseWriter) Write(data []byte) (int, error) {
-	return rw.ResponseWriter.Write(data)
+	n, err := rw.ResponseWriter.Write(data)
+	rw.bytesWritten += int64(n)
+	return n, err
+}
+
+// Flush sends any buffered data to the client if the underlying ResponseWriter supports it,
+// so chunked and streaming responses keep working through the middleware.
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter, for use with http.ResponseController.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
 }
 
 // Header returns the header map that will be sent by WriteHeader.
@@ -15,7 +15,7 @@ type mockLogger struct {
 	fields   []map[string]any
 }
 
-func (m *mockLogger) Sync() {}
+func (m *mockLogger) Sync() error { return nil }
 
 func (m *mockLogger) Debug(args ...any) {}
 
@@ -282,6 +282,12 @@ func TestResponseWriter_StatusCodeCapture(t *testing.T) {
 		t.Errorf("Expected to write %d bytes, wrote %d", len(data), n)
 	}
 
+	// Byte counter accumulates across multiple writes
+	wrapped.Write(data)
+	if wrapped.bytesWritten != int64(2*len(data)) {
+		t.Errorf("Expected %d bytes counted, got %d", 2*len(data), wrapped.bytesWritten)
+	}
+
 	// Test Header method
 	wrapped.Header().Set("X-Test", "value")
 	if wrapped.Header().Get("X-Test") != "value" {
@@ -318,6 +324,334 @@ func TestHTTPMiddleware_WithRealLogger(t *testing.T) {
 	}
 }
 
+func TestHTTPMiddlewareWithConfig_SkipPaths(t *testing.T) {
+	mockLog := &mockLogger{}
+
+	middleware := HTTPMiddlewareWithConfig(mockLog, MiddlewareConfig{
+		SkipPaths: []string{"/health", "/metrics"},
+	})
+
+	handlerCalls := 0
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalls++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Skipped paths are silent but still reach the handler
+	for _, path := range []string{"/health", "/metrics?format=prometheus"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected status code 200 for %s, got %d", path, rr.Code)
+		}
+	}
+
+	if len(mockLog.messages) != 0 {
+		t.Errorf("Expected no log messages for skipped paths, got %d", len(mockLog.messages))
+	}
+
+	// Other paths are logged
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api", nil))
+
+	if len(mockLog.messages) != 2 {
+		t.Errorf("Expected 2 log messages for /api, got %d", len(mockLog.messages))
+	}
+
+	if handlerCalls != 3 {
+		t.Errorf("Expected handler to be called 3 times, got %d", handlerCalls)
+	}
+}
+
+func TestHTTPMiddlewareWithConfig_SkipFunc(t *testing.T) {
+	mockLog := &mockLogger{}
+
+	middleware := HTTPMiddlewareWithConfig(mockLog, MiddlewareConfig{
+		SkipFunc: func(r *http.Request) bool {
+			return r.Header.Get("X-Probe") == "true"
+		},
+	})
+
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	probe := httptest.NewRequest("GET", "/api", nil)
+	probe.Header.Set("X-Probe", "true")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, probe)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status code 204, got %d", rr.Code)
+	}
+	if len(mockLog.messages) != 0 {
+		t.Errorf("Expected no log messages for skipped request, got %d", len(mockLog.messages))
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api", nil))
+	if len(mockLog.messages) != 2 {
+		t.Errorf("Expected 2 log messages for regular request, got %d", len(mockLog.messages))
+	}
+}
+
+func TestHTTPMiddleware_ByteCounting(t *testing.T) {
+	mockLog := &mockLogger{}
+
+	chunks := []string{"Hello, ", "chunked ", "World!"}
+	handler := HTTPMiddleware(mockLog)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, chunk := range chunks {
+			w.Write([]byte(chunk))
+			w.(http.Flusher).Flush()
+		}
+	}))
+
+	req := httptest.NewRequest("POST", "/upload", strings.NewReader("request body"))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if !rr.Flushed {
+		t.Error("Expected Flush to be forwarded to the underlying ResponseWriter")
+	}
+
+	if len(mockLog.fields) != 2 {
+		t.Fatalf("Expected 2 log messages, got %d", len(mockLog.fields))
+	}
+
+	endFields := mockLog.fields[1]
+	if endFields["response_bytes"] != int64(rr.Body.Len()) {
+		t.Errorf("Expected response_bytes %d, got '%v'", rr.Body.Len(), endFields["response_bytes"])
+	}
+	if endFields["request_bytes"] != int64(len("request body")) {
+		t.Errorf("Expected request_bytes %d, got '%v'", len("request body"), endFields["request_bytes"])
+	}
+}
+
+func TestHTTPMiddleware_RequestID(t *testing.T) {
+	t.Run("generated", func(t *testing.T) {
+		mockLog := &mockLogger{}
+
+		var ctxID string
+		handler := HTTPMiddleware(mockLog)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctxID = RequestIDFromContext(r.Context())
+		}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api", nil))
+
+		headerID := rr.Header().Get(DefaultRequestIDHeader)
+		if len(headerID) != 36 || strings.Count(headerID, "-") != 4 {
+			t.Errorf("Expected generated UUID in response header, got '%s'", headerID)
+		}
+
+		if ctxID != headerID {
+			t.Errorf("Expected context request ID '%s', got '%s'", headerID, ctxID)
+		}
+
+		if len(mockLog.fields) != 2 {
+			t.Fatalf("Expected 2 log messages, got %d", len(mockLog.fields))
+		}
+		for i, fields := range mockLog.fields {
+			if fields["request_id"] != headerID {
+				t.Errorf("Expected request_id '%s' in log %d, got '%v'", headerID, i, fields["request_id"])
+			}
+		}
+	})
+
+	t.Run("propagated from incoming header", func(t *testing.T) {
+		mockLog := &mockLogger{}
+
+		handler := HTTPMiddleware(mockLog)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest("GET", "/api", nil)
+		req.Header.Set("X-Request-ID", "incoming-id")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Header().Get("X-Request-ID") != "incoming-id" {
+			t.Errorf("Expected response header 'incoming-id', got '%s'", rr.Header().Get("X-Request-ID"))
+		}
+		if mockLog.fields[0]["request_id"] != "incoming-id" {
+			t.Errorf("Expected request_id 'incoming-id', got '%v'", mockLog.fields[0]["request_id"])
+		}
+	})
+
+	t.Run("custom header and context key", func(t *testing.T) {
+		type ctxKey struct{}
+		mockLog := &mockLogger{}
+
+		cfg := MiddlewareConfig{
+			RequestIDHeader:     "X-Correlation-ID",
+			RequestIDContextKey: ctxKey{},
+		}
+
+		var ctxID any
+		var cfgID, defaultID string
+		handler := HTTPMiddlewareWithConfig(mockLog, cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctxID = r.Context().Value(ctxKey{})
+			cfgID = cfg.RequestID(r.Context())
+			defaultID = RequestIDFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest("GET", "/api", nil)
+		req.Header.Set("X-Correlation-ID", "corr-1")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Header().Get("X-Correlation-ID") != "corr-1" {
+			t.Errorf("Expected response header 'corr-1', got '%s'", rr.Header().Get("X-Correlation-ID"))
+		}
+		if ctxID != "corr-1" {
+			t.Errorf("Expected context value 'corr-1', got '%v'", ctxID)
+		}
+		if cfgID != "corr-1" {
+			t.Errorf("Expected RequestID 'corr-1', got '%s'", cfgID)
+		}
+		if defaultID != "" {
+			t.Errorf("Expected no request ID under the default context key, got '%s'", defaultID)
+		}
+		if rr.Header().Get(DefaultRequestIDHeader) != "" {
+			t.Error("Expected default header not to be set when a custom header is configured")
+		}
+	})
+
+	t.Run("skipped request", func(t *testing.T) {
+		mockLog := &mockLogger{}
+
+		var ctxID string
+		handler := HTTPMiddlewareWithConfig(mockLog, MiddlewareConfig{
+			SkipPaths: []string{"/health"},
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctxID = RequestIDFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest("GET", "/health", nil)
+		req.Header.Set(DefaultRequestIDHeader, "probe-id")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if len(mockLog.messages) != 0 {
+			t.Errorf("Expected no log messages for skipped request, got %d", len(mockLog.messages))
+		}
+		if rr.Header().Get(DefaultRequestIDHeader) != "probe-id" {
+			t.Errorf("Expected response header 'probe-id', got '%s'", rr.Header().Get(DefaultRequestIDHeader))
+		}
+		if ctxID != "probe-id" {
+			t.Errorf("Expected context request ID 'probe-id', got '%s'", ctxID)
+		}
+
+		rr = httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+		if len(rr.Header().Get(DefaultRequestIDHeader)) != 36 || ctxID != rr.Header().Get(DefaultRequestIDHeader) {
+			t.Errorf("Expected generated request ID for skipped request, got '%s'", ctxID)
+		}
+	})
+}
+
+func TestHTTPMiddlewareWithConfig_LogHeaders(t *testing.T) {
+	mockLog := &mockLogger{}
+
+	handler := HTTPMiddlewareWithConfig(mockLog, MiddlewareConfig{
+		LogRequestHeaders:  []string{"user-agent", "X-FORWARDED-FOR", "X-Missing"},
+		LogResponseHeaders: []string{"content-type"},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/api", nil)
+	req.Header.Set("User-Agent", "curl/8.0")
+	req.Header.Add("X-Forwarded-For", "10.0.0.1")
+	req.Header.Add("X-Forwarded-For", "10.0.0.2")
+	req.Header.Set("Authorization", "Bearer secret")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(mockLog.fields) != 2 {
+		t.Fatalf("Expected 2 log messages, got %d", len(mockLog.fields))
+	}
+
+	// Only the listed headers are logged, with multiple values joined
+	wantRequest := map[string]string{"User-Agent": "curl/8.0", "X-Forwarded-For": "10.0.0.1, 10.0.0.2"}
+	if got := fmt.Sprint(mockLog.fields[0]["request_headers"]); got != fmt.Sprint(wantRequest) {
+		t.Errorf("Expected request headers %v, got %s", wantRequest, got)
+	}
+	wantResponse := map[string]string{"Content-Type": "application/json"}
+	if got := fmt.Sprint(mockLog.fields[1]["response_headers"]); got != fmt.Sprint(wantResponse) {
+		t.Errorf("Expected response headers %v, got %s", wantResponse, got)
+	}
+	if _, ok := mockLog.fields[0]["response_headers"]; ok {
+		t.Error("Expected no response headers in the start log line")
+	}
+
+	// Without listed headers, no header is logged
+	mockLog = &mockLogger{}
+	HTTPMiddleware(mockLog)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).
+		ServeHTTP(httptest.NewRecorder(), req)
+	for i, fields := range mockLog.fields {
+		if _, ok := fields["request_headers"]; ok {
+			t.Errorf("Expected no request headers in log %d", i)
+		}
+		if _, ok := fields["response_headers"]; ok {
+			t.Errorf("Expected no response headers in log %d", i)
+		}
+	}
+}
+
+func TestHTTPRequestFields(t *testing.T) {
+	req := httptest.NewRequest("POST", "http://example.com/api/users?id=1", strings.NewReader("payload"))
+	req.Header.Set("User-Agent", "test-agent")
+	req.RemoteAddr = "10.0.0.1:5555"
+
+	fields := HTTPRequestFields(req)
+	if len(fields)%2 != 0 {
+		t.Fatalf("Expected even number of key-value elements, got %d", len(fields))
+	}
+
+	got := make(map[string]any)
+	for i := 0; i < len(fields); i += 2 {
+		got[fields[i].(string)] = fields[i+1]
+	}
+
+	expected := map[string]any{
+		"method":         "POST",
+		"url":            "http://example.com/api/users?id=1",
+		"remote_addr":    "10.0.0.1:5555",
+		"user_agent":     "test-agent",
+		"host":           "example.com",
+		"content_length": int64(7),
+	}
+	for key, want := range expected {
+		if got[key] != want {
+			t.Errorf("Expected %s '%v', got '%v'", key, want, got[key])
+		}
+	}
+}
+
+func TestHTTPResponseFields(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/users", nil)
+	req.RemoteAddr = "10.0.0.1:5555"
+
+	fields := HTTPResponseFields(req, http.StatusNotFound, 150*time.Millisecond)
+
+	got := make(map[string]any)
+	for i := 0; i < len(fields); i += 2 {
+		got[fields[i].(string)] = fields[i+1]
+	}
+
+	expected := map[string]any{
+		"method":      "GET",
+		"url":         "/api/users",
+		"status_code": http.StatusNotFound,
+		"duration_ms": int64(150),
+		"duration_ns": (150 * time.Millisecond).Nanoseconds(),
+		"remote_addr": "10.0.0.1:5555",
+	}
+	for key, want := range expected {
+		if got[key] != want {
+			t.Errorf("Expected %s '%v', got '%v'", key, want, got[key])
+		}
+	}
+}
+
 // ExampleHTTPMiddleware_usage shows how to use the HTTP middleware with a real HTTP server
 func ExampleHTTPMiddleware_usage() {
 	// Create logger
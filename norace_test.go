@@ -0,0 +1,6 @@
+//go:build !race
+
+package log
+
+// raceEnabled reports whether the race detector is enabled, which adds allocations.
+const raceEnabled = false
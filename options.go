@@ -1,10 +1,18 @@
 package log
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"go.uber.org/zap/zapcore"
 
@@ -25,29 +33,94 @@ const (
 	DefaultLevel      = zapcore.InfoLevel
 	DefaultTimeLayout = "2006-01-02 15:04:05.000"
 	DefaultFormat     = "console" // console style
+	DefaultPrettyJSON = false     // JSON console output on a single line
 	DefaultFilename   = ""        // Default filename prefix
 
+	DefaultTimeEncoder     = TimeEncoderLayout      // Timestamps formatted with TimeLayout
+	DefaultDurationEncoder = DurationEncoderSeconds // Durations as floating-point seconds
+
+	DefaultMaxFilenameLength = 100 // Sanitized filenames are truncated to 100 bytes
+	DefaultRelativeTo        = ""  // Relative directories are used as is
+
 	DefaultDisableCaller     = false
 	DefaultDisableStacktrace = false
 	DefaultDisableSplitError = true
+	DefaultExtraCallerSkip   = 0 // No extra frames, the caller of the Log method is reported
+
+	DefaultStacktraceLevel = zapcore.PanicLevel // Stacktraces are attached from this level upward
+	DefaultIncludeHostPID  = false              // No host and pid fields by default
+	DefaultSplitFatal      = false              // No separate file for panic and fatal entries
+
+	DefaultErrorsOnlyInErrorFile = false // Error entries are written to the main log file too
+
+	DefaultIncludeGoroutineID = false // No goroutine field by default
+	DefaultCallerWithFunction = false // Caller as file:line only
+
+	DefaultMaxFieldLength = 0     // Field values are not truncated
+	DefaultIsolated       = false // Loggers replace the default logger
+
+	DefaultFlattenNested    = false // Nested values are encoded as objects
+	DefaultFlattenSeparator = "."   // Flattened keys like "user.id"
 
 	DefaultMaxSize    = 100   // 100MB
 	DefaultMaxBackups = 3     // Keep 3 old log files
 	DefaultCompress   = false // Not compress rotated log files
 
+	DefaultCompressAlgorithm = CompressGzip // Algorithm used when Compress is enabled
+	DefaultCompressActive    = false        // Not compress active log files
+	DefaultDisableRotation   = false        // Log files are rotated by size
+	DefaultFileHeader        = false        // No header line in new log files
+	DefaultDirMode           = 0o755        // Log directories readable by everyone
+	DefaultFileMode          = 0            // Log files created with the mode of the log file type
+
 	// Defaults for sampling functionality
 	DefaultEnableSampling   = false // Sampling disabled by default
 	DefaultSampleInitial    = 100   // Initial sample count
 	DefaultSampleThereafter = 100   // Subsequent sample count
 
+	DefaultSampleTick = time.Second // Sampling counters reset every second
+
 	// Console output control
-	DefaultConsoleOutput = true // Console output enabled by default
+	DefaultConsoleOutput = true  // Console output enabled by default
+	DefaultErrorToStderr = false // Error entries are not duplicated to stderr by default
+
+	// Write settings
+	DefaultWriteTimeout    = 0              // Retries of failed writes are not bounded in time
+	DefaultWriteMaxRetries = MaxRetries - 1 // Failed writes are attempted MaxRetries times
+	DefaultWriteRetryDelay = BriefDelay     // 10ms between attempts
+	DefaultAsyncQueueSize  = 0              // Log files are written synchronously
+	DefaultSyncOnError     = false          // Error entries are left to the OS page cache
+	DefaultDeadletterFile  = ""             // Entries failing to be written are only reported
+
+	// Prefix of the environment variables overriding configuration values
+	DefaultEnvPrefix = "LOG"
 
 	FormatConsole = "console"
 	FormatJSON    = "json"
+	FormatCSV     = "csv" // Rows of time, level, msg, caller and the fields as JSON
+
+	RelativeToCWD    = "cwd"    // Working directory when the logger is created
+	RelativeToExe    = "exe"    // Directory of the executable
+	RelativeToConfig = "config" // Directory of the configuration file loaded by LoadFromFile
+
+	CompressGzip = "gzip"
+	CompressZstd = "zstd"
+	CompressNone = "none"
 
 	LevelDebug = "debug"
 	LevelInfo  = "info"
+
+	TimeEncoderLayout      = "layout"      // TimeLayout
+	TimeEncoderEpoch       = "epoch"       // Seconds since the Unix epoch, e.g. 1721433600.123
+	TimeEncoderEpochMillis = "epochmillis" // Milliseconds since the Unix epoch, e.g. 1721433600123.456
+	TimeEncoderRFC3339     = "rfc3339"     // e.g. 2024-07-20T00:00:00Z
+	TimeEncoderRFC3339Nano = "rfc3339nano" // e.g. 2024-07-20T00:00:00.123456789Z
+	TimeEncoderISO8601     = "iso8601"     // e.g. 2024-07-20T00:00:00.123Z
+
+	DurationEncoderString  = "string"  // e.g. "1.5s"
+	DurationEncoderSeconds = "seconds" // e.g. 1.5
+	DurationEncoderMillis  = "millis"  // e.g. 1500
+	DurationEncoderNanos   = "nanos"   // e.g. 1500000000
 )
 
 // Options for logger
@@ -59,10 +132,119 @@ type Options struct {
 	TimeLayout string `mapstructure:"time_layout"` // Time Layout
 	Format     string `mapstructure:"format"`      // Log Format
 
+	// Encoding of the timestamps: "layout" (TimeLayout), "epoch", "epochmillis", "rfc3339",
+	// "rfc3339nano" or "iso8601", for backends expecting a specific format without guessing
+	// the Go layout. Empty means "layout".
+	TimeEncoder string `mapstructure:"time_encoder"`
+
+	// Encoding of the time.Duration values of fields: "string" ("1.5s"), "seconds" (1.5),
+	// "millis" (1500) or "nanos" (1500000000), so durations serialize consistently for
+	// dashboards. Empty means "seconds".
+	DurationEncoder string `mapstructure:"duration_encoder"`
+
+	// Path of the log files relative to Directory, replacing the default "{filename}-{date}.log"
+	// naming, e.g. "{name}/{date}/app-{level}.log" to organize logs in daily subdirectories.
+	// Supported tokens: {name} (the sanitized Filename), {date}, {level} ("main" for the main
+	// log file, "error" or "fatal" for the error and fatal log files), {pid} and {host}.
+	// Without {level}, "_error" or "_fatal" is added before the extension of these files.
+	FilenamePattern string `mapstructure:"filename_pattern"`
+
+	// Maximum length in bytes of the sanitized Filename, from 1 to 255 (the limit of most
+	// filesystems), 0 meaning DefaultMaxFilenameLength. Longer names are truncated at a
+	// rune boundary.
+	MaxFilenameLength int `mapstructure:"max_filename_length"`
+
+	// Anchor of a relative Directory, so logs land in the same place whatever the working
+	// directory the service is launched from: "cwd" (the working directory when the logger
+	// is created), "exe" (the directory of the executable) or "config" (the directory of the
+	// configuration file loaded by LoadFromFile, or the working directory otherwise). Empty
+	// uses the directory as is, relative to the working directory at each write.
+	RelativeTo string `mapstructure:"relative_to"`
+
+	// Formats of the console and file output, overriding Format, e.g. a readable console
+	// output with structured JSON files. Empty means Format.
+	ConsoleFormat string `mapstructure:"console_format"`
+	FileFormat    string `mapstructure:"file_format"`
+
+	// Minimum levels of the console and file output, overriding Level, e.g. a quiet console
+	// showing warnings while the files keep debug entries. Empty means Level. Unlike Level,
+	// they are fixed: SetLevel only changes the outputs without a level of their own.
+	ConsoleLevel string `mapstructure:"console_level"`
+	FileLevel    string `mapstructure:"file_level"`
+
+	// Whether to indent the JSON console output over multiple lines, for reading JSON logs in
+	// a terminal during development. It never applies to the log files, syslog or remote
+	// outputs read by aggregators.
+	PrettyJSON bool `mapstructure:"pretty_json"`
+
+	// Keys of the fields rendered first in the console format, in this order, e.g.
+	// ["request_id", "user_id"] so they are easy to scan whatever the order of the log call.
+	// The other fields follow in their original order. Fields added to the logger (Fields,
+	// WithOptions) are always rendered before the fields of the log call.
+	FieldOrder []string `mapstructure:"field_order"`
+
 	DisableCaller     bool `mapstructure:"disable_caller"`
 	DisableStacktrace bool `mapstructure:"disable_stacktrace"`
 	DisableSplitError bool `mapstructure:"disable_split_error"`
 
+	// Whether entries at error level and above are written to the error log file only, rather
+	// than to both the main and the error log files, keeping the main log file focused on
+	// info and warnings. It has no effect with DisableSplitError.
+	ErrorsOnlyInErrorFile bool `mapstructure:"errors_only_in_error_file"`
+
+	// Whether to also write Panic and Fatal entries to a separate "{name}-{date}_fatal.log"
+	// file (the "fatal" level of FilenamePattern), keeping a focused record of crashes.
+	// Entries are written before the logger panics or exits.
+	SplitFatal bool `mapstructure:"split_fatal"`
+
+	// Minimum level at which a stacktrace is attached to entries, unless DisableStacktrace
+	// is set, e.g. "error" to capture stacktraces of all errors.
+	StacktraceLevel string `mapstructure:"stacktrace_level"`
+
+	// Number of extra stack frames skipped when reporting the caller, on top of the Log
+	// method itself, which is always skipped. Wrappers around *Log add one per extra frame,
+	// so the caller points at their users' code.
+	ExtraCallerSkip int `mapstructure:"extra_caller_skip"`
+
+	// Whether to add the host name and process ID to every entry as the "host" and "pid"
+	// fields, to tell apart the entries of many instances in aggregated logs.
+	IncludeHostPID bool `mapstructure:"include_host_pid"`
+
+	// Whether to add the ID of the logging goroutine to every entry as the "goroutine" field,
+	// to correlate the lines of a goroutine when chasing concurrency bugs. The ID is parsed
+	// from the stack trace of the goroutine for every entry written, which costs a few
+	// microseconds and allocations per entry, so it's meant for debugging, not production.
+	IncludeGoroutineID bool `mapstructure:"include_goroutine_id"`
+
+	// Whether to add the name of the calling function to every entry as the "func" field,
+	// e.g. "github.com/acme/app/billing.(*Service).Charge", for when file:line is ambiguous
+	// or hard to read. It is the function of the caller reported by zap, so it honors
+	// ExtraCallerSkip, and is not added with DisableCaller.
+	CallerWithFunction bool `mapstructure:"caller_with_function"`
+
+	// Fields added to every entry of the logger, e.g. {"service": "checkout"}.
+	Fields map[string]any `mapstructure:"fields"`
+
+	// Maximum length in bytes of field values, protecting against accidentally logging a
+	// huge payload. Longer strings are truncated, and maps, slices and objects longer than
+	// this once serialized are replaced by their truncated JSON, with TruncatedMarker
+	// appended. 0 means no limit.
+	MaxFieldLength int `mapstructure:"max_field_length"`
+
+	// Whether to flatten the fields holding maps, structs and objects into one field per leaf
+	// value, so {"user": {"id": 1}} is logged as "user.id": 1, for log search tools indexing
+	// flat keys. Structs are flattened through their JSON encoding, honoring json tags, and
+	// slices are kept as is.
+	FlattenNested bool `mapstructure:"flatten_nested"`
+
+	// Separator joining the keys of flattened fields, "." if empty.
+	FlattenSeparator string `mapstructure:"flatten_separator"`
+
+	// Whether the logger is self-contained: it doesn't replace the default logger used by the
+	// package-level functions, and doesn't redirect the standard library logger to itself.
+	// This lets libraries embed a logger without affecting the host application.
+	Isolated bool `mapstructure:"isolated"`
+
 	// -----------------
 	// Log rotation settings
 	// -----------------
@@ -71,6 +253,39 @@ type Options struct {
 	MaxBackups int  `mapstructure:"max_backups"` // Maximum number of old log files
 	Compress   bool `mapstructure:"compress"`    // Whether to compress rotated log files
 
+	// Algorithm used to compress rotated log files when Compress is enabled: "gzip", "zstd"
+	// or "none". zstd compresses better and faster than gzip, at the cost of compatibility
+	// with tools expecting .gz files. Empty means gzip.
+	CompressAlgorithm string `mapstructure:"compress_algorithm"`
+
+	// Whether to gzip the active log files as they are written, adding a ".gz" extension.
+	// This saves space on append-heavy logs that rarely rotate, but the files can't be
+	// tailed, and up to ActiveGzipFlushSize of compressed entries are buffered in memory
+	// until Sync. Rotated files are not compressed again.
+	CompressActive bool `mapstructure:"compress_active"`
+
+	// Whether to write plain append-only files without size-based rotation, for files
+	// rotated by an external tool such as logrotate (copytruncate). Files still change
+	// with the date, MaxSize, MaxBackups and Compress are ignored, and Rotate reopens the
+	// files instead.
+	DisableRotation bool `mapstructure:"disable_rotation"`
+
+	// Whether to begin each new log file with a JSON header line describing it, for tooling
+	// reading the files: {"log_header":true,"created_at":...,"format":...} followed by the
+	// Fields of the logger, e.g. service and version. The header is prefixed like entries,
+	// and written once per file, not when appending to an existing file. CSV files begin
+	// with the names of the columns instead.
+	FileHeader bool `mapstructure:"file_header"`
+
+	// Permissions of the directories created for the log files, e.g. 0o700 to keep them
+	// private. Existing directories are left unchanged. Zero means DefaultDirMode.
+	DirMode os.FileMode `mapstructure:"dir_mode"`
+
+	// Permissions of the log files, e.g. 0o600, applied with a chmod when they are opened so
+	// the umask doesn't loosen or tighten them. Zero keeps the default: 0o600 for rotated
+	// files, as created by lumberjack, or 0o644 with DisableRotation.
+	FileMode os.FileMode `mapstructure:"file_mode"`
+
 	// -----------------
 	// Sampling settings
 	// -----------------
@@ -79,11 +294,124 @@ type Options struct {
 	SampleInitial    int  `mapstructure:"sample_initial"`
 	SampleThereafter int  `mapstructure:"sample_thereafter"`
 
+	// Window of the sampling counters: the first SampleInitial entries with the same message
+	// and level in every window are logged, then one in SampleThereafter. A longer window
+	// suits bursty workloads, a shorter one lets logs resume sooner after a burst. Zero means
+	// the default of one second.
+	SampleTick time.Duration `mapstructure:"sample_tick"`
+
+	// SamplingHook is called with every sampling decision when sampling is enabled,
+	// e.g. to export a logs_dropped_total metric for zapcore.LogDropped decisions.
+	SamplingHook func(entry zapcore.Entry, dec zapcore.SamplingDecision) `mapstructure:"-"`
+
+	// SampleKeyFunc returns the key grouping entries for sampling, instead of their message,
+	// e.g. the value of an "endpoint" or "status_code" field to sample per endpoint. It's
+	// called with the fields of the log call. If nil, entries are sampled by message.
+	SampleKeyFunc func(entry zapcore.Entry, fields []zapcore.Field) string `mapstructure:"-"`
+
 	// -----------------
 	// Console output settings
 	// -----------------
 
 	ConsoleOutput bool `mapstructure:"console_output"` // Whether to output logs to console
+
+	// Whether to write entries at Error level and above to stderr, regardless of ConsoleOutput.
+	// It takes precedence over ConsoleOutput for those entries: they are written to stderr
+	// instead of stdout, so they are never printed twice.
+	ErrorToStderr bool `mapstructure:"error_to_stderr"`
+
+	// -----------------
+	// Syslog output settings
+	// -----------------
+
+	Syslog SyslogOptions `mapstructure:"syslog"`
+
+	// -----------------
+	// Remote output settings
+	// -----------------
+
+	// Address of a collector to stream logs to, e.g. "logstash:5000", and its protocol, "tcp"
+	// or "udp"; empty for "tcp".
+	RemoteAddr     string `mapstructure:"remote_addr"`
+	RemoteProtocol string `mapstructure:"remote_protocol"`
+
+	// Writers receiving a copy of every encoded entry, as written to the log files, e.g. an
+	// in-memory buffer of the last lines for a /debug/logs endpoint. Writes are serialized,
+	// so writers don't need to be safe for concurrent use. Failed writes are reported to
+	// OnWriteError.
+	ExtraWriters []io.Writer `mapstructure:"-"`
+
+	// -----------------
+	// Write settings
+	// -----------------
+
+	// Maximum time spent retrying a failed write to a log file, so a struggling disk doesn't
+	// block the caller for all its retries. 0 means no limit.
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+
+	// Number of times a failed write to a log file is retried, 0 for a single attempt, and
+	// the delay between attempts. NewOptions sets DefaultWriteMaxRetries and
+	// DefaultWriteRetryDelay. More retries favor durability, fewer favor latency.
+	WriteMaxRetries int           `mapstructure:"write_max_retries"`
+	WriteRetryDelay time.Duration `mapstructure:"write_retry_delay"`
+
+	// Size of the queue of entries written to the log files by a background goroutine. When
+	// set, log calls never wait for the disk: entries are dropped while the queue is full,
+	// and counted by DroppedEntries. Sync waits for the queued entries to be written, and
+	// Close stops the goroutine once they are. 0 writes the log files synchronously.
+	AsyncQueueSize int `mapstructure:"async_queue_size"`
+
+	// Whether to fsync the log files after writing an entry at error level or above, so it
+	// survives a crash or power loss right after, at the cost of some latency on errors. It
+	// has no effect on the writers that aren't files, such as the console, syslog or remote
+	// outputs. With AsyncQueueSize, files are synced when the entry is dequeued.
+	SyncOnError bool `mapstructure:"sync_on_error"`
+
+	// -----------------
+	// Error handling settings
+	// -----------------
+
+	// OnWriteError is called with the error and the encoded entry when an entry ultimately
+	// fails to be written to a log file, remote collector or extra writer, e.g. on a full disk,
+	// and with the entries dropped by the remote output, at most once per RemoteDropInterval.
+	// The entry may be retained. If nil, the error is printed to InternalErrorWriter.
+	OnWriteError func(err error, entry []byte) `mapstructure:"-"`
+
+	// InternalErrorWriter receives the diagnostics of the logger itself, such as invalid
+	// options replaced by fallback values, write errors without OnWriteError and failed
+	// compressions, e.g. to keep them out of a captured stderr. It must be safe for
+	// concurrent use. If nil, os.Stderr is used.
+	InternalErrorWriter io.Writer `mapstructure:"-"`
+
+	// FatalHook is called after a fatal entry is written and before the process exits, e.g. to
+	// close databases or flush other loggers, since Fatal skips deferred functions. The log
+	// files are flushed after it returns, and the process exits even if it panics.
+	FatalHook func() `mapstructure:"-"`
+
+	// Path of a file where entries that ultimately failed to be written to a log file are
+	// appended, best-effort, so nothing is silently lost when the log files misbehave, e.g.
+	// for audit logs. These entries are still reported to OnWriteError if set, but no longer
+	// printed to stderr. Empty disables it.
+	DeadletterFile string `mapstructure:"deadletter_file"`
+}
+
+// SyslogOptions configures sending logs to a local or remote syslog daemon.
+type SyslogOptions struct {
+	Enabled  bool   `mapstructure:"enabled"`  // Whether to send logs to syslog
+	Network  string `mapstructure:"network"`  // "udp", "tcp" or "unix"; empty for the local syslog daemon
+	Address  string `mapstructure:"address"`  // Syslog server address; empty for the local syslog daemon
+	Facility string `mapstructure:"facility"` // Syslog facility, e.g. "user" or "local0"; empty for "user"
+	Tag      string `mapstructure:"tag"`      // Syslog tag; empty for the program name
+	Only     bool   `mapstructure:"only"`     // Send logs to syslog instead of log files
+}
+
+// syslogFacilities maps facility names to their syslog (RFC 5424) facility codes.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3,
+	"auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
 }
 
 // NewOptions return the default Options.
@@ -97,48 +425,148 @@ type Options struct {
 //	TimeLayout: "2006-01-02 15:04:05.000",
 //	Format:     "console",
 //
+//	TimeEncoder:     "layout",
+//	DurationEncoder: "seconds",
+//
+//	FilenamePattern:   "", // "{filename}-{date}.log"
+//	MaxFilenameLength: 100,
+//	RelativeTo:        "", // Relative directories are used as is
+//
+//	ConsoleFormat: "", // Same as Format
+//	FileFormat:    "", // Same as Format
+//	ConsoleLevel:  "", // Same as Level
+//	FileLevel:     "", // Same as Level
+//	PrettyJSON:    false,
+//	FieldOrder:    nil, // Fields in the order of the log call
+//
 //	DisableCaller:     false,
 //	DisableStacktrace: false,
 //	DisableSplitError: false,
+//	SplitFatal:        false, // No separate file for panic and fatal entries
+//	ErrorsOnlyInErrorFile: false, // Error entries are in the main log file too
+//	ExtraCallerSkip:   0, // The Log method itself is always skipped
+//	StacktraceLevel:   "panic",
+//	IncludeHostPID:    false,
+//	Fields:            nil,
+//	MaxFieldLength:    0, // Field values are not truncated
+//	Isolated:          false, // Replaces the default logger and the standard library logger
+//
+//	IncludeGoroutineID: false, // No goroutine field, for debugging only
+//	CallerWithFunction: false, // Caller as file:line only
+//
+//	FlattenNested:    false, // Nested values are encoded as objects
+//	FlattenSeparator: ".",
 //
 //	// Default log rotation settings
 //	MaxSize:    100, // 100MB
 //	MaxBackups: 3,   // Keep 3 old log files
 //	Compress:   false,
 //
+//	CompressAlgorithm: "gzip", // Used when Compress is enabled
+//	CompressActive:    false,  // Active log files are written uncompressed
+//	DisableRotation:   false,  // Log files are rotated by size
+//	FileHeader:        false,  // No header line in new log files
+//	DirMode:           0o755,  // Log directories readable by everyone
+//	FileMode:          0,      // 0o600 for rotated files, 0o644 otherwise
+//
 //	// Sampling settings
 //	EnableSampling:   false, // Sampling disabled by default
 //	SampleInitial:    100,   // Initial sample count
 //	SampleThereafter: 100,   // Subsequent sample count
+//	SampleTick:       1s,    // Sampling counters reset every second
+//	SamplingHook:     nil,   // No sampling hook by default
+//	SampleKeyFunc:    nil,   // Entries are sampled by message
 //
 //	// Console output settings
-//	ConsoleOutput: true, // Console output enabled by default
+//	ConsoleOutput: true,  // Console output enabled by default
+//	ErrorToStderr: false, // Error entries are not duplicated to stderr
+//
+//	// Syslog output settings
+//	Syslog: SyslogOptions{}, // Syslog output disabled by default
+//
+//	// Remote output settings
+//	RemoteAddr:     "", // Remote output disabled by default
+//	RemoteProtocol: "",
+//	ExtraWriters:   nil, // No extra writers by default
+//
+//	// Write settings
+//	WriteTimeout:    0,     // Retries of failed writes are not bounded in time
+//	WriteMaxRetries: 2,     // Failed writes are attempted 3 times
+//	WriteRetryDelay: 10ms,  // Delay between attempts
+//	AsyncQueueSize:  0,     // Log files are written synchronously
+//	SyncOnError:     false, // Error entries are not synced to disk
+//
+//	// Error handling settings
+//	OnWriteError:        nil, // Write errors are printed to InternalErrorWriter by default
+//	InternalErrorWriter: nil, // Internal diagnostics are printed to stderr
+//	FatalHook:           nil, // No cleanup before exiting on fatal entries
+//	DeadletterFile:      "",  // Failed entries are only reported
 func NewOptions() *Options {
 	opt := &Options{
 		Prefix:    DefaultPrefix,
 		Directory: DefaultDirectory,
 		Filename:  DefaultFilename,
 
-		Level:      DefaultLevel.String(),
-		TimeLayout: DefaultTimeLayout,
-		Format:     DefaultFormat,
+		MaxFilenameLength: DefaultMaxFilenameLength,
+		RelativeTo:        DefaultRelativeTo,
+
+		Level:       DefaultLevel.String(),
+		TimeLayout:  DefaultTimeLayout,
+		TimeEncoder: DefaultTimeEncoder,
+		Format:      DefaultFormat,
+		PrettyJSON:  DefaultPrettyJSON,
+
+		DurationEncoder: DefaultDurationEncoder,
 
 		DisableCaller:     DefaultDisableCaller,
 		DisableStacktrace: DefaultDisableStacktrace,
 		DisableSplitError: DefaultDisableSplitError,
+		SplitFatal:        DefaultSplitFatal,
+		ExtraCallerSkip:   DefaultExtraCallerSkip,
+		StacktraceLevel:   DefaultStacktraceLevel.String(),
+		IncludeHostPID:    DefaultIncludeHostPID,
+		MaxFieldLength:    DefaultMaxFieldLength,
+		Isolated:          DefaultIsolated,
+
+		IncludeGoroutineID: DefaultIncludeGoroutineID,
+		CallerWithFunction: DefaultCallerWithFunction,
+
+		ErrorsOnlyInErrorFile: DefaultErrorsOnlyInErrorFile,
+
+		FlattenNested:    DefaultFlattenNested,
+		FlattenSeparator: DefaultFlattenSeparator,
 
 		// Default log rotation settings
 		MaxSize:    DefaultMaxSize,
 		MaxBackups: DefaultMaxBackups,
 		Compress:   DefaultCompress,
 
+		CompressAlgorithm: DefaultCompressAlgorithm,
+		CompressActive:    DefaultCompressActive,
+		DisableRotation:   DefaultDisableRotation,
+		FileHeader:        DefaultFileHeader,
+		DirMode:           DefaultDirMode,
+		FileMode:          DefaultFileMode,
+
 		// Sampling settings
 		EnableSampling:   DefaultEnableSampling,
 		SampleInitial:    DefaultSampleInitial,
 		SampleThereafter: DefaultSampleThereafter,
+		SampleTick:       DefaultSampleTick,
 
 		// Console output settings
 		ConsoleOutput: DefaultConsoleOutput,
+		ErrorToStderr: DefaultErrorToStderr,
+
+		// Write settings
+		WriteTimeout:    DefaultWriteTimeout,
+		WriteMaxRetries: DefaultWriteMaxRetries,
+		WriteRetryDelay: DefaultWriteRetryDelay,
+		AsyncQueueSize:  DefaultAsyncQueueSize,
+		SyncOnError:     DefaultSyncOnError,
+
+		// Error handling settings
+		DeadletterFile: DefaultDeadletterFile,
 	}
 
 	if err := opt.Validate(); err != nil {
@@ -168,6 +596,28 @@ func (opt *Options) WithFilename(filename string) *Options {
 	return opt
 }
 
+// WithFilenamePattern sets the path of the log files relative to the directory,
+// e.g. "{name}/{date}/app-{level}.log".
+func (opt *Options) WithFilenamePattern(pattern string) *Options {
+	opt.FilenamePattern = pattern
+	return opt
+}
+
+func (opt *Options) WithMaxFilenameLength(length int) *Options {
+	if length <= 0 {
+		opt.MaxFilenameLength = DefaultMaxFilenameLength
+	} else {
+		opt.MaxFilenameLength = length
+	}
+	return opt
+}
+
+// WithRelativeTo sets the anchor of a relative Directory: "cwd", "exe", "config" or empty.
+func (opt *Options) WithRelativeTo(anchor string) *Options {
+	opt.RelativeTo = anchor
+	return opt
+}
+
 func (opt *Options) WithLevel(level string) *Options {
 	if level == "" || !isValidLevelString(level) {
 		opt.Level = DefaultLevel.String()
@@ -186,8 +636,27 @@ func (opt *Options) WithTimeLayout(timeLayout string) *Options {
 	return opt
 }
 
+// WithTimeEncoder sets the encoding of the timestamps: layout, epoch, epochmillis, rfc3339,
+// rfc3339nano or iso8601.
+func (opt *Options) WithTimeEncoder(encoder string) *Options {
+	if encoder == "" {
+		encoder = DefaultTimeEncoder
+	}
+	opt.TimeEncoder = encoder
+	return opt
+}
+
+// WithDurationEncoder sets the encoding of durations: string, seconds, millis or nanos.
+func (opt *Options) WithDurationEncoder(encoder string) *Options {
+	if encoder == "" {
+		encoder = DefaultDurationEncoder
+	}
+	opt.DurationEncoder = encoder
+	return opt
+}
+
 func (opt *Options) WithFormat(format string) *Options {
-	if format == "" || (format != DefaultFormat && format != "json") {
+	if !isValidFormat(format) {
 		opt.Format = DefaultFormat
 	} else {
 		opt.Format = format
@@ -195,6 +664,44 @@ func (opt *Options) WithFormat(format string) *Options {
 	return opt
 }
 
+// WithConsoleFormat sets the format of the console output: "console", "json", "csv",
+// or empty to use Format.
+func (opt *Options) WithConsoleFormat(format string) *Options {
+	opt.ConsoleFormat = format
+	return opt
+}
+
+// WithFileFormat sets the format of the log files: "console", "json", "csv",
+// or empty to use Format.
+func (opt *Options) WithFileFormat(format string) *Options {
+	opt.FileFormat = format
+	return opt
+}
+
+// WithConsoleLevel sets the minimum level of the console output, or empty to use Level.
+func (opt *Options) WithConsoleLevel(level string) *Options {
+	opt.ConsoleLevel = level
+	return opt
+}
+
+// WithFileLevel sets the minimum level of the log files, or empty to use Level.
+func (opt *Options) WithFileLevel(level string) *Options {
+	opt.FileLevel = level
+	return opt
+}
+
+// WithPrettyJSON sets whether to indent the JSON console output over multiple lines.
+func (opt *Options) WithPrettyJSON(pretty bool) *Options {
+	opt.PrettyJSON = pretty
+	return opt
+}
+
+// WithFieldOrder sets the keys of the fields rendered first in the console format.
+func (opt *Options) WithFieldOrder(keys ...string) *Options {
+	opt.FieldOrder = keys
+	return opt
+}
+
 func (opt *Options) WithDisableCaller(disableCaller bool) *Options {
 	opt.DisableCaller = disableCaller
 	return opt
@@ -205,11 +712,84 @@ func (opt *Options) WithDisableStacktrace(disableStacktrace bool) *Options {
 	return opt
 }
 
+// WithStacktraceLevel sets the minimum level at which a stacktrace is attached to entries.
+func (opt *Options) WithStacktraceLevel(level string) *Options {
+	opt.StacktraceLevel = level
+	return opt
+}
+
+// WithIncludeGoroutineID sets whether to add the ID of the logging goroutine to every entry.
+func (opt *Options) WithIncludeGoroutineID(include bool) *Options {
+	opt.IncludeGoroutineID = include
+	return opt
+}
+
+// WithCallerWithFunction sets whether to add the name of the calling function to every entry.
+func (opt *Options) WithCallerWithFunction(include bool) *Options {
+	opt.CallerWithFunction = include
+	return opt
+}
+
+// WithIncludeHostPID sets whether to add the host name and process ID to every entry.
+func (opt *Options) WithIncludeHostPID(include bool) *Options {
+	opt.IncludeHostPID = include
+	return opt
+}
+
+// WithFields sets the fields added to every entry of the logger.
+func (opt *Options) WithFields(fields map[string]any) *Options {
+	opt.Fields = fields
+	return opt
+}
+
+// WithMaxFieldLength sets the maximum length in bytes of field values, 0 meaning no limit.
+func (opt *Options) WithMaxFieldLength(length int) *Options {
+	opt.MaxFieldLength = length
+	return opt
+}
+
+// WithFlattenNested sets whether to flatten nested field values into one field per leaf value.
+func (opt *Options) WithFlattenNested(flatten bool) *Options {
+	opt.FlattenNested = flatten
+	return opt
+}
+
+// WithFlattenSeparator sets the separator joining the keys of flattened fields, "." if empty.
+func (opt *Options) WithFlattenSeparator(separator string) *Options {
+	opt.FlattenSeparator = separator
+	return opt
+}
+
+// WithIsolated sets whether the logger leaves the default logger and the standard library
+// logger untouched.
+func (opt *Options) WithIsolated(isolated bool) *Options {
+	opt.Isolated = isolated
+	return opt
+}
+
 func (opt *Options) WithDisableSplitError(disableSplitError bool) *Options {
 	opt.DisableSplitError = disableSplitError
 	return opt
 }
 
+// WithErrorsOnlyInErrorFile sets whether error entries are written to the error log file only.
+func (opt *Options) WithErrorsOnlyInErrorFile(only bool) *Options {
+	opt.ErrorsOnlyInErrorFile = only
+	return opt
+}
+
+// WithSplitFatal sets whether to also write Panic and Fatal entries to a separate fatal log file.
+func (opt *Options) WithSplitFatal(split bool) *Options {
+	opt.SplitFatal = split
+	return opt
+}
+
+// WithExtraCallerSkip sets the number of extra stack frames skipped when reporting the caller.
+func (opt *Options) WithExtraCallerSkip(skip int) *Options {
+	opt.ExtraCallerSkip = skip
+	return opt
+}
+
 func (opt *Options) WithMaxSize(maxSize int) *Options {
 	if maxSize <= 0 {
 		opt.MaxSize = DefaultMaxSize
@@ -233,6 +813,45 @@ func (opt *Options) WithCompress(compress bool) *Options {
 	return opt
 }
 
+// WithCompressAlgorithm sets the algorithm used to compress rotated log files: gzip, zstd or none.
+func (opt *Options) WithCompressAlgorithm(algorithm string) *Options {
+	if algorithm == "" {
+		algorithm = DefaultCompressAlgorithm
+	}
+	opt.CompressAlgorithm = algorithm
+	return opt
+}
+
+// WithCompressActive sets whether to gzip the active log files as they are written.
+func (opt *Options) WithCompressActive(compress bool) *Options {
+	opt.CompressActive = compress
+	return opt
+}
+
+// WithDisableRotation sets whether to write plain append-only files without size-based rotation.
+func (opt *Options) WithDisableRotation(disable bool) *Options {
+	opt.DisableRotation = disable
+	return opt
+}
+
+// WithFileHeader sets whether to begin each new log file with a JSON header line.
+func (opt *Options) WithFileHeader(header bool) *Options {
+	opt.FileHeader = header
+	return opt
+}
+
+// WithDirMode sets the permissions of the directories created for the log files.
+func (opt *Options) WithDirMode(mode os.FileMode) *Options {
+	opt.DirMode = mode
+	return opt
+}
+
+// WithFileMode sets the permissions of the log files, or zero for the default.
+func (opt *Options) WithFileMode(mode os.FileMode) *Options {
+	opt.FileMode = mode
+	return opt
+}
+
 func (opt *Options) WithSampling(enable bool, initial, thereafter int) *Options {
 	opt.EnableSampling = enable
 	if initial > 0 {
@@ -248,11 +867,112 @@ func (opt *Options) WithSampling(enable bool, initial, thereafter int) *Options
 	return opt
 }
 
+// WithSamplingHook sets the function called with every sampling decision when sampling is enabled.
+func (opt *Options) WithSamplingHook(hook func(entry zapcore.Entry, dec zapcore.SamplingDecision)) *Options {
+	opt.SamplingHook = hook
+	return opt
+}
+
+// WithSampleTick sets the window of the sampling counters, or the default for a non-positive tick.
+func (opt *Options) WithSampleTick(tick time.Duration) *Options {
+	if tick > 0 {
+		opt.SampleTick = tick
+	} else {
+		opt.SampleTick = DefaultSampleTick
+	}
+	return opt
+}
+
+// WithSampleKeyFunc sets the function returning the key grouping entries for sampling.
+func (opt *Options) WithSampleKeyFunc(keyFunc func(entry zapcore.Entry, fields []zapcore.Field) string) *Options {
+	opt.SampleKeyFunc = keyFunc
+	return opt
+}
+
 func (opt *Options) WithConsoleOutput(enable bool) *Options {
 	opt.ConsoleOutput = enable
 	return opt
 }
 
+// WithErrorToStderr sets whether to write entries at Error level and above to stderr.
+func (opt *Options) WithErrorToStderr(enable bool) *Options {
+	opt.ErrorToStderr = enable
+	return opt
+}
+
+// WithSyslog configures sending logs to syslog.
+func (opt *Options) WithSyslog(syslog SyslogOptions) *Options {
+	opt.Syslog = syslog
+	return opt
+}
+
+// WithRemote configures streaming logs to a TCP or UDP collector at addr.
+// An empty addr disables remote output.
+func (opt *Options) WithRemote(protocol, addr string) *Options {
+	opt.RemoteProtocol = protocol
+	opt.RemoteAddr = addr
+	return opt
+}
+
+// WithWriteTimeout sets the maximum time spent retrying a failed write to a log file.
+func (opt *Options) WithWriteTimeout(timeout time.Duration) *Options {
+	opt.WriteTimeout = timeout
+	return opt
+}
+
+// WithWriteRetries sets the number of times a failed write to a log file is retried, and the
+// delay between attempts.
+func (opt *Options) WithWriteRetries(maxRetries int, delay time.Duration) *Options {
+	opt.WriteMaxRetries = maxRetries
+	opt.WriteRetryDelay = delay
+	return opt
+}
+
+// WithAsyncQueueSize sets the size of the queue of entries written to the log files in the
+// background, 0 to write them synchronously.
+func (opt *Options) WithAsyncQueueSize(size int) *Options {
+	opt.AsyncQueueSize = size
+	return opt
+}
+
+// WithSyncOnError sets whether to fsync the log files after writing an error entry.
+func (opt *Options) WithSyncOnError(sync bool) *Options {
+	opt.SyncOnError = sync
+	return opt
+}
+
+// WithExtraWriters sets the writers receiving a copy of every encoded entry.
+func (opt *Options) WithExtraWriters(writers ...io.Writer) *Options {
+	opt.ExtraWriters = writers
+	return opt
+}
+
+// WithOnWriteError sets the callback invoked when an entry ultimately fails to be written.
+func (opt *Options) WithOnWriteError(fn func(err error, entry []byte)) *Options {
+	opt.OnWriteError = fn
+	return opt
+}
+
+// WithInternalErrorWriter sets the writer receiving the diagnostics of the logger itself,
+// nil for os.Stderr.
+func (opt *Options) WithInternalErrorWriter(w io.Writer) *Options {
+	opt.InternalErrorWriter = w
+	return opt
+}
+
+// WithDeadletterFile sets the path of the file where entries that failed to be written to
+// a log file are appended.
+func (opt *Options) WithDeadletterFile(path string) *Options {
+	opt.DeadletterFile = path
+	return opt
+}
+
+// WithFatalHook sets the function called before the process exits on a fatal entry.
+func (opt *Options) WithFatalHook(hook func()) *Options {
+	opt.FatalHook = hook
+	return opt
+}
+
 // isValidLevelString checks if the provided level string is valid
 func isValidLevelString(level string) bool {
 	return level == zapcore.DebugLevel.String() ||
@@ -264,56 +984,437 @@ func isValidLevelString(level string) bool {
 		level == zapcore.FatalLevel.String()
 }
 
+// Validate checks every option and returns all problems found, joined with errors.Join,
+// so a single run surfaces every invalid field. It returns nil if the options are valid.
 func (opt *Options) Validate() error {
+	var errs []error
+
 	if opt.Directory == "" {
-		return fmt.Errorf("invalid directory: %s, expected: not empty", opt.Directory)
+		errs = append(errs, fmt.Errorf("invalid directory: %s, expected: not empty", opt.Directory))
+	}
+
+	if opt.MaxFilenameLength < 0 || opt.MaxFilenameLength > maxFilenameLengthLimit {
+		errs = append(errs, fmt.Errorf("invalid max filename length: %d, expected: 0 to %d",
+			opt.MaxFilenameLength, maxFilenameLengthLimit))
+	}
+
+	if !isValidRelativeTo(opt.RelativeTo) {
+		errs = append(errs, fmt.Errorf("invalid relative to: %s, expected: cwd, exe, config or empty", opt.RelativeTo))
 	}
 
 	// Validate filename if provided
 	if opt.Filename != "" {
-		sanitized := sanitizeFilename(opt.Filename)
+		sanitized := sanitizeFilenameMax(opt.Filename, opt.MaxFilenameLength)
 		if sanitized == "" {
-			return fmt.Errorf("invalid filename: %s, results in empty name after sanitization", opt.Filename)
+			errs = append(errs,
+				fmt.Errorf("invalid filename: %s, results in empty name after sanitization", opt.Filename))
+		}
+	}
+
+	if opt.FilenamePattern != "" {
+		if err := validateFilenamePattern(opt.FilenamePattern); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
 	if !isValidLevelString(opt.Level) {
-		return fmt.Errorf("invalid level: %s, expected: debug, info, warn, error, dpanic, panic or fatal", opt.Level)
+		errs = append(errs,
+			fmt.Errorf("invalid level: %s, expected: debug, info, warn, error, dpanic, panic or fatal", opt.Level))
 	}
 
 	if err := internal.ValidateTimeLayout(opt.TimeLayout); err != nil {
-		return fmt.Errorf("invalid time layout: %s, expected: valid time layout", opt.TimeLayout)
+		errs = append(errs, fmt.Errorf("invalid time layout: %s, expected: valid time layout", opt.TimeLayout))
+	}
+
+	if !isValidTimeEncoder(opt.TimeEncoder) {
+		errs = append(errs, fmt.Errorf("invalid time encoder: %s, expected: layout, epoch, epochmillis, "+
+			"rfc3339, rfc3339nano or iso8601", opt.TimeEncoder))
 	}
 
-	if opt.Format != DefaultFormat && opt.Format != "json" {
-		return fmt.Errorf("invalid format: %s, expected: console or json", opt.Format)
+	if _, ok := internal.DurationEncoders[opt.DurationEncoder]; !ok && opt.DurationEncoder != "" {
+		errs = append(errs, fmt.Errorf("invalid duration encoder: %s, expected: string, seconds, millis or nanos",
+			opt.DurationEncoder))
+	}
+
+	if !isValidFormat(opt.Format) {
+		errs = append(errs, fmt.Errorf("invalid format: %s, expected: console, json or csv", opt.Format))
+	}
+
+	if opt.ConsoleFormat != "" && !isValidFormat(opt.ConsoleFormat) {
+		errs = append(errs,
+			fmt.Errorf("invalid console format: %s, expected: console, json, csv or empty", opt.ConsoleFormat))
+	}
+
+	if opt.FileFormat != "" && !isValidFormat(opt.FileFormat) {
+		errs = append(errs,
+			fmt.Errorf("invalid file format: %s, expected: console, json, csv or empty", opt.FileFormat))
+	}
+
+	if opt.ConsoleLevel != "" && !isValidLevelString(opt.ConsoleLevel) {
+		errs = append(errs, fmt.Errorf(
+			"invalid console level: %s, expected: debug, info, warn, error, dpanic, panic, fatal or empty",
+			opt.ConsoleLevel))
+	}
+
+	if opt.FileLevel != "" && !isValidLevelString(opt.FileLevel) {
+		errs = append(errs, fmt.Errorf(
+			"invalid file level: %s, expected: debug, info, warn, error, dpanic, panic, fatal or empty",
+			opt.FileLevel))
+	}
+
+	if opt.StacktraceLevel != "" && !isValidLevelString(opt.StacktraceLevel) {
+		errs = append(errs, fmt.Errorf(
+			"invalid stacktrace level: %s, expected: debug, info, warn, error, dpanic, panic or fatal",
+			opt.StacktraceLevel))
+	}
+
+	if opt.ExtraCallerSkip < 0 {
+		errs = append(errs, fmt.Errorf("invalid extra caller skip: %d, expected: >= 0", opt.ExtraCallerSkip))
+	}
+
+	if opt.MaxFieldLength < 0 {
+		errs = append(errs, fmt.Errorf("invalid max field length: %d, expected: >= 0", opt.MaxFieldLength))
 	}
 
 	if opt.MaxSize <= 0 {
-		return fmt.Errorf("invalid max size: %d, expected: > 0", opt.MaxSize)
+		errs = append(errs, fmt.Errorf("invalid max size: %d, expected: > 0", opt.MaxSize))
+	}
+
+	if !slices.Contains([]string{"", CompressGzip, CompressZstd, CompressNone}, opt.CompressAlgorithm) {
+		errs = append(errs,
+			fmt.Errorf("invalid compress algorithm: %s, expected: gzip, zstd or none", opt.CompressAlgorithm))
 	}
 
 	if opt.MaxBackups <= 0 {
-		return fmt.Errorf("invalid max backups: %d, expected: > 0", opt.MaxBackups)
+		errs = append(errs, fmt.Errorf("invalid max backups: %d, expected: > 0", opt.MaxBackups))
+	}
+
+	if opt.DirMode&^os.ModePerm != 0 {
+		errs = append(errs, fmt.Errorf("invalid dir mode: %#o, expected: permission bits, e.g. 0o700", opt.DirMode))
+	}
+
+	if opt.FileMode&^os.ModePerm != 0 {
+		errs = append(errs, fmt.Errorf("invalid file mode: %#o, expected: permission bits, e.g. 0o600", opt.FileMode))
 	}
 
 	// Validate sampling settings
 	if opt.EnableSampling {
 		if opt.SampleInitial <= 0 {
-			return fmt.Errorf("invalid sample initial: %d, expected: > 0", opt.SampleInitial)
+			errs = append(errs, fmt.Errorf("invalid sample initial: %d, expected: > 0", opt.SampleInitial))
 		}
 		if opt.SampleThereafter <= 0 {
-			return fmt.Errorf("invalid sample thereafter: %d, expected: > 0", opt.SampleThereafter)
+			errs = append(errs, fmt.Errorf("invalid sample thereafter: %d, expected: > 0", opt.SampleThereafter))
+		}
+		if opt.SampleTick < 0 {
+			errs = append(errs, fmt.Errorf("invalid sample tick: %s, expected: >= 0, 0 for the default", opt.SampleTick))
+		}
+	}
+
+	// Validate syslog settings
+	if opt.Syslog.Enabled {
+		if opt.Syslog.Facility != "" {
+			if _, ok := syslogFacilities[opt.Syslog.Facility]; !ok {
+				errs = append(errs, fmt.Errorf("invalid syslog facility: %s, expected: kern, user, mail, daemon, "+
+					"auth, syslog, lpr, news, uucp, cron, authpriv, ftp or local0-local7", opt.Syslog.Facility))
+			}
+		}
+		if (opt.Syslog.Network == "") != (opt.Syslog.Address == "") {
+			errs = append(errs, fmt.Errorf("invalid syslog address: %s://%s, expected: both network and address "+
+				"set for a remote server, or both empty for the local syslog daemon", opt.Syslog.Network, opt.Syslog.Address))
+		}
+	}
+
+	if opt.RemoteProtocol != "" && opt.RemoteProtocol != "tcp" && opt.RemoteProtocol != "udp" {
+		errs = append(errs, fmt.Errorf("invalid remote protocol: %s, expected: tcp or udp", opt.RemoteProtocol))
+	}
+
+	if opt.WriteTimeout < 0 {
+		errs = append(errs, fmt.Errorf("invalid write timeout: %s, expected: >= 0", opt.WriteTimeout))
+	}
+
+	if opt.WriteMaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("invalid write max retries: %d, expected: >= 0", opt.WriteMaxRetries))
+	}
+
+	if opt.WriteRetryDelay < 0 {
+		errs = append(errs, fmt.Errorf("invalid write retry delay: %s, expected: >= 0", opt.WriteRetryDelay))
+	}
+
+	if opt.AsyncQueueSize < 0 {
+		errs = append(errs, fmt.Errorf("invalid async queue size: %d, expected: >= 0", opt.AsyncQueueSize))
+	}
+
+	return errors.Join(errs...)
+}
+
+// String returns a readable dump of the options as space-separated key=value pairs,
+// using the same keys as the configuration file.
+func (opt *Options) String() string {
+	fields := opt.fields()
+
+	var sb strings.Builder
+	for i := 0; i < len(fields); i += 2 {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%s=%#v", fields[i], fields[i+1])
+	}
+	return sb.String()
+}
+
+// fields returns the options as alternating key-value pairs keyed by their
+// configuration file names, suitable for structured logging.
+func (opt *Options) fields() []any {
+	var fields []any
+	visitOptionFields(reflect.ValueOf(opt).Elem(), "", func(key string, value reflect.Value) {
+		fields = append(fields, key, value.Interface())
+	})
+	return fields
+}
+
+// MergeOptions layers the overrides on top of base, in order, e.g. a base configuration file
+// and an environment-specific one, and returns the result. base and the overrides are not
+// modified.
+//
+// Overrides are expected to start from the defaults, as the options returned by NewOptions,
+// NewBuilder and LoadFromFile do: a field of an override is considered set, and replaces the
+// merged value, when it differs from its default. An override can thus set a field to its
+// zero value (e.g. ConsoleOutput: false), but can't reset it back to its default. Syslog
+// settings are merged field by field, while lists and maps are replaced as a whole.
+//
+// A nil base stands for the defaults, and nil overrides are skipped. The result is not
+// validated, which is left to Validate or NewLog.
+//
+// Example Usage:
+//
+//	base, _ := log.LoadFromFile("base.yaml")
+//	prod, _ := log.LoadFromFile("prod.yaml")
+//	logger := log.NewLog(log.MergeOptions(base, prod))
+func MergeOptions(base *Options, overrides ...*Options) *Options {
+	defaults := NewOptions()
+
+	merged := *defaults
+	if base != nil {
+		merged = *base
+	}
+
+	for _, override := range overrides {
+		if override != nil {
+			mergeFields(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(override).Elem(),
+				reflect.ValueOf(defaults).Elem())
 		}
 	}
 
+	// The result doesn't share lists and maps with base and the overrides
+	merged.FieldOrder = slices.Clone(merged.FieldOrder)
+	merged.Fields = maps.Clone(merged.Fields)
+	merged.ExtraWriters = slices.Clone(merged.ExtraWriters)
+
+	return &merged
+}
+
+// mergeFields sets the fields of dst to those of src differing from def, descending into
+// nested structs.
+func mergeFields(dst, src, def reflect.Value) {
+	for i := range src.NumField() {
+		field := src.Field(i)
+		if field.Kind() == reflect.Struct {
+			mergeFields(dst.Field(i), field, def.Field(i))
+			continue
+		}
+
+		// Functions are never deeply equal unless nil, so any function set is merged
+		if !reflect.DeepEqual(field.Interface(), def.Field(i).Interface()) {
+			dst.Field(i).Set(field)
+		}
+	}
+}
+
+// visitOptionFields calls fn with the configuration key and value of every field of v,
+// descending into nested structs with dotted keys (e.g. "syslog.address").
+func visitOptionFields(v reflect.Value, prefix string, fn func(key string, value reflect.Value)) {
+	t := v.Type()
+	for i := range t.NumField() {
+		key := t.Field(i).Tag.Get("mapstructure")
+		if key == "" || key == "-" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if field := v.Field(i); field.Kind() == reflect.Struct {
+			visitOptionFields(field, key, fn)
+		} else {
+			fn(key, field)
+		}
+	}
+}
+
+// isValidRelativeTo reports whether anchor is a supported anchor of relative directories,
+// or empty.
+func isValidRelativeTo(anchor string) bool {
+	return slices.Contains([]string{"", RelativeToCWD, RelativeToExe, RelativeToConfig}, anchor)
+}
+
+// logDirectory returns Directory, resolved against the RelativeTo anchor if it's relative.
+// If the anchor can't be determined, the directory is used as is.
+func (opt *Options) logDirectory() string {
+	dir := opt.Directory
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+
+	switch opt.RelativeTo {
+	case RelativeToExe:
+		exe, err := os.Executable()
+		if err == nil {
+			exe, err = filepath.EvalSymlinks(exe)
+		}
+		if err != nil {
+			fmt.Fprintf(opt.internalErrors(),
+				"Failed to locate the executable: %v. Using log directory '%s' as is.\n", err, dir)
+			return dir
+		}
+		return filepath.Join(filepath.Dir(exe), dir)
+
+	case RelativeToCWD, RelativeToConfig:
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Fprintf(opt.internalErrors(),
+				"Failed to resolve the working directory: %v. Using log directory '%s' as is.\n", err, dir)
+			return dir
+		}
+		return abs
+
+	default:
+		return dir
+	}
+}
+
+// callerSkip returns the number of stack frames zap skips when reporting the caller: the
+// Log method itself, and the extra frames of ExtraCallerSkip.
+func (opt *Options) callerSkip() int {
+	return 1 + opt.ExtraCallerSkip
+}
+
+// sampleTick returns the window of the sampling counters, the default if SampleTick isn't set.
+func (opt *Options) sampleTick() time.Duration {
+	if opt.SampleTick <= 0 {
+		return DefaultSampleTick
+	}
+	return opt.SampleTick
+}
+
+// flattenSeparator returns the separator of flattened keys, the default if FlattenSeparator
+// isn't set.
+func (opt *Options) flattenSeparator() string {
+	if opt.FlattenSeparator == "" {
+		return DefaultFlattenSeparator
+	}
+	return opt.FlattenSeparator
+}
+
+// internalErrors returns the writer receiving the diagnostics of the logger itself.
+func (opt *Options) internalErrors() io.Writer {
+	if opt.InternalErrorWriter == nil {
+		return os.Stderr
+	}
+	return opt.InternalErrorWriter
+}
+
+// consoleFormat returns the format of the console output.
+func (opt *Options) consoleFormat() string {
+	if opt.ConsoleFormat == "" {
+		return opt.Format
+	}
+	return opt.ConsoleFormat
+}
+
+// isValidTimeEncoder reports whether name is a supported time encoder, or empty.
+func isValidTimeEncoder(name string) bool {
+	if name == "" || name == TimeEncoderLayout {
+		return true
+	}
+	_, ok := internal.TimeEncoders[name]
+	return ok
+}
+
+// prettyConsole reports whether the console output is indented JSON.
+func (opt *Options) prettyConsole() bool {
+	return opt.PrettyJSON && opt.consoleFormat() == FormatJSON
+}
+
+// isValidFormat reports whether format is a supported output format.
+func isValidFormat(format string) bool {
+	return format == FormatConsole || format == FormatJSON || format == FormatCSV
+}
+
+// fileFormat returns the format of the log files.
+func (opt *Options) fileFormat() string {
+	if opt.FileFormat == "" {
+		return opt.Format
+	}
+	return opt.FileFormat
+}
+
+// compressAlgorithm returns the algorithm used to compress rotated log files,
+// or CompressNone if they are not compressed.
+func (opt *Options) compressAlgorithm() string {
+	switch {
+	case !opt.Compress || opt.CompressActive: // Active files are already compressed
+		return CompressNone
+	case opt.DisableRotation: // There are no rotated files
+		return CompressNone
+	case opt.CompressAlgorithm == "":
+		return DefaultCompressAlgorithm
+	default:
+		return opt.CompressAlgorithm
+	}
+}
+
+// filenamePatternTokens are the tokens supported in FilenamePattern.
+var filenamePatternTokens = []string{"{name}", "{date}", "{level}", "{pid}", "{host}"}
+
+// filenamePatternToken matches the tokens of a FilenamePattern.
+var filenamePatternToken = regexp.MustCompile(`\{[^{}]*\}`)
+
+// validateFilenamePattern checks that pattern only uses supported tokens and stays
+// within the log directory.
+func validateFilenamePattern(pattern string) error {
+	for _, token := range filenamePatternToken.FindAllString(pattern, -1) {
+		if !slices.Contains(filenamePatternTokens, token) {
+			return fmt.Errorf("invalid filename pattern: %s, unknown token %s, expected: %s",
+				pattern, token, strings.Join(filenamePatternTokens, ", "))
+		}
+	}
+
+	cleaned := filepath.Clean(filepath.FromSlash(pattern))
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("invalid filename pattern: %s, expected: a path relative to the log directory", pattern)
+	}
+
 	return nil
 }
 
+// maxFilenameLengthLimit is the upper bound of MaxFilenameLength, the maximum length of a
+// file name on most filesystems.
+const maxFilenameLengthLimit = 255
+
 // sanitizeFilename cleans and validates a filename by removing unsafe characters,
 // limiting length, and ensuring the filename is valid for filesystem use.
 // It returns the sanitized filename or an empty string if the input results in an invalid filename.
 func sanitizeFilename(filename string) string {
+	return sanitizeFilenameMax(filename, DefaultMaxFilenameLength)
+}
+
+// sanitizeFilenameMax is sanitizeFilename with the filename truncated to maxLength bytes.
+// A maxLength <= 0 means DefaultMaxFilenameLength.
+func sanitizeFilenameMax(filename string, maxLength int) string {
+	if maxLength <= 0 {
+		maxLength = DefaultMaxFilenameLength
+	}
+
 	if filename == "" {
 		return ""
 	}
@@ -345,9 +1446,14 @@ func sanitizeFilename(filename string) string {
 		cleaned = "_" + cleaned[1:]
 	}
 
-	// Limit length to prevent filesystem issues (max 100 characters)
-	if len(cleaned) > 100 {
-		cleaned = cleaned[:100]
+	// Limit length to prevent filesystem issues (max maxLength bytes), cutting at a rune
+	// boundary so multibyte characters are not split
+	if len(cleaned) > maxLength {
+		cut := maxLength
+		for cut > 0 && !utf8.RuneStart(cleaned[cut]) {
+			cut--
+		}
+		cleaned = cleaned[:cut]
 	}
 
 	// Ensure filename is not empty after cleaning
@@ -3,8 +3,11 @@ package log
 import (
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -16,6 +19,7 @@ func Test_NewOptions(t *testing.T) {
 	asrt.Equal(DefaultPrefix, opt.Prefix)
 	asrt.Equal(DefaultDirectory, opt.Directory)
 	asrt.Equal(DefaultFilename, opt.Filename)
+	asrt.Equal(DefaultMaxFilenameLength, opt.MaxFilenameLength)
 	asrt.Equal(DefaultLevel.String(), opt.Level)
 	asrt.Equal(DefaultTimeLayout, opt.TimeLayout)
 	asrt.Equal(DefaultFormat, opt.Format)
@@ -249,6 +253,30 @@ func Test_Options_WithCompress(t *testing.T) {
 	asrt.False(opt.Compress)
 }
 
+func Test_Options_WithCompressAlgorithm(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	asrt.Equal(CompressGzip, NewOptions().CompressAlgorithm)
+
+	opt := NewOptions().WithCompressAlgorithm(CompressZstd)
+	asrt.Equal(CompressZstd, opt.CompressAlgorithm)
+	asrt.NoError(opt.Validate())
+
+	opt = NewOptions().WithCompressAlgorithm("")
+	asrt.Equal(DefaultCompressAlgorithm, opt.CompressAlgorithm)
+
+	opt = NewOptions().WithCompressAlgorithm("brotli")
+	err := opt.Validate()
+	asrt.Error(err)
+	asrt.Contains(err.Error(), "invalid compress algorithm")
+
+	// The algorithm only applies when compression is enabled
+	asrt.Equal(CompressNone, NewOptions().WithCompressAlgorithm(CompressZstd).compressAlgorithm())
+	asrt.Equal(CompressZstd, NewOptions().WithCompress(true).WithCompressAlgorithm(CompressZstd).compressAlgorithm())
+	asrt.Equal(CompressGzip, (&Options{Compress: true}).compressAlgorithm())
+}
+
 // Test the optimized level validation using slices.Contains
 func Test_isValidLevelString(t *testing.T) {
 	t.Parallel()
@@ -342,6 +370,136 @@ func Test_Options_Validate_Enhanced(t *testing.T) {
 	asrt.Contains(err.Error(), "invalid max backups")
 }
 
+// Test that all invalid fields are reported at once
+func Test_Options_Validate_ReportsAllErrors(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	opts := NewOptions()
+	opts.Directory = ""
+	opts.Level = "invalid_level"
+	opts.Format = "xml"
+	opts.MaxSize = 0
+	opts.MaxBackups = -1
+	opts.EnableSampling = true
+	opts.SampleInitial = 0
+	opts.SampleThereafter = 0
+
+	err := opts.Validate()
+	asrt.Error(err)
+
+	for _, want := range []string{
+		"invalid directory",
+		"invalid level",
+		"invalid format",
+		"invalid max size",
+		"invalid max backups",
+		"invalid sample initial",
+		"invalid sample thereafter",
+	} {
+		asrt.Contains(err.Error(), want)
+	}
+
+	// Each problem is a separate joined error
+	joined, ok := err.(interface{ Unwrap() []error })
+	asrt.True(ok)
+	asrt.Len(joined.Unwrap(), 7)
+}
+
+// Test syslog options validation
+func Test_Options_Validate_Syslog(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	// Disabled syslog settings are not validated
+	opts := NewOptions().WithSyslog(SyslogOptions{Facility: "bogus"})
+	asrt.NoError(opts.Validate())
+
+	opts = NewOptions().WithSyslog(SyslogOptions{Enabled: true})
+	asrt.NoError(opts.Validate())
+
+	opts = NewOptions().WithSyslog(SyslogOptions{
+		Enabled: true, Network: "tcp", Address: "syslog.example.com:514", Facility: "local3",
+	})
+	asrt.NoError(opts.Validate())
+
+	opts = NewOptions().WithSyslog(SyslogOptions{Enabled: true, Facility: "bogus"})
+	err := opts.Validate()
+	asrt.Error(err)
+	asrt.Contains(err.Error(), "invalid syslog facility")
+
+	opts = NewOptions().WithSyslog(SyslogOptions{Enabled: true, Network: "udp"})
+	err = opts.Validate()
+	asrt.Error(err)
+	asrt.Contains(err.Error(), "invalid syslog address")
+}
+
+// Test remote output options validation
+func Test_Options_Validate_Remote(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	asrt.NoError(NewOptions().WithRemote("", "localhost:5000").Validate())
+	asrt.NoError(NewOptions().WithRemote("tcp", "localhost:5000").Validate())
+	asrt.NoError(NewOptions().WithRemote("udp", "localhost:5000").Validate())
+
+	err := NewOptions().WithRemote("http", "localhost:5000").Validate()
+	asrt.Error(err)
+	asrt.Contains(err.Error(), "invalid remote protocol")
+}
+
+// Test console and file format validation
+func Test_Options_Validate_ConsoleFileFormat(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	opts := NewOptions().WithFormat(FormatJSON)
+	asrt.Equal(FormatJSON, opts.consoleFormat())
+	asrt.Equal(FormatJSON, opts.fileFormat())
+
+	opts.WithConsoleFormat(FormatConsole)
+	asrt.NoError(opts.Validate())
+	asrt.Equal(FormatConsole, opts.consoleFormat())
+	asrt.Equal(FormatJSON, opts.fileFormat())
+
+	asrt.NoError(NewOptions().WithFormat(FormatCSV).WithConsoleFormat(FormatConsole).Validate())
+	asrt.Equal(FormatCSV, NewOptions().WithFormat(FormatCSV).Format)
+
+	err := NewOptions().WithConsoleFormat("pretty").WithFileFormat("xml").Validate()
+	asrt.Error(err)
+	asrt.Contains(err.Error(), "invalid console format: pretty")
+	asrt.Contains(err.Error(), "invalid file format: xml")
+}
+
+// Test console and file level validation
+func Test_Options_Validate_ConsoleFileLevel(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	asrt.NoError(NewOptions().WithConsoleLevel("warn").WithFileLevel("debug").Validate())
+	asrt.NoError(NewOptions().WithConsoleLevel("").WithFileLevel("").Validate())
+
+	err := NewOptions().WithConsoleLevel("loud").WithFileLevel("verbose").Validate()
+	asrt.Error(err)
+	asrt.Contains(err.Error(), "invalid console level: loud")
+	asrt.Contains(err.Error(), "invalid file level: verbose")
+}
+
+// Test readable dump of options
+func Test_Options_String(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	opts := NewOptions().WithDirectory("/var/log/app").WithLevel("debug").WithMaxSize(50)
+	s := opts.String()
+
+	asrt.Contains(s, `directory="/var/log/app"`)
+	asrt.Contains(s, `level="debug"`)
+	asrt.Contains(s, "max_size=50")
+	asrt.Contains(s, "console_output=true")
+	asrt.True(strings.HasPrefix(s, "prefix="))
+}
+
 // Test options method chaining with validation
 func Test_Options_ChainedValidation(t *testing.T) {
 	t.Parallel()
@@ -479,6 +637,79 @@ func Test_sanitizeFilename(t *testing.T) {
 	asrt.NotContains(result, "\\")
 }
 
+func Test_sanitizeFilename_UnicodeTruncation(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	// 3-byte runes: 34 of them are 102 bytes, so the last one doesn't fit
+	result := sanitizeFilename(strings.Repeat("文", 34))
+	asrt.True(utf8.ValidString(result))
+	asrt.Equal(strings.Repeat("文", 33), result)
+
+	// Mixed widths: the cut falls inside the 4-byte rune at bytes 99-102
+	result = sanitizeFilename(strings.Repeat("a", 99) + "😀" + "b")
+	asrt.True(utf8.ValidString(result))
+	asrt.LessOrEqual(len(result), 100)
+	asrt.Equal(strings.Repeat("a", 99), result)
+
+	// A rune ending exactly at the limit is kept
+	result = sanitizeFilename(strings.Repeat("a", 97) + "名" + "b")
+	asrt.Equal(strings.Repeat("a", 97)+"名", result)
+}
+
+func Test_sanitizeFilenameMax(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	long := strings.Repeat("a", 300)
+
+	asrt.Len(sanitizeFilenameMax(long, 20), 20)
+	asrt.Len(sanitizeFilenameMax(long, 200), 200)
+	asrt.Len(sanitizeFilenameMax(long, 255), 255)
+	asrt.Equal("short", sanitizeFilenameMax("short", 20))
+
+	// 0 means the default limit
+	asrt.Len(sanitizeFilenameMax(long, 0), DefaultMaxFilenameLength)
+
+	// Custom limits also cut at a rune boundary: 7 runes of 3 bytes don't fit in 20
+	result := sanitizeFilenameMax(strings.Repeat("名", 10), 20)
+	asrt.True(utf8.ValidString(result))
+	asrt.Equal(strings.Repeat("名", 6), result)
+
+	// A limit below the first rune leaves nothing
+	asrt.Empty(sanitizeFilenameMax("名字", 2))
+}
+
+func Test_Options_Validate_MaxFilenameLength(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	asrt.NoError(NewOptions().WithMaxFilenameLength(255).Validate())
+	asrt.NoError(NewOptions().WithMaxFilenameLength(10).Validate())
+	asrt.NoError((&Options{Directory: "logs", Level: "info", TimeLayout: DefaultTimeLayout,
+		Format: FormatConsole, MaxSize: 1, MaxBackups: 1}).Validate(), "0 means the default")
+
+	err := NewOptions().WithMaxFilenameLength(256).Validate()
+	asrt.ErrorContains(err, "invalid max filename length: 256")
+
+	opt := NewOptions()
+	opt.MaxFilenameLength = -1
+	asrt.ErrorContains(opt.Validate(), "invalid max filename length: -1")
+
+	// WithMaxFilenameLength resets non-positive values to the default
+	asrt.Equal(DefaultMaxFilenameLength, NewOptions().WithMaxFilenameLength(-5).MaxFilenameLength)
+}
+
+func Test_Options_Validate_MaxFieldLength(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	asrt.Equal(DefaultMaxFieldLength, NewOptions().MaxFieldLength)
+	asrt.NoError(NewOptions().WithMaxFieldLength(0).Validate())
+	asrt.NoError(NewOptions().WithMaxFieldLength(4096).Validate())
+	asrt.ErrorContains(NewOptions().WithMaxFieldLength(-1).Validate(), "invalid max field length: -1")
+}
+
 // Test sanitizeFilename boundary conditions
 func Test_sanitizeFilename_BoundaryConditions(t *testing.T) {
 	t.Parallel()
@@ -1163,3 +1394,77 @@ func TestOptions_ConsoleOutput_Integration(t *testing.T) {
 	err := opts.Validate()
 	asrt.NoError(err)
 }
+
+func TestMergeOptions(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	base := NewBuilder().
+		Level("debug").
+		MaxSize(50).
+		Compress(true).
+		FieldOrder("request_id").
+		Fields(map[string]any{"service": "api"}).
+		WriteRetries(5, time.Second).
+		Options()
+	base.Syslog.Enabled = true
+	base.Syslog.Tag = "base"
+
+	var hooked bool
+	staging := NewOptions().
+		WithLevel("warn").
+		WithMaxSize(80).
+		WithConsoleOutput(false)
+
+	prod := NewOptions().
+		WithLevel("error").
+		WithFieldOrder("trace_id", "span_id").
+		WithFields(map[string]any{"env": "prod"}).
+		WithFatalHook(func() { hooked = true })
+	prod.WriteTimeout = 2 * time.Second
+	prod.Syslog.Tag = "prod"
+
+	merged := MergeOptions(base, staging, nil, prod)
+
+	// string: the last override setting it wins
+	asrt.Equal("error", merged.Level)
+	// int: set by an override but not by the next one
+	asrt.Equal(80, merged.MaxSize)
+	// bool: overrides can set the zero value, and fields left to their default keep base
+	asrt.False(merged.ConsoleOutput)
+	asrt.True(merged.Compress)
+	// lists and maps are replaced as a whole
+	asrt.Equal([]string{"trace_id", "span_id"}, merged.FieldOrder)
+	asrt.Equal(map[string]any{"env": "prod"}, merged.Fields)
+	// durations
+	asrt.Equal(2*time.Second, merged.WriteTimeout)
+	asrt.Equal(time.Second, merged.WriteRetryDelay)
+	asrt.Equal(5, merged.WriteMaxRetries)
+	// nested settings are merged field by field
+	asrt.True(merged.Syslog.Enabled)
+	asrt.Equal("prod", merged.Syslog.Tag)
+	// functions
+	require.NotNil(t, merged.FatalHook)
+	merged.FatalHook()
+	asrt.True(hooked)
+	asrt.Nil(merged.OnWriteError)
+
+	asrt.NoError(merged.Validate())
+
+	// base and overrides are not modified, nor shared with the result
+	asrt.Equal("debug", base.Level)
+	asrt.Equal("base", base.Syslog.Tag)
+	merged.Fields["env"] = "dev"
+	asrt.Equal("prod", prod.Fields["env"])
+}
+
+func TestMergeOptions_NilBase(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	asrt.Equal(NewOptions(), MergeOptions(nil))
+
+	merged := MergeOptions(nil, NewOptions().WithFormat("json"))
+	asrt.Equal("json", merged.Format)
+	asrt.Equal(DefaultLevel.String(), merged.Level)
+}
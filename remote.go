@@ -0,0 +1,197 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	RemoteDialTimeout  = time.Second            // Timeout for connecting to the remote collector
+	RemoteWriteTimeout = time.Second            // Timeout for sending an entry to the remote collector
+	RemoteMinBackoff   = time.Millisecond * 100 // Initial delay before reconnecting after a failure
+	RemoteMaxBackoff   = time.Second * 30       // Maximum delay before reconnecting after a failure
+	RemoteQueueSize    = 1024                   // Entries waiting to be sent, dropped beyond
+	RemoteDropInterval = time.Second            // Minimum delay between two reports of dropped entries
+)
+
+// remoteWriter is a zapcore.WriteSyncer streaming encoded entries to a TCP or UDP
+// collector (e.g. Logstash or Fluentd). Entries are queued and sent by a background
+// goroutine, so logging never waits for the network: entries are dropped while the
+// queue is full, and every send is bounded by RemoteDialTimeout and RemoteWriteTimeout.
+//
+// On failure the connection is dropped and re-established for a later entry, with an
+// exponential backoff between attempts. Entries sent while backing off are dropped.
+// Failures are reported to onError rather than returned, so they don't fail the entry
+// for the other outputs. Dropped entries are counted, and reported to onDrop at most once
+// per RemoteDropInterval.
+type remoteWriter struct {
+	protocol string
+	addr     string
+	onError  func(err error, entry []byte)
+	onDrop   func(err error, entry []byte)
+
+	dropped    atomic.Uint64 // entries dropped since the writer was created
+	unreported atomic.Uint64 // entries dropped since the last report to onDrop
+	lastReport atomic.Int64  // time of the last report to onDrop, in Unix nanoseconds
+
+	queue    chan remoteWrite // entries waiting to be sent, see RemoteQueueSize
+	done     chan struct{}    // closed once the sending goroutine exits
+	mu       sync.RWMutex     // held for writing to close the queue, for reading to send to it
+	closed   bool             // whether the queue is closed
+	closeErr error            // error closing the connection, set before done is closed
+
+	// Owned by the sending goroutine
+	conn      net.Conn
+	backoff   time.Duration // delay before the next reconnect attempt
+	nextRetry time.Time     // no reconnect attempt before this time
+}
+
+// remoteWrite is an encoded entry waiting to be sent, or a request to be notified once the
+// entries queued before it are sent, if flushed is set.
+type remoteWrite struct {
+	data    []byte
+	flushed chan struct{}
+}
+
+// newRemoteWriter returns a writer for the collector at addr, reporting failures to onError
+// and dropped entries to onDrop, and starts its sending goroutine. The connection is
+// established lazily for the first entry. An empty protocol defaults to "tcp".
+func newRemoteWriter(protocol, addr string, onError, onDrop func(err error, entry []byte)) *remoteWriter {
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	w := &remoteWriter{
+		protocol: protocol,
+		addr:     addr,
+		onError:  onError,
+		onDrop:   onDrop,
+		queue:    make(chan remoteWrite, RemoteQueueSize),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p to be sent to the collector, or drops it if the queue is full or
+// the writer is closed. It never blocks.
+func (w *remoteWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return len(p), nil
+	}
+
+	select {
+	case w.queue <- remoteWrite{data: bytes.Clone(p)}:
+	default:
+		w.drop(p, "queue full")
+	}
+	return len(p), nil
+}
+
+// drop counts a dropped entry, and reports the entries dropped since the last report unless
+// the last one is more recent than RemoteDropInterval.
+func (w *remoteWriter) drop(p []byte, reason string) {
+	w.dropped.Add(1)
+	w.unreported.Add(1)
+
+	now := time.Now().UnixNano()
+	last := w.lastReport.Load()
+	if last != 0 && now-last < RemoteDropInterval.Nanoseconds() || !w.lastReport.CompareAndSwap(last, now) {
+		return
+	}
+
+	n := w.unreported.Swap(0)
+	w.onDrop(fmt.Errorf("dropped %d entries for remote %s://%s: %s", n, w.protocol, w.addr, reason), p)
+}
+
+// run sends the queued entries until the queue is closed, then closes the connection.
+func (w *remoteWriter) run() {
+	defer close(w.done)
+
+	for m := range w.queue {
+		if m.flushed != nil {
+			close(m.flushed)
+			continue
+		}
+		w.send(m.data)
+	}
+
+	if w.conn != nil {
+		w.closeErr = w.conn.Close()
+		w.conn = nil
+	}
+}
+
+// send sends p to the collector, connecting first if needed.
+func (w *remoteWriter) send(p []byte) {
+	if w.conn == nil {
+		// Still backing off from the last failure, drop the entry
+		if time.Now().Before(w.nextRetry) {
+			w.drop(p, "reconnecting")
+			return
+		}
+
+		conn, err := net.DialTimeout(w.protocol, w.addr, RemoteDialTimeout)
+		if err != nil {
+			w.fail()
+			w.onError(fmt.Errorf("failed to connect to remote %s://%s: %w", w.protocol, w.addr, err), p)
+			return
+		}
+		w.conn = conn
+	}
+
+	_ = w.conn.SetWriteDeadline(time.Now().Add(RemoteWriteTimeout))
+	if _, err := w.conn.Write(p); err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		w.fail()
+		w.onError(fmt.Errorf("failed to write to remote %s://%s: %w", w.protocol, w.addr, err), p)
+		return
+	}
+
+	// Connected and healthy again
+	w.backoff = 0
+}
+
+// fail schedules the next reconnect attempt with an exponential backoff.
+func (w *remoteWriter) fail() {
+	w.backoff = min(max(w.backoff*2, RemoteMinBackoff), RemoteMaxBackoff)
+	w.nextRetry = time.Now().Add(w.backoff)
+}
+
+// Sync waits until the entries queued so far are sent, or dropped.
+func (w *remoteWriter) Sync() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return nil
+	}
+
+	flushed := make(chan struct{})
+	w.queue <- remoteWrite{flushed: flushed}
+	<-flushed
+	return nil
+}
+
+// Close sends the queued entries, stops the sending goroutine and closes the connection,
+// if any. The entries written afterwards are dropped.
+func (w *remoteWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	return w.closeErr
+}
@@ -0,0 +1,253 @@
+package log
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteOutput_TCP(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_remote_tcp"
+	defer os.RemoveAll(testDir)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	lines := make(chan string, 16)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithFormat("json").
+		WithConsoleOutput(false).
+		WithRemote("tcp", listener.Addr().String()))
+	defer logger.Close()
+
+	logger.Infow("first remote message", "key", "value")
+	logger.Error("second remote message")
+
+	for _, want := range []string{"first remote message", "second remote message"} {
+		select {
+		case line := <-lines:
+			// Lines are sent without the prefix, so they are valid JSON
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			asrt.Equal(want, entry["msg"])
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for %q", want)
+		}
+	}
+
+	// Entries are still written locally
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	asrt.Contains(string(content), "first remote message")
+}
+
+func TestRemoteOutput_Unreachable(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_remote_unreachable"
+	defer os.RemoveAll(testDir)
+
+	// Grab a free port and close it, so nothing is listening there
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	var failed, dropped atomic.Int32
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(false).
+		WithRemote("tcp", addr).
+		WithOnWriteError(func(err error, _ []byte) {
+			if strings.Contains(err.Error(), "dropped") {
+				dropped.Add(1)
+			} else {
+				failed.Add(1)
+			}
+		}))
+	defer logger.Close()
+
+	start := time.Now()
+	for range 100 {
+		logger.Info("local fallback message")
+	}
+	asrt.Less(time.Since(start), RemoteDialTimeout, "Logging must not block on an unreachable collector")
+	require.NoError(t, logger.Sync())
+
+	// The failure is surfaced to the callback, and the dropped entries are counted but only
+	// reported once per RemoteDropInterval
+	asrt.Equal(int32(1), failed.Load())
+	asrt.Equal(int32(1), dropped.Load())
+	stats := logger.Stats()
+	asrt.Equal(uint64(1), stats.WriteErrors)
+	asrt.Equal(uint64(99), stats.DroppedRemote)
+
+	// Falls back to local output
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	asrt.Contains(string(content), "local fallback message")
+}
+
+func TestRemoteWriter_Backoff(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	var reported, dropped []string
+	w := newRemoteWriter("", addr, func(err error, entry []byte) {
+		asrt.Contains(err.Error(), "failed to connect to remote tcp://"+addr)
+		reported = append(reported, string(entry))
+	}, func(err error, entry []byte) {
+		asrt.Equal("dropped 1 entries for remote tcp://"+addr+": reconnecting", err.Error())
+		dropped = append(dropped, string(entry))
+	})
+	asrt.Equal("tcp", w.protocol)
+
+	// The failed connection is reported once, then entries are dropped while backing off
+	n, err := w.Write([]byte("lost\n"))
+	asrt.NoError(err)
+	asrt.Equal(len("lost\n"), n)
+	require.NoError(t, w.Sync())
+	asrt.Equal([]string{"lost\n"}, reported)
+	asrt.Equal(RemoteMinBackoff, w.backoff)
+
+	_, err = w.Write([]byte("dropped\n"))
+	asrt.NoError(err)
+	require.NoError(t, w.Sync())
+	asrt.Len(reported, 1)
+	asrt.Equal([]string{"dropped\n"}, dropped)
+	asrt.Equal(uint64(1), w.dropped.Load())
+
+	// Once the backoff elapsed and the collector is back, writes succeed again
+	listener, err = net.Listen("tcp", addr)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	time.Sleep(RemoteMinBackoff + 10*time.Millisecond)
+	_, err = w.Write([]byte("delivered\n"))
+	asrt.NoError(err)
+	require.NoError(t, w.Sync())
+	asrt.Zero(w.backoff)
+	asrt.NoError(w.Close())
+
+	// Entries written after Close are dropped
+	_, err = w.Write([]byte("closed\n"))
+	asrt.NoError(err)
+	asrt.NoError(w.Sync())
+	asrt.Len(reported, 1)
+	asrt.Len(dropped, 1)
+}
+
+func TestRemoteWriter_SlowCollector(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	// The collector accepts the connection but never reads, so sends end up blocking
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	var (
+		mu      sync.Mutex
+		reports []string
+	)
+	w := newRemoteWriter("tcp", listener.Addr().String(), func(error, []byte) {}, func(err error, _ []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, err.Error())
+	})
+
+	// Writes are queued, or dropped once the queue is full, without waiting for the network
+	entry := []byte(strings.Repeat("x", 1023) + "\n")
+	start := time.Now()
+	for range 20 * RemoteQueueSize {
+		n, err := w.Write(entry)
+		asrt.NoError(err)
+		asrt.Equal(len(entry), n)
+	}
+	asrt.Less(time.Since(start), RemoteWriteTimeout, "Logging must not block on a slow collector")
+	asrt.LessOrEqual(len(w.queue), RemoteQueueSize)
+
+	// The dropped entries are counted, and reported once per RemoteDropInterval
+	asrt.Positive(w.dropped.Load())
+	mu.Lock()
+	asrt.Equal([]string{"dropped 1 entries for remote tcp://" + listener.Addr().String() + ": queue full"}, reports)
+	mu.Unlock()
+
+	// Unblock the pending send, so Close doesn't wait for the write deadline
+	select {
+	case conn := <-accepted:
+		require.NoError(t, conn.Close())
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the connection")
+	}
+	require.NoError(t, listener.Close())
+	asrt.NoError(w.Close())
+}
+
+func TestRemoteOutput_UDP(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_remote_udp"
+	defer os.RemoveAll(testDir)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(false).
+		WithRemote("udp", conn.LocalAddr().String()))
+	defer logger.Close()
+
+	logger.Warn("udp remote message")
+
+	buf := make([]byte, 64*1024)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	asrt.Contains(string(buf[:n]), "udp remote message")
+}
@@ -0,0 +1,81 @@
+package log
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DefaultRingBufferSize is the number of lines kept by a RingBuffer created with a size <= 0.
+const DefaultRingBufferSize = 1000
+
+// RingBuffer is an io.Writer keeping the last lines written to it in memory, e.g. added to
+// ExtraWriters to expose the recent entries of a logger with Handler. It is safe for
+// concurrent use.
+type RingBuffer struct {
+	mu    sync.Mutex
+	lines []string // circular buffer, oldest line at next once full
+	next  int      // index of the next line to write
+	full  bool     // whether lines has wrapped around
+}
+
+// NewRingBuffer creates a RingBuffer keeping the last size lines, or DefaultRingBufferSize
+// if size <= 0.
+//
+// Example Usage:
+//
+//	recent := log.NewRingBuffer(500)
+//	logger := log.NewBuilder().ExtraWriters(recent).Build()
+//	http.Handle("/debug/logs", recent.Handler())
+func NewRingBuffer(size int) *RingBuffer {
+	if size <= 0 {
+		size = DefaultRingBufferSize
+	}
+	return &RingBuffer{lines: make([]string, size)}
+}
+
+// Write stores every line of p, evicting the oldest lines once the buffer is full.
+func (r *RingBuffer) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, line := range bytes.Split(bytes.TrimSuffix(p, []byte("\n")), []byte("\n")) {
+		r.lines[r.next] = string(line)
+		r.next = (r.next + 1) % len(r.lines)
+		if r.next == 0 {
+			r.full = true
+		}
+	}
+	return len(p), nil
+}
+
+// Lines returns the lines kept by the buffer, oldest first.
+func (r *RingBuffer) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+	return append(append([]string(nil), r.lines[r.next:]...), r.lines[:r.next]...)
+}
+
+// Handler returns an http.Handler serving the lines kept by the buffer as plain text,
+// oldest first, e.g. at /debug/logs. Since logs may contain sensitive data, it should
+// only be reachable by operators.
+func (r *RingBuffer) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		lines := r.Lines()
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if len(lines) > 0 {
+			_, _ = w.Write([]byte(strings.Join(lines, "\n") + "\n"))
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBuffer_Handler(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_ring_buffer"
+	defer os.RemoveAll(testDir)
+
+	recent := NewRingBuffer(5)
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		ExtraWriters(recent).
+		Isolated(true).
+		Build()
+	defer logger.Sync()
+
+	for i := range 12 {
+		logger.Infow("request handled", "i", i)
+	}
+
+	server := httptest.NewServer(recent.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/debug/logs")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	asrt.Equal(http.StatusOK, resp.StatusCode)
+	asrt.Equal("text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	// Only the last 5 entries, oldest first
+	lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+	require.Len(t, lines, 5)
+	for j, line := range lines {
+		asrt.Contains(line, fmt.Sprintf(`"i":%d}`, 7+j))
+	}
+}
+
+func TestRingBuffer(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	ring := NewRingBuffer(3)
+	asrt.Empty(ring.Lines())
+
+	// Every line of a write is kept, without the trailing newline
+	n, err := ring.Write([]byte("a\nb\n"))
+	asrt.NoError(err)
+	asrt.Equal(4, n)
+	asrt.Equal([]string{"a", "b"}, ring.Lines())
+
+	_, _ = ring.Write(nil)
+	_, _ = ring.Write([]byte("c\n"))
+	asrt.Equal([]string{"a", "b", "c"}, ring.Lines())
+
+	_, _ = ring.Write([]byte("d\ne"))
+	asrt.Equal([]string{"c", "d", "e"}, ring.Lines())
+
+	// Empty buffers serve an empty body
+	rec := httptest.NewRecorder()
+	NewRingBuffer(0).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs", nil))
+	asrt.Empty(rec.Body.String())
+	asrt.Len(NewRingBuffer(0).lines, DefaultRingBufferSize)
+}
+
+func TestRingBuffer_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	ring := NewRingBuffer(100)
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				_, _ = fmt.Fprintf(ring, "%d-%d\n", i, j)
+				_ = ring.Lines()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, ring.Lines(), 100)
+}
@@ -0,0 +1,146 @@
+package log
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	sampleLevels           = int(zapcore.FatalLevel-zapcore.DebugLevel) + 1
+	sampleCountersPerLevel = 4096
+)
+
+// keySampler is a zapcore.Core wrapper sampling entries like zapcore.NewSamplerWithOptions,
+// grouping them by their message, or by the key returned by Options.SampleKeyFunc if set.
+// Since the key may depend on the fields, the decision is made in Write.
+//
+// The settings are swapped as a whole by Log.setSampling, so sampling can be changed or
+// disabled while the logger runs, including for the cores created by With.
+type keySampler struct {
+	zapcore.Core
+	settings *atomic.Pointer[sampleSettings] // shared with the cores created by With, nil disables sampling
+	hook     func(entry zapcore.Entry, dec zapcore.SamplingDecision)
+}
+
+// sampleSettings logs the first entries of every key and level in each tick, then every
+// thereafter-th entry.
+type sampleSettings struct {
+	keyFunc    func(entry zapcore.Entry, fields []zapcore.Field) string
+	counters   *[sampleLevels][sampleCountersPerLevel]sampleCounter
+	tick       time.Duration
+	first      uint64
+	thereafter uint64
+}
+
+// newSampleSettings returns the sampling settings, with fresh counters, or nil if sampling
+// is disabled. A nil keyFunc groups the entries by message.
+func newSampleSettings(
+	enable bool,
+	keyFunc func(entry zapcore.Entry, fields []zapcore.Field) string,
+	tick time.Duration, first, thereafter int,
+) *sampleSettings {
+	if !enable {
+		return nil
+	}
+	if keyFunc == nil {
+		keyFunc = messageKey
+	}
+	return &sampleSettings{
+		keyFunc:    keyFunc,
+		counters:   &[sampleLevels][sampleCountersPerLevel]sampleCounter{},
+		tick:       tick,
+		first:      uint64(max(first, 0)),
+		thereafter: uint64(max(thereafter, 0)),
+	}
+}
+
+// messageKey groups the entries by message, like zap does.
+func messageKey(entry zapcore.Entry, _ []zapcore.Field) string { return entry.Message }
+
+// newKeySampler wraps core with a sampler using the settings stored in settings, calling hook
+// with every decision.
+func newKeySampler(
+	core zapcore.Core,
+	settings *atomic.Pointer[sampleSettings],
+	hook func(entry zapcore.Entry, dec zapcore.SamplingDecision),
+) zapcore.Core {
+	return &keySampler{Core: core, settings: settings, hook: hook}
+}
+
+// With adds structured context to the wrapped core, sharing the settings.
+func (s *keySampler) With(fields []zapcore.Field) zapcore.Core {
+	clone := *s
+	clone.Core = s.Core.With(fields)
+	return &clone
+}
+
+// Check registers this core if the wrapped core accepts the entry, so it's sampled in Write.
+// Without sampling, the entry goes straight to the wrapped core.
+func (s *keySampler) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if s.settings.Load() == nil {
+		return s.Core.Check(entry, ce)
+	}
+	if s.Core.Check(entry, nil) == nil {
+		return ce
+	}
+	return ce.AddCore(entry, s)
+}
+
+// Write writes the entry unless it's dropped by sampling.
+func (s *keySampler) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	// Levels outside the known range are never sampled, like zap does
+	settings := s.settings.Load()
+	if settings == nil || entry.Level < zapcore.DebugLevel || entry.Level > zapcore.FatalLevel {
+		return s.Core.Write(entry, fields)
+	}
+
+	key := settings.keyFunc(entry, fields)
+	counter := &settings.counters[entry.Level-zapcore.DebugLevel][fnv32a(key)%sampleCountersPerLevel]
+	n := counter.incCheckReset(entry.Time, settings.tick)
+	if n > settings.first && (settings.thereafter == 0 || (n-settings.first)%settings.thereafter != 0) {
+		s.hook(entry, zapcore.LogDropped)
+		return nil
+	}
+
+	s.hook(entry, zapcore.LogSampled)
+	return s.Core.Write(entry, fields)
+}
+
+// sampleCounter counts the entries of a key and level in the current tick.
+type sampleCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// incCheckReset increments the counter, resetting it first if the tick starting at its
+// last reset has elapsed at t, and returns the new count.
+func (c *sampleCounter) incCheckReset(t time.Time, tick time.Duration) uint64 {
+	now := t.UnixNano()
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.count.Add(1)
+	}
+
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick.Nanoseconds()) {
+		// Another goroutine reset the counter concurrently, count this entry on top
+		return c.count.Add(1)
+	}
+	return 1
+}
+
+// fnv32a returns the FNV-1a hash of s, without converting it to a []byte.
+func fnv32a(s string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	hash := uint32(offset32)
+	for i := 0; i < len(s); i++ {
+		hash ^= uint32(s[i])
+		hash *= prime32
+	}
+	return hash
+}
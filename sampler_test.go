@@ -0,0 +1,176 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// endpointKey groups entries by the value of their "endpoint" field.
+func endpointKey(_ zapcore.Entry, fields []zapcore.Field) string {
+	for _, field := range fields {
+		if field.Key == "endpoint" {
+			return field.String
+		}
+	}
+	return ""
+}
+
+func TestSampleKeyFunc(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_sample_key_func"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		Sampling(true, 2, 1000).
+		SampleKeyFunc(endpointKey).
+		Build()
+
+	// Messages differ on every call, but entries are grouped by endpoint
+	for i := range 10 {
+		for _, endpoint := range []string{"/users", "/orders", "/health"} {
+			logger.Infow(fmt.Sprintf("request %d", i), "endpoint", endpoint)
+		}
+	}
+	require.NoError(t, logger.Sync())
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 6)
+	counts := map[any]int{}
+	for _, entry := range entries {
+		counts[entry["endpoint"]]++
+	}
+	asrt.Equal(map[any]int{"/users": 2, "/orders": 2, "/health": 2}, counts)
+	asrt.Equal(uint64(24), logger.Stats().DroppedBySampling)
+}
+
+func TestSampleTick(t *testing.T) {
+	t.Parallel()
+
+	for _, keyed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("key func %t", keyed), func(t *testing.T) {
+			t.Parallel()
+			asrt := assert.New(t)
+
+			testDir := fmt.Sprintf("./logs/test_logs_sample_tick_%t", keyed)
+			defer os.RemoveAll(testDir)
+
+			builder := NewBuilder().
+				Directory(testDir).
+				Prefix("").
+				Format(FormatJSON).
+				ConsoleOutput(false).
+				Sampling(true, 2, 1000).
+				SampleTick(200 * time.Millisecond).
+				Isolated(true)
+			if keyed {
+				builder.SampleKeyFunc(endpointKey)
+			}
+			logger := builder.Build()
+
+			burst := func() {
+				for range 10 {
+					logger.Infow("burst", "endpoint", "/users")
+				}
+			}
+
+			burst()
+			asrt.Equal(uint64(8), logger.Stats().DroppedBySampling)
+
+			// Logs pass again once the window elapsed
+			time.Sleep(250 * time.Millisecond)
+			burst()
+			require.NoError(t, logger.Sync())
+
+			asrt.Len(readJSONEntries(t, logger, testDir), 4)
+			asrt.Equal(uint64(16), logger.Stats().DroppedBySampling)
+		})
+	}
+}
+
+func TestSampleTick_Validation(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	opts := NewOptions()
+	asrt.Equal(DefaultSampleTick, opts.SampleTick)
+	asrt.Equal(DefaultSampleTick, opts.WithSampleTick(-time.Second).SampleTick)
+
+	// Zero uses the default, negative ticks are rejected when sampling is enabled
+	opts = NewOptions().WithSampling(true, 10, 10)
+	opts.SampleTick = 0
+	asrt.NoError(opts.Validate())
+	asrt.Equal(time.Second, opts.sampleTick())
+
+	opts.SampleTick = -time.Second
+	err := opts.Validate()
+	require.Error(t, err)
+	asrt.Contains(err.Error(), "invalid sample tick: -1s, expected: >= 0, 0 for the default")
+
+	fixed, err := ValidateOptions(opts)
+	require.Error(t, err)
+	asrt.Equal(DefaultSampleTick, fixed.SampleTick)
+
+	opts.EnableSampling = false
+	asrt.NoError(opts.Validate())
+}
+
+func Test_newKeySampler(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	var decisions []zapcore.SamplingDecision
+	core, recorded := observer.New(zapcore.InfoLevel)
+	var settings atomic.Pointer[sampleSettings]
+	settings.Store(newSampleSettings(true, endpointKey, time.Minute, 1, 3))
+	sampler := newKeySampler(core, &settings,
+		func(_ zapcore.Entry, dec zapcore.SamplingDecision) { decisions = append(decisions, dec) })
+	sampler = sampler.With([]zapcore.Field{String("service", "api")}) // Counters are shared
+
+	now := time.Now()
+	write := func(level zapcore.Level, endpoint string, at time.Time) {
+		entry := zapcore.Entry{Level: level, Time: at, Message: "msg"}
+		if ce := sampler.Check(entry, nil); ce != nil {
+			ce.Write(String("endpoint", endpoint))
+		}
+	}
+
+	// The first entry, then every third one
+	for range 7 {
+		write(zapcore.InfoLevel, "/a", now)
+	}
+	asrt.Len(recorded.TakeAll(), 3)
+	asrt.Equal([]zapcore.SamplingDecision{
+		zapcore.LogSampled, zapcore.LogDropped, zapcore.LogDropped, zapcore.LogSampled,
+		zapcore.LogDropped, zapcore.LogDropped, zapcore.LogSampled,
+	}, decisions)
+
+	// Levels and keys are counted separately, and counters are reset after the tick
+	write(zapcore.WarnLevel, "/a", now)
+	write(zapcore.InfoLevel, "/b", now)
+	write(zapcore.InfoLevel, "/a", now.Add(time.Minute))
+	write(zapcore.DebugLevel, "/a", now) // Disabled by the wrapped core
+	entries := recorded.TakeAll()
+	require.Len(t, entries, 3)
+	asrt.Equal(map[string]any{"service": "api", "endpoint": "/a"}, entries[0].ContextMap())
+
+	// Disabling sampling applies to the cores created by With as well
+	settings.Store(nil)
+	for range 3 {
+		write(zapcore.InfoLevel, "/a", now)
+	}
+	asrt.Len(recorded.TakeAll(), 3)
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/kydenul/log/internal"
+)
+
+// HandleSIGHUP rotates the log files whenever the process receives SIGHUP, the standard
+// way for daemons managed by logrotate and similar tools to reopen their log files.
+// While the handler is active, SIGHUP no longer terminates the process.
+//
+// The returned stop function removes the handler; it is safe to call multiple times.
+// On Windows, which has no SIGHUP, this is a no-op.
+//
+// Example Usage:
+//
+//	logger := log.NewLog(opts)
+//	stop := logger.HandleSIGHUP()
+//	defer stop()
+func (l *Log) HandleSIGHUP() (stop func()) {
+	// Register before subscribing, so a SIGHUP never reaches the auto sync handler alone
+	remove := internal.AddSIGHUPHandler()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-hup:
+				if err := l.Rotate(); err != nil {
+					l.Errorw("Failed to rotate log files on SIGHUP", "error", err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(hup)
+			remove()
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+//go:build !windows
+
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_HandleSIGHUP(t *testing.T) {
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_sighup"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithFilename("daemon").
+		WithConsoleOutput(false))
+	defer logger.Close()
+
+	logger.Info("before SIGHUP")
+
+	stop := logger.HandleSIGHUP()
+	defer stop()
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	// The files are rotated, and the process keeps running
+	var backups []string
+	require.Eventually(t, func() bool {
+		backups, _ = filepath.Glob(filepath.Join(testDir, "daemon-*T*.log"))
+		return len(backups) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+
+	content, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	asrt.Contains(string(content), "before SIGHUP")
+
+	// Stopping is idempotent
+	stop()
+	stop()
+}
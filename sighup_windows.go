@@ -0,0 +1,7 @@
+//go:build windows
+
+package log
+
+// HandleSIGHUP is a no-op on Windows, which has no SIGHUP.
+// The returned stop function does nothing.
+func (l *Log) HandleSIGHUP() (stop func()) { return func() {} }
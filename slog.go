@@ -0,0 +1,175 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogHandler is a slog.Handler writing records through the core of a Log, so they go
+// through the same prefix, file rotation, sampling and outputs as the Log methods.
+type slogHandler struct {
+	core      zapcore.Core
+	addCaller bool
+	holdGroup string // group to open before the next attributes, so empty groups are omitted
+}
+
+// SlogHandler returns a slog.Handler writing through this logger, so code written against
+// log/slog emits to the same files and outputs. slog levels are mapped to the closest zap
+// level at or below them, attributes become fields, and groups nest their fields under the
+// group name.
+//
+// Example Usage:
+//
+//	logger := log.NewLog(opts)
+//	slog.SetDefault(slog.New(logger.SlogHandler()))
+//	slog.Info("user signed in", "user_id", 42)
+func (l *Log) SlogHandler() slog.Handler {
+	return &slogHandler{
+		core:      l.log.Core(),
+		addCaller: !l.opts.DisableCaller,
+	}
+}
+
+// NewSlogLogger creates a new logger with the given options, like NewLog, and returns a
+// *slog.Logger writing through it.
+func NewSlogLogger(opts *Options) *slog.Logger {
+	return slog.New(NewLog(opts).SlogHandler())
+}
+
+// Enabled reports whether the logger emits entries at the given level.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(slogToZapLevel(level))
+}
+
+// Handle writes the record, with its attributes as fields.
+func (h *slogHandler) Handle(_ context.Context, record slog.Record) error {
+	entry := zapcore.Entry{
+		Time:    record.Time,
+		Level:   slogToZapLevel(record.Level),
+		Message: record.Message,
+	}
+	if h.addCaller && record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		entry.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+		entry.Caller.Function = frame.Function
+	}
+
+	ce := h.core.Check(entry, nil)
+	if ce == nil {
+		return nil
+	}
+
+	fields := make([]zapcore.Field, 0, record.NumAttrs()+1)
+	if h.holdGroup != "" && record.NumAttrs() > 0 {
+		fields = append(fields, zap.Namespace(h.holdGroup))
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = append(fields, slogAttrToField(attr))
+		return true
+	})
+
+	ce.Write(fields...)
+	return nil
+}
+
+// WithAttrs returns a handler adding the attributes to every record.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	fields := make([]zapcore.Field, 0, len(attrs)+1)
+	if h.holdGroup != "" {
+		fields = append(fields, zap.Namespace(h.holdGroup))
+	}
+	for _, attr := range attrs {
+		fields = append(fields, slogAttrToField(attr))
+	}
+
+	clone := *h
+	clone.core = h.core.With(fields)
+	clone.holdGroup = ""
+	return &clone
+}
+
+// WithGroup returns a handler nesting the attributes added afterwards under name.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	if h.holdGroup != "" {
+		// The held group is no longer empty, since it contains this one
+		clone.core = h.core.With([]zapcore.Field{zap.Namespace(h.holdGroup)})
+	}
+	clone.holdGroup = name
+	return &clone
+}
+
+// slogToZapLevel maps a slog level to the closest zap level at or below it.
+func slogToZapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+// slogAttrToField converts a slog attribute to a field.
+func slogAttrToField(attr slog.Attr) zapcore.Field {
+	// Empty attributes are ignored, as required by slog.Handler
+	if attr.Equal(slog.Attr{}) {
+		return zap.Skip()
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindBool:
+		return zap.Bool(attr.Key, attr.Value.Bool())
+	case slog.KindDuration:
+		return zap.Duration(attr.Key, attr.Value.Duration())
+	case slog.KindFloat64:
+		return zap.Float64(attr.Key, attr.Value.Float64())
+	case slog.KindInt64:
+		return zap.Int64(attr.Key, attr.Value.Int64())
+	case slog.KindString:
+		return zap.String(attr.Key, attr.Value.String())
+	case slog.KindTime:
+		return zap.Time(attr.Key, attr.Value.Time())
+	case slog.KindUint64:
+		return zap.Uint64(attr.Key, attr.Value.Uint64())
+	case slog.KindGroup:
+		group := slogGroup(attr.Value.Group())
+		if len(group) == 0 {
+			return zap.Skip()
+		}
+		// Groups without a key are inlined into the parent
+		if attr.Key == "" {
+			return zap.Inline(group)
+		}
+		return zap.Object(attr.Key, group)
+	case slog.KindLogValuer:
+		return slogAttrToField(slog.Attr{Key: attr.Key, Value: attr.Value.Resolve()})
+	default:
+		return zap.Any(attr.Key, attr.Value.Any())
+	}
+}
+
+// slogGroup marshals the attributes of a slog group as an object.
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, attr := range g {
+		slogAttrToField(attr).AddTo(enc)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// readJSONEntries reads the JSON entries of the main log file of logger in dir.
+func readJSONEntries(t *testing.T, logger *Log, dir string) []map[string]any {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(dir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), "line: %s", line)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestSlogHandler(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_slog"
+	defer os.RemoveAll(testDir)
+
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("").
+		WithFormat(FormatJSON).
+		WithConsoleOutput(false))
+	sl := slog.New(logger.SlogHandler())
+
+	sl.Debug("filtered by level")
+	sl.Info("user signed in",
+		"user_id", 42,
+		"elapsed", 1500*time.Millisecond,
+		slog.Group("request", slog.String("method", "GET"), slog.Int("status", 200)),
+	)
+	sl.With("service", "api").WithGroup("job").Warn("job slow", "id", "j-1", "retries", uint64(3))
+	sl.WithGroup("empty").Error("no attributes")
+	require.NoError(t, logger.Sync())
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 3)
+
+	// Attributes become fields, groups nest them
+	asrt.Equal("info", entries[0]["level"])
+	asrt.Equal("user signed in", entries[0]["msg"])
+	asrt.InDelta(42, entries[0]["user_id"], 0)
+	asrt.InDelta(1.5, entries[0]["elapsed"], 0)
+	asrt.Equal(map[string]any{"method": "GET", "status": float64(200)}, entries[0]["request"])
+	asrt.Contains(entries[0]["caller"], "slog_test.go")
+
+	// WithAttrs and WithGroup are kept by the derived handlers
+	asrt.Equal("warn", entries[1]["level"])
+	asrt.Equal("api", entries[1]["service"])
+	asrt.Equal(map[string]any{"id": "j-1", "retries": float64(3)}, entries[1]["job"])
+
+	// Empty groups are omitted
+	asrt.Equal("error", entries[2]["level"])
+	asrt.NotContains(entries[2], "empty")
+}
+
+func TestSlogHandler_Enabled(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	logger := NewLog(NewOptions().
+		WithDirectory("./logs/test_logs_slog_enabled").
+		WithLevel("warn").
+		WithConsoleOutput(false))
+	defer os.RemoveAll("./logs/test_logs_slog_enabled")
+	handler := logger.SlogHandler()
+
+	asrt.False(handler.Enabled(context.Background(), slog.LevelInfo))
+	asrt.True(handler.Enabled(context.Background(), slog.LevelWarn))
+	asrt.True(handler.Enabled(context.Background(), slog.LevelError+4))
+}
+
+func Test_slogToZapLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		level slog.Level
+		want  zapcore.Level
+	}{
+		{slog.LevelDebug - 4, zapcore.DebugLevel},
+		{slog.LevelDebug, zapcore.DebugLevel},
+		{slog.LevelInfo - 1, zapcore.DebugLevel},
+		{slog.LevelInfo, zapcore.InfoLevel},
+		{slog.LevelInfo + 2, zapcore.InfoLevel},
+		{slog.LevelWarn, zapcore.WarnLevel},
+		{slog.LevelError, zapcore.ErrorLevel},
+		{slog.LevelError + 4, zapcore.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, slogToZapLevel(tt.level), "slog level %s", tt.level)
+	}
+}
+
+func TestNewSlogLogger(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_new_slog"
+	defer os.RemoveAll(testDir)
+
+	sl := NewSlogLogger(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("").
+		WithFormat(FormatJSON).
+		WithConsoleOutput(false))
+	sl.Info("from slog", "key", "value")
+
+	files, err := filepath.Glob(filepath.Join(testDir, "*.log"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	asrt.Contains(string(content), `"msg":"from slog","key":"value"`)
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Stats are counters of the entries handled by a logger since its creation, e.g. to
+// export log health metrics.
+type Stats struct {
+	Written           uint64 // Entries written to the main log file
+	DroppedBySampling uint64 // Entries dropped by sampling, see Options.EnableSampling
+	DroppedByQueue    uint64 // Entries dropped because the async queue was full, see Options.AsyncQueueSize
+	WriteErrors       uint64 // Entries that failed to be written to a log file or another output
+	DroppedRemote     uint64 // Entries not sent to the remote collector, see Options.RemoteAddr
+}
+
+// logStats holds the counters of Stats, updated atomically.
+type logStats struct {
+	written           atomic.Uint64
+	droppedBySampling atomic.Uint64
+	droppedByQueue    atomic.Uint64
+	writeErrors       atomic.Uint64
+}
+
+// Stats returns the counters of the entries handled by the logger. The counters of a logger
+// created by Tee are the sums of the counters of its loggers.
+//
+// Example Usage:
+//
+//	stats := logger.Stats()
+//	droppedGauge.Set(float64(stats.DroppedBySampling + stats.DroppedByQueue))
+func (l *Log) Stats() Stats {
+	if l.parent != nil {
+		return l.parent.Stats()
+	}
+
+	stats := Stats{
+		Written:           l.stats.written.Load(),
+		DroppedBySampling: l.stats.droppedBySampling.Load(),
+		DroppedByQueue:    l.stats.droppedByQueue.Load(),
+		WriteErrors:       l.stats.writeErrors.Load(),
+	}
+	if l.remote != nil {
+		stats.DroppedRemote = l.remote.dropped.Load()
+	}
+
+	for _, tee := range l.tees {
+		s := tee.Stats()
+		stats.Written += s.Written
+		stats.DroppedBySampling += s.DroppedBySampling
+		stats.DroppedByQueue += s.DroppedByQueue
+		stats.WriteErrors += s.WriteErrors
+		stats.DroppedRemote += s.DroppedRemote
+	}
+	return stats
+}
+
+// samplingHook counts the entries dropped by sampling, and calls Options.SamplingHook.
+func (l *Log) samplingHook(entry zapcore.Entry, dec zapcore.SamplingDecision) {
+	if dec&zapcore.LogDropped != 0 {
+		l.stats.droppedBySampling.Add(1)
+	}
+	if l.opts.SamplingHook != nil {
+		l.opts.SamplingHook(entry, dec)
+	}
+}
@@ -0,0 +1,100 @@
+package log
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestStats_Sampling(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_stats_sampling"
+	defer os.RemoveAll(testDir)
+
+	var hookDropped atomic.Int64
+	logger := NewBuilder().
+		Directory(testDir).
+		Sampling(true, 10, 100).
+		SamplingHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+			if dec&zapcore.LogDropped != 0 {
+				hookDropped.Add(1)
+			}
+		}).
+		ConsoleOutput(false).
+		Build()
+
+	for range 1000 {
+		logger.Info("flood")
+	}
+	require.NoError(t, logger.Sync())
+
+	stats := logger.Stats()
+	asrt.Positive(stats.DroppedBySampling)
+	asrt.Equal(uint64(1000), stats.Written+stats.DroppedBySampling)
+	asrt.Zero(stats.DroppedByQueue)
+	asrt.Zero(stats.WriteErrors)
+
+	// The SamplingHook of the options is still called
+	asrt.Equal(int64(stats.DroppedBySampling), hookDropped.Load())
+}
+
+func TestStats_WriteErrorsAndQueue(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_stats_errors"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		WriteRetries(0, 0).
+		OnWriteError(func(error, []byte) {}).
+		ConsoleOutput(false).
+		Build()
+
+	logger.Info("written")
+	asrt.Equal(Stats{Written: 1}, logger.Stats())
+
+	// Replace the main log file with one failing every write
+	logger.mu.Lock()
+	logger.file = failingFile{}
+	logger.mu.Unlock()
+
+	logger.Info("lost")
+	logger.Warn("lost too")
+	asrt.Equal(Stats{Written: 1, WriteErrors: 2}, logger.Stats())
+
+	// Entries dropped by the async queue are counted as well
+	queued := NewBuilder().
+		Directory(testDir).
+		AsyncQueueSize(1).
+		ConsoleOutput(false).
+		Build()
+	queued.mu.Lock()
+	queued.file = &slowFile{delay: 50 * time.Millisecond}
+	queued.currDate = time.Now().Format(time.DateOnly)
+	queued.mu.Unlock()
+
+	for range 10 {
+		queued.Info("queued")
+	}
+	require.NoError(t, queued.Sync())
+	asrt.Positive(queued.Stats().DroppedByQueue)
+	asrt.Equal(queued.DroppedEntries(), queued.Stats().DroppedByQueue)
+	asrt.Equal(uint64(10), queued.Stats().Written+queued.Stats().DroppedByQueue)
+
+	// A logger combining others sums their counters
+	combined := &Log{tees: []*Log{logger, queued}}
+	asrt.Equal(Stats{
+		Written:        1 + queued.Stats().Written,
+		DroppedByQueue: queued.Stats().DroppedByQueue,
+		WriteErrors:    2,
+	}, combined.Stats())
+}
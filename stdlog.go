@@ -0,0 +1,33 @@
+package log
+
+import (
+	stdlog "log"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// StdLogger returns a standard library *log.Logger writing through this logger at the given
+// level, for code using log.Printf or taking a *log.Logger (e.g. http.Server.ErrorLog).
+// The trailing newline added by the standard logger is trimmed, and the caller is the
+// code calling the standard logger. Invalid levels fall back to info.
+//
+// Example Usage:
+//
+//	server := &http.Server{ErrorLog: logger.StdLogger("error")}
+func (l *Log) StdLogger(level string) *stdlog.Logger {
+	zapLevel := zapcore.InfoLevel
+	if isValidLevel(level) {
+		_ = zapLevel.UnmarshalText([]byte(level))
+	}
+
+	// The standard logger doesn't go through the Log methods or wrappers skipped by ExtraCallerSkip
+	logger := l.log.WithOptions(zap.AddCallerSkip(-l.opts.callerSkip()))
+
+	std, err := zap.NewStdLogAt(logger, zapLevel)
+	if err != nil {
+		// Only possible for levels above fatal, which isValidLevel rejects
+		return zap.NewStdLog(logger)
+	}
+	return std
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestStdLogger(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix(""))
+
+	std := logger.StdLogger("info")
+	std.Printf("hi %d", 1)
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 1)
+	asrt.Equal(zapcore.InfoLevel, entries[0].Level)
+	asrt.Equal("hi 1", entries[0].Message) // No trailing newline
+
+	// The caller is the code calling the standard logger
+	asrt.Contains(entries[0].Caller.File, "stdlog_test.go")
+}
+
+func TestStdLogger_Levels(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.DebugLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix("STD_"))
+
+	logger.StdLogger("warn").Println("multi\nline")
+	logger.StdLogger("error").Print("failed")
+	logger.StdLogger("verbose").Print("invalid level") // Falls back to info
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 3)
+	asrt.Equal(zapcore.WarnLevel, entries[0].Level)
+	asrt.Equal("STD_multi\nline", entries[0].Message)
+	asrt.Equal(zapcore.ErrorLevel, entries[1].Level)
+	asrt.Equal("STD_failed", entries[1].Message)
+	asrt.Equal(zapcore.InfoLevel, entries[2].Level)
+}
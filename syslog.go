@@ -0,0 +1,90 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogCore is a zapcore.Core that sends every entry to syslog, mapping
+// log levels to syslog severities.
+type syslogCore struct {
+	zapcore.LevelEnabler
+
+	enc    zapcore.Encoder
+	prefix string // prepended to every encoded entry, like in the log files
+	writer *syslog.Writer
+}
+
+// newSyslogCore connects to the syslog daemon described by opts and returns a core
+// writing to it, along with the closer of the connection.
+func newSyslogCore(
+	opts SyslogOptions,
+	prefix string,
+	enc zapcore.Encoder,
+	enab zapcore.LevelEnabler,
+) (zapcore.Core, io.Closer, error) {
+	facility, ok := syslogFacilities[opts.Facility]
+	if !ok {
+		facility = syslogFacilities["user"]
+	}
+
+	// The severity is chosen per entry, only the facility matters here
+	writer, err := syslog.Dial(opts.Network, opts.Address, syslog.Priority(facility<<3), opts.Tag)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+
+	return &syslogCore{LevelEnabler: enab, enc: enc, prefix: prefix, writer: writer}, writer, nil
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for i := range fields {
+		fields[i].AddTo(enc)
+	}
+	return &syslogCore{LevelEnabler: c.LevelEnabler, enc: enc, prefix: c.prefix, writer: c.writer}
+}
+
+func (c *syslogCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(entry, fields)
+	if err != nil {
+		return fmt.Errorf("EncodeEntry error: %w", err)
+	}
+	defer buf.Free()
+
+	msg := c.prefix + strings.TrimSuffix(buf.String(), "\n")
+
+	switch entry.Level {
+	case zapcore.DebugLevel:
+		return c.writer.Debug(msg)
+	case zapcore.InfoLevel:
+		return c.writer.Info(msg)
+	case zapcore.WarnLevel:
+		return c.writer.Warning(msg)
+	case zapcore.ErrorLevel:
+		return c.writer.Err(msg)
+	case zapcore.DPanicLevel:
+		return c.writer.Crit(msg)
+	case zapcore.PanicLevel:
+		return c.writer.Alert(msg)
+	case zapcore.FatalLevel:
+		return c.writer.Emerg(msg)
+	default:
+		return c.writer.Info(msg)
+	}
+}
+
+func (c *syslogCore) Sync() error { return nil }
@@ -0,0 +1,15 @@
+//go:build windows || plan9
+
+package log
+
+import (
+	"errors"
+	"io"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// newSyslogCore always fails, syslog is not available on this platform.
+func newSyslogCore(SyslogOptions, string, zapcore.Encoder, zapcore.LevelEnabler) (zapcore.Core, io.Closer, error) {
+	return nil, nil, errors.New("syslog is not supported on this platform")
+}
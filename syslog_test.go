@@ -0,0 +1,112 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockSyslogServer starts a UDP server that collects received syslog messages.
+func newMockSyslogServer(t *testing.T) (addr string, messages <-chan string) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ch := make(chan string, 16)
+	go func() {
+		buf := make([]byte, 64*1024)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			ch <- string(buf[:n])
+		}
+	}()
+
+	return conn.LocalAddr().String(), ch
+}
+
+func receiveSyslog(t *testing.T, messages <-chan string) string {
+	t.Helper()
+
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for syslog message")
+		return ""
+	}
+}
+
+func TestSyslogOutput(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_syslog"
+	defer os.RemoveAll(testDir)
+
+	addr, messages := newMockSyslogServer(t)
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithPrefix("SYS_").
+		WithConsoleOutput(false).
+		WithSyslog(SyslogOptions{
+			Enabled:  true,
+			Network:  "udp",
+			Address:  addr,
+			Facility: "local0",
+			Tag:      "myapp",
+		}))
+	defer logger.Close()
+
+	logger.Infow("syslog info message", "key", "value")
+	msg := receiveSyslog(t, messages)
+	asrt.Contains(msg, "<134>") // local0 (16) * 8 + info (6)
+	asrt.Contains(msg, "myapp")
+	asrt.Contains(msg, ": SYS_") // the prefix starts the encoded line, like in the log files
+	asrt.Contains(msg, "syslog info message")
+	asrt.Contains(msg, "value")
+
+	logger.Error("syslog error message")
+	msg = receiveSyslog(t, messages)
+	asrt.Contains(msg, "<131>") // local0 (16) * 8 + err (3)
+	asrt.Contains(msg, "syslog error message")
+
+	// Syslog output is in addition to file output
+	content, err := os.ReadFile(filepath.Join(testDir, logger.generateFileName(logger.currDate, false)))
+	require.NoError(t, err)
+	asrt.Contains(string(content), "syslog info message")
+}
+
+func TestSyslogOutput_Only(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_syslog_only"
+	defer os.RemoveAll(testDir)
+
+	addr, messages := newMockSyslogServer(t)
+	logger := NewLog(NewOptions().
+		WithDirectory(testDir).
+		WithConsoleOutput(false).
+		WithSyslog(SyslogOptions{Enabled: true, Network: "udp", Address: addr, Only: true}))
+	defer logger.Close()
+
+	logger.Warn("syslog only message")
+	msg := receiveSyslog(t, messages)
+	asrt.Contains(msg, "<12>") // user (1) * 8 + warning (4)
+	asrt.Contains(msg, "syslog only message")
+
+	// No log files are written
+	asrt.NoDirExists(testDir)
+}
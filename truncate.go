@@ -0,0 +1,94 @@
+package log
+
+import (
+	"encoding/json"
+	"slices"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TruncatedMarker is appended to the field values truncated by Options.MaxFieldLength.
+const TruncatedMarker = "…(truncated)"
+
+// truncateCore is a zapcore.Core wrapper truncating the field values longer than maxLength
+// bytes, see Options.MaxFieldLength.
+type truncateCore struct {
+	zapcore.Core
+	maxLength int
+}
+
+// With adds structured context to the wrapped core, with the values truncated.
+func (c *truncateCore) With(fields []zapcore.Field) zapcore.Core {
+	return &truncateCore{Core: c.Core.With(c.truncate(fields)), maxLength: c.maxLength}
+}
+
+// Check registers this core if the wrapped core accepts the entry, so the fields are
+// truncated in Write.
+func (c *truncateCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Check(entry, nil) == nil {
+		return ce
+	}
+	return ce.AddCore(entry, c)
+}
+
+// Write writes the entry with the field values truncated.
+func (c *truncateCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.truncate(fields))
+}
+
+// truncate returns fields with the values longer than maxLength truncated. fields is only
+// copied if a value is truncated.
+func (c *truncateCore) truncate(fields []zapcore.Field) []zapcore.Field {
+	truncated := fields
+	for i, field := range fields {
+		field, ok := truncateField(field, c.maxLength)
+		if !ok {
+			continue
+		}
+		if &truncated[0] == &fields[0] {
+			truncated = slices.Clone(fields)
+		}
+		truncated[i] = field
+	}
+	return truncated
+}
+
+// truncateField truncates the value of field if it's longer than maxLength bytes. Strings
+// are cut, and collections longer than maxLength once serialized are replaced by their
+// truncated JSON. It reports whether the value was truncated.
+func truncateField(field zapcore.Field, maxLength int) (zapcore.Field, bool) {
+	switch field.Type {
+	case zapcore.StringType:
+		if len(field.String) <= maxLength {
+			return field, false
+		}
+		return zap.String(field.Key, truncateString(field.String, maxLength)), true
+	case zapcore.ByteStringType:
+		value, _ := field.Interface.([]byte)
+		if len(value) <= maxLength {
+			return field, false
+		}
+		return zap.String(field.Key, truncateString(string(value), maxLength)), true
+	case zapcore.ArrayMarshalerType, zapcore.ObjectMarshalerType, zapcore.ReflectType:
+		enc := zapcore.NewMapObjectEncoder()
+		field.AddTo(enc)
+		data, err := json.Marshal(enc.Fields[field.Key])
+		if err != nil || len(data) <= maxLength {
+			return field, false
+		}
+		return zap.String(field.Key, truncateString(string(data), maxLength)), true
+	default:
+		return field, false
+	}
+}
+
+// truncateString cuts s to at most maxLength bytes at a rune boundary, and appends
+// TruncatedMarker.
+func truncateString(s string, maxLength int) string {
+	for maxLength > 0 && !utf8.RuneStart(s[maxLength]) {
+		maxLength--
+	}
+	return s[:maxLength] + TruncatedMarker
+}
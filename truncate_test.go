@@ -0,0 +1,68 @@
+package log
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMaxFieldLength(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	core, recorded := observer.New(zapcore.InfoLevel)
+	logger := NewLogWithCore(core, NewOptions().WithPrefix("").WithMaxFieldLength(10))
+
+	ids := make([]int, 1000)
+	logger.Infow("oversized",
+		"body", strings.Repeat("x", 1<<20),
+		"ids", ids,
+		"short", "ok",
+		"count", 42,
+	)
+	logger.InfoF("typed", Any("tags", []string{"a", "b"}), String("name", "héllo wörld"))
+
+	entries := recorded.AllUntimed()
+	require.Len(t, entries, 2)
+
+	fields := entries[0].ContextMap()
+	asrt.Equal("xxxxxxxxxx"+TruncatedMarker, fields["body"])
+	asrt.Equal("[0,0,0,0,0"+TruncatedMarker, fields["ids"])
+	asrt.Equal("ok", fields["short"])
+	asrt.Equal(int64(42), fields["count"])
+
+	// Short collections are kept as is, strings are cut at a rune boundary
+	fields = entries[1].ContextMap()
+	asrt.Equal([]any{"a", "b"}, fields["tags"])
+	asrt.Equal("héllo wö"+TruncatedMarker, fields["name"])
+}
+
+func TestMaxFieldLength_FileOutput(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_max_field_length"
+	defer os.RemoveAll(testDir)
+
+	logger := NewBuilder().
+		Directory(testDir).
+		Prefix("").
+		Format(FormatJSON).
+		ConsoleOutput(false).
+		MaxFieldLength(16).
+		Fields(map[string]any{"payload": strings.Repeat("p", 100)}).
+		Build()
+
+	logger.Infow("to file", "config", map[string]any{"key": strings.Repeat("v", 100)})
+	require.NoError(t, logger.Sync())
+
+	entries := readJSONEntries(t, logger, testDir)
+	require.Len(t, entries, 1)
+	asrt.Equal(strings.Repeat("p", 16)+TruncatedMarker, entries[0]["payload"])
+	asrt.Equal(`{"key":"vvvvvvvv`+TruncatedMarker, entries[0]["config"])
+}
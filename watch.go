@@ -0,0 +1,157 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchConfigFile watches the configuration file at path and applies changes to the
+// running logger without recreating its file handles. This enables zero-downtime
+// verbosity changes from configuration.
+//
+// The level and sampling (enable_sampling, sample_initial, sample_thereafter and
+// sample_tick) are changed live, a sampling change resetting the sampling counters.
+// Changes to any other field, such as directory, filename or format, are reported with
+// a warning stating that a restart is required. Invalid configurations are reported
+// and ignored.
+//
+// Parameters:
+//   - path: Path to the configuration file (any format supported by LoadFromFile)
+//   - logger: The running logger to apply changes to
+//
+// Returns:
+//   - stop: Function that stops watching; safe to call multiple times
+//   - error: Error if the file can't be loaded or watched
+//
+// Example Usage:
+//
+//	stop, err := log.WatchConfigFile("config.yaml", logger)
+//	if err != nil {
+//	    log.Fatal("Failed to watch config:", err)
+//	}
+//	defer stop()
+func WatchConfigFile(path string, logger *Log) (stop func(), err error) {
+	if logger == nil {
+		return nil, errors.New("logger is nil")
+	}
+
+	current, err := LoadFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	// Watch the directory rather than the file, so that editors and config management
+	// tools replacing the file via rename are handled as well.
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to resolve config path %s: %w", path, err)
+	}
+	if err := watcher.Add(filepath.Dir(absPath)); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to watch config file %s: %w", path, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != absPath ||
+					!event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+					continue
+				}
+
+				updated, err := LoadFromFile(path)
+				if err != nil {
+					logger.Warnw("Failed to reload log configuration, keeping current settings",
+						"path", path, "error", err)
+					continue
+				}
+
+				applyConfigChanges(logger, current, updated, path)
+				current = updated
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Warnw("Log configuration watcher error", "path", path, "error", err)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			_ = watcher.Close()
+		})
+	}, nil
+}
+
+// applyConfigChanges applies the live-changeable fields from updated to the logger, the
+// level and sampling, and warns about the fields that require a restart.
+func applyConfigChanges(logger *Log, current, updated *Options, path string) {
+	if updated.Level != current.Level {
+		if err := logger.SetLevel(updated.Level); err != nil {
+			logger.Warnw("Failed to apply log level from configuration", "path", path, "error", err)
+		} else {
+			logger.Infow("Log level changed from configuration",
+				"path", path, "from", current.Level, "to", updated.Level)
+		}
+	}
+
+	if updated.EnableSampling != current.EnableSampling ||
+		updated.SampleInitial != current.SampleInitial ||
+		updated.SampleThereafter != current.SampleThereafter ||
+		updated.sampleTick() != current.sampleTick() {
+		logger.setSampling(updated.EnableSampling, updated.SampleInitial, updated.SampleThereafter,
+			updated.sampleTick())
+		logger.Infow("Log sampling changed from configuration",
+			"path", path, "enabled", updated.EnableSampling, "initial", updated.SampleInitial,
+			"thereafter", updated.SampleThereafter, "tick", updated.sampleTick())
+	}
+
+	// Everything else is baked into the logger at construction time
+	if changed := changedOptionFields(current, updated,
+		"Level", "EnableSampling", "SampleInitial", "SampleThereafter", "SampleTick"); len(changed) > 0 {
+		logger.Warnw("Log configuration changed, restart required to apply these settings",
+			"path", path, "fields", changed)
+	}
+}
+
+// changedOptionFields returns the names of the Options fields that differ between a and b,
+// ignoring the given field names.
+func changedOptionFields(a, b *Options, ignore ...string) []string {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+
+	var changed []string
+	for i := range va.NumField() {
+		name := va.Type().Field(i).Name
+		if slices.Contains(ignore, name) {
+			continue
+		}
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
@@ -0,0 +1,132 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchConfigFile(t *testing.T) {
+	t.Parallel()
+
+	testDir := "./logs/test_logs_watch"
+	defer os.RemoveAll(testDir)
+	require.NoError(t, os.MkdirAll(testDir, 0o755))
+
+	configPath := filepath.Join(testDir, "watch.yaml")
+	writeConfig := func(level, filename string) {
+		content := "level: " + level + "\n" +
+			"directory: " + testDir + "\n" +
+			"filename: " + filename + "\n" +
+			"console_output: false\n"
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+	}
+	writeConfig("info", "watch")
+
+	logger, err := FromConfigFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "info", logger.Level())
+
+	stop, err := WatchConfigFile(configPath, logger)
+	require.NoError(t, err)
+	defer stop()
+
+	// Level changes are applied live
+	writeConfig("debug", "watch")
+	assert.Eventually(t, func() bool { return logger.Level() == "debug" },
+		3*time.Second, 20*time.Millisecond, "level should be reloaded from the config file")
+
+	// Fields that can't change live leave the level untouched and warn instead
+	writeConfig("debug", "renamed")
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "debug", logger.Level())
+
+	// Stop is idempotent and further changes are ignored
+	stop()
+	stop()
+	writeConfig("error", "watch")
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "debug", logger.Level())
+}
+
+func TestWatchConfigFile_Sampling(t *testing.T) {
+	t.Parallel()
+	asrt := assert.New(t)
+
+	testDir := "./logs/test_logs_watch_sampling"
+	defer os.RemoveAll(testDir)
+	require.NoError(t, os.MkdirAll(testDir, 0o755))
+
+	configPath := filepath.Join(testDir, "watch.yaml")
+	writeConfig := func(sampling string) {
+		content := "directory: " + testDir + "\n" +
+			"console_output: false\n" +
+			"isolated: true\n" + sampling
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+	}
+	writeConfig("enable_sampling: false\n")
+
+	logger, err := FromConfigFile(configPath)
+	require.NoError(t, err)
+	defer logger.Close()
+
+	stop, err := WatchConfigFile(configPath, logger)
+	require.NoError(t, err)
+	defer stop()
+
+	logBurst := func() {
+		for range 5 {
+			logger.Info("burst message")
+		}
+	}
+
+	// Enabling sampling is applied live: only the first entry of the burst is logged
+	writeConfig("enable_sampling: true\nsample_initial: 1\nsample_thereafter: 100\n")
+	asrt.Eventually(func() bool { return logger.sampling.Load() != nil },
+		3*time.Second, 20*time.Millisecond, "sampling should be reloaded from the config file")
+	logBurst()
+	asrt.Equal(uint64(4), logger.Stats().DroppedBySampling)
+
+	// Changed settings are applied with fresh counters
+	writeConfig("enable_sampling: true\nsample_initial: 3\nsample_thereafter: 100\n")
+	asrt.Eventually(func() bool {
+		settings := logger.sampling.Load()
+		return settings != nil && settings.first == 3
+	}, 3*time.Second, 20*time.Millisecond, "sampling should be reloaded from the config file")
+	logBurst()
+	asrt.Equal(uint64(6), logger.Stats().DroppedBySampling)
+
+	// Disabling sampling is applied live as well
+	writeConfig("enable_sampling: false\n")
+	asrt.Eventually(func() bool { return logger.sampling.Load() == nil },
+		3*time.Second, 20*time.Millisecond, "sampling should be reloaded from the config file")
+	logBurst()
+	asrt.Equal(uint64(6), logger.Stats().DroppedBySampling)
+}
+
+func TestWatchConfigFile_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := WatchConfigFile("nonexistent.yaml", NewNop())
+	assert.Error(t, err)
+
+	_, err = WatchConfigFile("nonexistent.yaml", nil)
+	assert.Error(t, err)
+}
+
+func Test_changedOptionFields(t *testing.T) {
+	t.Parallel()
+
+	a := NewOptions()
+	b := NewOptions()
+	assert.Empty(t, changedOptionFields(a, b))
+
+	b.Level = "debug"
+	b.Directory = "/tmp/other"
+	assert.Equal(t, []string{"Directory", "Level"}, changedOptionFields(a, b))
+	assert.Equal(t, []string{"Directory"}, changedOptionFields(a, b, "Level"))
+}